@@ -1,7 +1,7 @@
 /*
 This package implements a caching system for storing and managing cloud-based metric families.
 It provides a thread-safe, singleton CacheBackend which manages CloudCache objects.
-Each CloudCache can hold multiple MetricFamilyCaches, indexed by the metric family name to avoid duplication.
+Each CloudCache can hold multiple MetricFamilyCaches, indexed by the service and metric family name to avoid duplication.
 The system includes functionality to:
 - Initialize and retrieve a singleton CacheBackend
 - Add or update MetricFamily data in a CloudCache
@@ -49,11 +49,15 @@ type CacheBackend interface {
 	// Flush expired caches based on cloud's update time.
 	// Cache will be deleted if their update time is older than the ttl.
 	FlushExpiredCloudCaches(ttl time.Duration)
+	// Flush expired caches based on cloud's update time.
+	// Cache will be deleted if their update time is older than the ttl of the cloud.
+	FlushExpiredCloudCachesFunc(ttl func(cloud string) time.Duration)
 }
 
 // MetricFamily Cache Data
 type MetricFamilyCache struct {
 	Service string
+	Region  string
 	MF      *dto.MetricFamily
 }
 
@@ -61,7 +65,7 @@ type MetricFamilyCache struct {
 type CloudCache struct {
 	// Latest update time.
 	Time time.Time
-	// The key of MetricFamilyCaches is metric family name
+	// The key of MetricFamilyCaches is region, service and metric family name
 	// to avoid duplicate MFs in the map.
 	MetricFamilyCaches map[string]*MetricFamilyCache
 }
@@ -120,11 +124,17 @@ func (c *InMemoryCache) SetCloudCache(cloud string, data CloudCache) {
 // Flush expired caches based on cloud's update time.
 // Cache will be deleted if their update time is older than the ttl.
 func (c *InMemoryCache) FlushExpiredCloudCaches(ttl time.Duration) {
+	c.FlushExpiredCloudCachesFunc(func(string) time.Duration { return ttl })
+}
+
+// Flush expired caches based on cloud's update time.
+// Cache will be deleted if their update time is older than the ttl of the cloud.
+func (c *InMemoryCache) FlushExpiredCloudCachesFunc(ttl func(cloud string) time.Duration) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	for key, cloudCache := range c.CloudCaches {
-		expirationTime := cloudCache.Time.Add(ttl)
+		expirationTime := cloudCache.Time.Add(ttl(key))
 		if time.Now().After(expirationTime) {
 			delete(c.CloudCaches, key)
 		}
@@ -140,7 +150,7 @@ func NewCloudCache() CloudCache {
 	return cloud
 }
 
-// SetMetricFamilyCache updates the MetricFamilyCaches by associating a key, which is the metric family name.
+// SetMetricFamilyCache updates the MetricFamilyCaches by associating a key, which is the region, service and metric family name.
 func (c *CloudCache) SetMetricFamilyCache(mfName string, data MetricFamilyCache) {
 	c.MetricFamilyCaches[mfName] = &data
 }
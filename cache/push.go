@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/openstack-exporter/openstack-exporter/config"
+	"github.com/openstack-exporter/openstack-exporter/exporters"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"google.golang.org/protobuf/proto"
+)
+
+// PushConfig configures the push of the cached metrics to a Pushgateway.
+type PushConfig struct {
+	// URL is the URL of the Pushgateway, i.e: http://pushgateway:9091.
+	URL string
+	Job string
+	// Username and Password set the basic authentication of the pushes, when Username is set.
+	Username string
+	Password string
+	// Client sends the pushes, with the TLS configuration of the Pushgateway. http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// PushCache pushes the cached metric families of the clouds to the Pushgateway, in one group per
+// cloud and enabled service, whose grouping key is the cloud and the exporter name of the service.
+// Each push replaces the metrics of its group. A failed push is logged, and the other groups are
+// still pushed, the last error is returned.
+func PushCache(ctx context.Context, pushConfig PushConfig, multiCloud bool, cloud string, cloudOptions func(cloud string) config.Options, logger *slog.Logger) error {
+	clouds, err := CollectedClouds(multiCloud, cloud)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	cacheBackend := GetCache()
+	for _, cloud := range clouds {
+		cloudCache, exists := cacheBackend.GetCloudCache(cloud)
+		if !exists {
+			logger.Debug("Cache not exists, nothing to push", "cloud", cloud)
+			continue
+		}
+		for _, service := range cloudOptions(cloud).EnabledServices {
+			mfs := mergeMetricFamilies(cloudCache, []string{service})
+			if len(mfs) == 0 {
+				continue
+			}
+			grouping := map[string]string{"cloud": cloud, "service": exporters.ServiceName(service)}
+			if err := pushMetricFamilies(ctx, pushConfig, grouping, mfs); err != nil {
+				logger.Error("Pushing the metrics failed", "cloud", cloud, "service", service, "error", err)
+				lastErr = err
+				continue
+			}
+			logger.Debug("Pushed the metrics", "cloud", cloud, "service", service)
+		}
+	}
+	return lastErr
+}
+
+// pushMetricFamilies replaces the metrics of a group of the Pushgateway with mfs. The request is
+// built here rather than with push.Pusher, which adds the grouping labels to the URL in the random
+// order of a map: the labels are added in the order of their names.
+func pushMetricFamilies(ctx context.Context, pushConfig PushConfig, grouping map[string]string, mfs []*dto.MetricFamily) error {
+	mfs = withoutGroupingLabels(mfs, grouping)
+	format := expfmt.NewFormat(expfmt.TypeProtoDelim)
+	var buf bytes.Buffer
+	encoder := expfmt.NewEncoder(&buf, format)
+	for _, mf := range mfs {
+		if err := encoder.Encode(mf); err != nil {
+			return fmt.Errorf("failed to encode metric family %s: %w", mf.GetName(), err)
+		}
+	}
+
+	groupURL := strings.TrimSuffix(pushConfig.URL, "/") + "/metrics/" + groupingComponent("job", pushConfig.Job)
+	for _, name := range slices.Sorted(maps.Keys(grouping)) {
+		groupURL += "/" + groupingComponent(name, grouping[name])
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, groupURL, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", string(format))
+	if pushConfig.Username != "" {
+		req.SetBasicAuth(pushConfig.Username, pushConfig.Password)
+	}
+	client := pushConfig.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Depending on its version and configuration, the Pushgateway answers 200 or 202.
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code %d while pushing to %s: %s", resp.StatusCode, groupURL, body)
+	}
+	return nil
+}
+
+// groupingComponent returns the path of a label of the grouping key in the URL of a group, encoded
+// as done by push.Pusher: in base64 when the value is empty or holds a slash.
+func groupingComponent(name, value string) string {
+	if value == "" {
+		return name + "@base64/="
+	}
+	if strings.Contains(value, "/") {
+		return name + "@base64/" + base64.RawURLEncoding.EncodeToString([]byte(value))
+	}
+	return name + "/" + url.QueryEscape(value)
+}
+
+// withoutGroupingLabels returns copies of the metric families without the labels of the grouping
+// key, which the Pushgateway adds to the metrics of the group. A label with the value of the grouping
+// key is dropped, a label with another value is renamed exported_<label>, as done by Prometheus for
+// the labels conflicting with the target labels (i.e: the service label of agent_state).
+func withoutGroupingLabels(mfs []*dto.MetricFamily, grouping map[string]string) []*dto.MetricFamily {
+	pushed := make([]*dto.MetricFamily, 0, len(mfs))
+	for _, mf := range mfs {
+		mf = proto.Clone(mf).(*dto.MetricFamily)
+		for _, m := range mf.Metric {
+			labels := m.Label[:0]
+			for _, label := range m.Label {
+				value, ok := grouping[label.GetName()]
+				switch {
+				case !ok:
+				case value == label.GetValue():
+					continue
+				default:
+					label.Name = proto.String("exported_" + label.GetName())
+				}
+				labels = append(labels, label)
+			}
+			// The labels of the metrics are sorted by name, as gathered by the registries.
+			slices.SortFunc(labels, func(a, b *dto.LabelPair) int { return strings.Compare(a.GetName(), b.GetName()) })
+			m.Label = labels
+		}
+		pushed = append(pushed, mf)
+	}
+	return pushed
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/openstack-exporter/openstack-exporter/config"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPushCache(t *testing.T) {
+	newSingleCache()
+	defer newSingleCache()
+
+	var mu sync.Mutex
+	pushes := map[string]string{}
+	pushgateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ := r.BasicAuth()
+		assert.Equal(t, http.MethodPut, r.Method, "a push should replace the metrics of its group")
+		assert.Equal(t, "admin:secret", username+":"+password)
+		var body strings.Builder
+		decoder := expfmt.NewDecoder(r.Body, expfmt.ResponseFormat(r.Header))
+		for {
+			mf := &dto.MetricFamily{}
+			if err := decoder.Decode(mf); err != nil {
+				assert.ErrorIs(t, err, io.EOF)
+				break
+			}
+			_, err := expfmt.MetricFamilyToText(&body, mf)
+			assert.NoError(t, err)
+		}
+		mu.Lock()
+		pushes[r.URL.Path] = body.String()
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer pushgateway.Close()
+
+	gauge := func(name string, value float64, labels ...string) *dto.MetricFamily {
+		m := &dto.Metric{Gauge: &dto.Gauge{Value: proto.Float64(value)}}
+		for i := 0; i < len(labels); i += 2 {
+			m.Label = append(m.Label, &dto.LabelPair{Name: proto.String(labels[i]), Value: proto.String(labels[i+1])})
+		}
+		return &dto.MetricFamily{Name: proto.String(name), Help: proto.String(name), Type: dto.MetricType_GAUGE.Enum(), Metric: []*dto.Metric{m}}
+	}
+	cloudCache := NewCloudCache()
+	cloudCache.SetMetricFamilyCache("/compute/openstack_nova_agent_state", MetricFamilyCache{Service: "compute", MF: gauge("openstack_nova_agent_state", 1, "hostname", "host1", "service", "nova-compute")})
+	cloudCache.SetMetricFamilyCache("/compute/openstack_collector_success", MetricFamilyCache{Service: "compute", MF: gauge("openstack_collector_success", 1, "metric", "agent_state", "service", "nova")})
+	cloudCache.SetMetricFamilyCache("/network/openstack_neutron_up", MetricFamilyCache{Service: "network", MF: gauge("openstack_neutron_up", 1)})
+	cloudCache.SetMetricFamilyCache("/volume/openstack_cinder_up", MetricFamilyCache{Service: "volume", MF: gauge("openstack_cinder_up", 1)})
+	GetCache().SetCloudCache("test.cloud", cloudCache)
+
+	pushConfig := PushConfig{URL: pushgateway.URL, Job: "openstack_exporter", Username: "admin", Password: "secret"}
+	cloudOptions := func(string) config.Options {
+		return config.Options{EnabledServices: []string{"network", "compute", "image"}}
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	require.NoError(t, PushCache(context.Background(), pushConfig, false, "test.cloud", cloudOptions, logger))
+
+	assert.Len(t, pushes, 2, "the enabled services having cached metrics should be pushed")
+	assert.Contains(t, pushes["/metrics/job/openstack_exporter/cloud/test.cloud/service/neutron"], "openstack_neutron_up 1")
+	nova := pushes["/metrics/job/openstack_exporter/cloud/test.cloud/service/nova"]
+	assert.Contains(t, nova, `openstack_collector_success{metric="agent_state"} 1`, "the label of the grouping key should be dropped")
+	assert.Contains(t, nova, `openstack_nova_agent_state{exported_service="nova-compute",hostname="host1"} 1`, "the label conflicting with the grouping key should be renamed")
+
+	pushgateway.Close()
+	assert.Error(t, PushCache(context.Background(), pushConfig, false, "test.cloud", cloudOptions, logger))
+}
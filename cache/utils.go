@@ -4,6 +4,8 @@ package cache
 
 import (
 	"bytes"
+	"context"
+	"maps"
 	"net/http"
 	"slices"
 	"time"
@@ -11,42 +13,84 @@ import (
 	"log/slog"
 
 	"github.com/gophercloud/utils/openstack/clientconfig"
+	"github.com/openstack-exporter/openstack-exporter/config"
 	"github.com/openstack-exporter/openstack-exporter/exporters"
-	"github.com/openstack-exporter/openstack-exporter/utils"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/expfmt"
 )
 
 // CollectCache collects the MetricsFamily for required clouds and services and stores in the cache.
+// The services and the collection options of each cloud are given by cloudOptions, the other options
+// of the exporters by defaults. A cloud is skipped when its cache is still fresh at the next collection,
+// which happens after interval.
 func CollectCache(
-	enableExporterFunc func(
-		string, string, string, []string, string, bool, bool, bool, bool, string, string, *utils.LabelMappingFlag, func() (string, error), *slog.Logger,
-	) (*exporters.OpenStackExporter, error),
+	ctx context.Context,
+	enableExporterFunc func(context.Context, exporters.ExporterOptions, *slog.Logger) (*exporters.OpenStackExporter, error),
 	multiCloud bool,
-	services map[string]*bool, prefix,
 	cloud string,
-	disabledMetrics []string,
-	endpointType string,
-	collectTime bool,
-	disableSlowMetrics bool,
-	disableDeprecatedMetrics bool,
-	disableCinderAgentUUID bool,
-	domainID string,
-	tenantID string,
-	novaMetadataMapping *utils.LabelMappingFlag,
-	uuidGenFunc func() (string, error),
+	cloudOptions func(cloud string) config.Options,
+	interval time.Duration,
+	defaults exporters.ExporterOptions,
 	logger *slog.Logger,
 ) error {
 	logger.Info("Run collect cache job")
 	cacheBackend := GetCache()
 
+	clouds, err := CollectedClouds(multiCloud, cloud)
+	if err != nil {
+		return err
+	}
+	exporters.RefreshClouds(logger)
+
+	for _, cloud := range clouds {
+		// Stop collecting once the caller gives up, so a partial collection doesn't replace a cloud's cache.
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		options := cloudOptions(cloud)
+		if cached, exists := cacheBackend.GetCloudCache(cloud); exists && time.Since(cached.Time)+interval <= options.CacheTTL/2 {
+			logger.Debug("Cache data is still fresh", "cloud", cloud)
+			continue
+		}
+
+		logger.Info("Start update cache data", "cloud", cloud)
+		// Update cloud's cache once finish all exporters' collection job. so we won't mix the old
+		// and new metrics in the cache and confuse users.
+		cloudCache := NewCloudCache()
+		// The exporters of a cloud share the resources listed during the cycle.
+		cloudCtx := exporters.WithSnapshot(ctx)
+
+		regions, err := exporters.CollectedRegions(cloudCtx, cloud, options.MultiRegion, options.RegionLabel, options.EndpointType, logger)
+		if err != nil {
+			logger.Error("Listing the regions failed", "cloud", cloud, "error", err)
+			continue
+		}
+
+		for _, region := range regions {
+			collectRegionCache(cloudCtx, &cloudCache, enableExporterFunc, cloud, region, options, defaults, logger)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		cacheBackend.SetCloudCache(
+			cloud, cloudCache,
+		)
+	}
+
+	return nil
+}
+
+// CollectedClouds returns the clouds collected by the cache: every cloud of clouds.yaml with multiCloud,
+// cloud otherwise.
+func CollectedClouds(multiCloud bool, cloud string) ([]string, error) {
 	clouds := []string{}
 
 	if multiCloud {
 		cloudsConfig, err := clientconfig.LoadCloudsYAML()
 		if err != nil {
-			return err
+			return nil, err
 		}
 		for cloud := range cloudsConfig {
 			clouds = append(clouds, cloud)
@@ -55,54 +99,51 @@ func CollectCache(
 	if cloud != "" && !multiCloud {
 		clouds = append(clouds, cloud)
 	}
+	return clouds, nil
+}
 
-	enabledServices := []string{}
-	for service, disabled := range services {
-		if !*disabled {
-			enabledServices = append(enabledServices, service)
+// collectRegionCache collects the MetricsFamily of the services of a cloud region into cloudCache.
+func collectRegionCache(
+	ctx context.Context,
+	cloudCache *CloudCache,
+	enableExporterFunc func(context.Context, exporters.ExporterOptions, *slog.Logger) (*exporters.OpenStackExporter, error),
+	cloud string,
+	region string,
+	options config.Options,
+	defaults exporters.ExporterOptions,
+	logger *slog.Logger,
+) {
+	for _, service := range options.EnabledServices {
+		logger.Info("Start collect cache data", "cloud", cloud, "region", region, "service", service)
+		exp, err := enableExporterFunc(ctx, options.ExporterOptions(defaults, service, cloud, region), logger)
+		if err != nil {
+			// Log error and continue with enabling other exporters
+			logger.Error("enabling exporter for service failed", "cloud", cloud, "region", region, "service", service, "error", err)
+			continue
 		}
-	}
+		registry := prometheus.NewPedanticRegistry()
+		registry.MustRegister(exporters.WithContext(ctx, *exp))
 
-	for _, cloud := range clouds {
-		logger.Info("Start update cache data", "cloud", cloud)
-		// Update cloud's cache once finish all exporters' collection job. so we won't mix the old
-		// and new metrics in the cache and confuse users.
-		cloudCache := NewCloudCache()
-
-		for _, service := range enabledServices {
-			logger.Info("Start collect cache data", "cloud", cloud, "service", service)
-			exp, err := enableExporterFunc(service, prefix, cloud, disabledMetrics, endpointType, collectTime, disableSlowMetrics, disableDeprecatedMetrics, disableCinderAgentUUID, domainID, tenantID, novaMetadataMapping, nil, logger)
-			if err != nil {
-				// Log error and continue with enabling other exporters
-				logger.Error("enabling exporter for service failed", "cloud", cloud, "service", service, "error", err)
-				continue
-			}
-			registry := prometheus.NewPedanticRegistry()
-			registry.MustRegister(*exp)
-
-			metricFamilies, err := registry.Gather()
-			if err != nil {
-				logger.Error("Create gather failed", "cloud", cloud, "service", service, "error", err)
-				continue
-			}
-			for _, mf := range metricFamilies {
-				cloudCache.SetMetricFamilyCache(
-					*mf.Name,
-					MetricFamilyCache{
-						Service: service,
-						MF:      mf,
-					},
-				)
-				logger.Debug("Update cache data", "cloud", cloud, "service", service, "MetricsFamily", mf.Name)
-			}
-			logger.Info("Finish update cache data", "cloud", cloud, "service", service)
+		metricFamilies, err := exporters.WithUnits(registry).Gather()
+		if err != nil {
+			logger.Error("Create gather failed", "cloud", cloud, "region", region, "service", service, "error", err)
+			continue
 		}
-		cacheBackend.SetCloudCache(
-			cloud, cloudCache,
-		)
+		for _, mf := range metricFamilies {
+			// Families like <prefix>_collector_success are emitted by every service
+			// of every region, so the region and the service are part of the key.
+			cloudCache.SetMetricFamilyCache(
+				region+"/"+service+"/"+*mf.Name,
+				MetricFamilyCache{
+					Service: service,
+					Region:  region,
+					MF:      mf,
+				},
+			)
+			logger.Debug("Update cache data", "cloud", cloud, "region", region, "service", service, "MetricsFamily", mf.Name)
+		}
+		logger.Info("Finish update cache data", "cloud", cloud, "region", region, "service", service)
 	}
-
-	return nil
 }
 
 // BufferFromCache reads cloud's MetricsFamily data from cache and writes into a buffer.
@@ -115,15 +156,54 @@ func BufferFromCache(cloud string, services []string, logger *slog.Logger) (byte
 		return buf, nil
 	}
 
+	for _, mf := range mergeMetricFamilies(cloudCache, services) {
+		if _, err := expfmt.MetricFamilyToText(&buf, mf); err != nil {
+			return buf, err
+		}
+	}
+	return buf, nil
+}
+
+// Gatherer returns a prometheus.Gatherer of the cached MetricsFamily of the services of cloud, to be
+// served by exporters.HandlerFor.
+func Gatherer(cloud string, services []string) prometheus.Gatherer {
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		cloudCache, exists := GetCache().GetCloudCache(cloud)
+		if !exists {
+			return nil, nil
+		}
+		return mergeMetricFamilies(cloudCache, services), nil
+	})
+}
+
+// mergeMetricFamilies returns the cached metric families of the services, sorted by name.
+// The families shared by several services or regions are merged, the text format doesn't
+// allow a metric family to be written twice.
+func mergeMetricFamilies(cloudCache CloudCache, services []string) []*dto.MetricFamily {
+	merged := make(map[string]*dto.MetricFamily)
 	for _, mfCache := range cloudCache.MetricFamilyCaches {
 		if !slices.Contains(services, mfCache.Service) {
 			continue
 		}
-		if _, err := expfmt.MetricFamilyToText(&buf, mfCache.MF); err != nil {
-			return buf, err
+		name := mfCache.MF.GetName()
+		if mf, ok := merged[name]; ok {
+			mf.Metric = append(mf.Metric, mfCache.MF.Metric...)
+			continue
+		}
+		merged[name] = &dto.MetricFamily{
+			Name:   mfCache.MF.Name,
+			Help:   mfCache.MF.Help,
+			Type:   mfCache.MF.Type,
+			Unit:   mfCache.MF.Unit,
+			Metric: slices.Clone(mfCache.MF.Metric),
 		}
 	}
-	return buf, nil
+
+	mfs := make([]*dto.MetricFamily, 0, len(merged))
+	for _, name := range slices.Sorted(maps.Keys(merged)) {
+		mfs = append(mfs, merged[name])
+	}
+	return mfs
 }
 
 // FlushExpiredCloudCaches flush expired caches based on cloud's update time
@@ -132,6 +212,12 @@ func FlushExpiredCloudCaches(ttl time.Duration) {
 	cacheBackend.FlushExpiredCloudCaches(ttl)
 }
 
+// FlushExpiredCloudCachesFunc flush expired caches based on cloud's update time and the ttl of each cloud.
+func FlushExpiredCloudCachesFunc(ttl func(cloud string) time.Duration) {
+	cacheBackend := GetCache()
+	cacheBackend.FlushExpiredCloudCachesFunc(ttl)
+}
+
 // WriteCacheToResponse read cache and write to the connection as part of an HTTP reply.
 func WriteCacheToResponse(w http.ResponseWriter, r *http.Request, cloud string, enabledServices []string, logger *slog.Logger) error {
 	buf, err := BufferFromCache(cloud, enabledServices, logger)
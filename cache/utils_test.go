@@ -2,6 +2,7 @@ package cache
 
 import (
 	"bytes"
+	"context"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
@@ -10,30 +11,17 @@ import (
 	"testing"
 	"time"
 
+	"github.com/openstack-exporter/openstack-exporter/config"
 	"github.com/openstack-exporter/openstack-exporter/exporters"
 	"github.com/openstack-exporter/openstack-exporter/utils"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/prometheus/common/expfmt"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 )
 
-func mockEnableExporter(
-	service,
-	prefix,
-	cloud string,
-	disabledMetrics []string,
-	endpointType string,
-	collectTime bool,
-	disableSlowMetrics bool,
-	disableDeprecatedMetrics bool,
-	disableCinderAgentUUID bool,
-	domainID string,
-	tenantID string,
-	novaMetadataMapping *utils.LabelMappingFlag,
-	uuidGenFunc func() (string, error),
-	logger *slog.Logger,
-) (*exporters.OpenStackExporter, error) {
+func mockEnableExporter(ctx context.Context, options exporters.ExporterOptions, logger *slog.Logger) (*exporters.OpenStackExporter, error) {
 	var exporter exporters.OpenStackExporter = &mockOpenStackExporter{
 		cnt: prometheus.NewCounter(prometheus.CounterOpts{Name: "c1", Help: "Help c1"}),
 		gge: prometheus.NewGauge(prometheus.GaugeOpts{Name: "g1", Help: "Help g1"}),
@@ -56,6 +44,10 @@ func (m *mockOpenStackExporter) Collect(ch chan<- prometheus.Metric) {
 	ch <- m.gge
 }
 
+func (m *mockOpenStackExporter) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
+	m.Collect(ch)
+}
+
 func (m *mockOpenStackExporter) GetName() string {
 	return "MockOpenStackExporter"
 }
@@ -72,40 +64,32 @@ func TestCollectCache(t *testing.T) {
 	defer newSingleCache()
 
 	multiCloud := false
-	services := make(map[string]*bool)
-	serviceADisable := false
-	serviceBDisable := true
-	services["service-a"] = &serviceADisable
-	services["service-b"] = &serviceBDisable
-	prefix := "testPrefix"
 	cloud := "testCloud"
-	disabledMetrics := []string{}
-	endpointType := "public"
-	collectTime := true
-	disableSlowMetrics := false
-	disableDeprecatedMetrics := true
-	disableCinderAgentUUID := false
-	domainID := ""
-	tenantID := ""
-	novaMetadataMapping := new(utils.LabelMappingFlag)
+	cloudOptions := func(string) config.Options {
+		return config.Options{
+			EnabledServices: []string{"service-a"},
+			DisabledMetrics: []string{},
+			EndpointType:    "public",
+			LabelMappings:   new(utils.ResourceLabelMappingFlag),
+			CacheTTL:        time.Minute,
+		}
+	}
+	defaults := exporters.ExporterOptions{
+		Prefix:                   "testPrefix",
+		CollectTime:              true,
+		DisableDeprecatedMetrics: true,
+		CollectConcurrency:       4,
+	}
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
 
 	if err := CollectCache(
+		context.Background(),
 		mockEnableExporter,
 		multiCloud,
-		services,
-		prefix,
 		cloud,
-		disabledMetrics,
-		endpointType,
-		collectTime,
-		disableSlowMetrics,
-		disableDeprecatedMetrics,
-		disableCinderAgentUUID,
-		domainID,
-		tenantID,
-		novaMetadataMapping,
-		nil,
+		cloudOptions,
+		30*time.Second,
+		defaults,
 		logger,
 	); err != nil {
 		t.Errorf("Collect cache failed")
@@ -129,6 +113,34 @@ func TestCollectCache(t *testing.T) {
 	}
 }
 
+func TestCollectCacheSkipsFreshClouds(t *testing.T) {
+	newSingleCache()
+	defer newSingleCache()
+
+	cloud := "testCloud"
+	calls := 0
+	enableExporter := func(ctx context.Context, options exporters.ExporterOptions, logger *slog.Logger) (*exporters.OpenStackExporter, error) {
+		calls++
+		return mockEnableExporter(ctx, options, logger)
+	}
+	collect := func(ttl time.Duration) {
+		cloudOptions := func(string) config.Options {
+			return config.Options{EnabledServices: []string{"service-a"}, CacheTTL: ttl}
+		}
+		if err := CollectCache(context.Background(), enableExporter, false, cloud, cloudOptions, 30*time.Second, exporters.ExporterOptions{Prefix: "testPrefix", CollectConcurrency: 4},
+			slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))); err != nil {
+			t.Errorf("Collect cache failed")
+		}
+	}
+
+	collect(10 * time.Minute)
+	collect(10 * time.Minute)
+	assert.Equal(t, 1, calls, "a fresh cloud cache should not be collected again")
+
+	collect(time.Minute)
+	assert.Equal(t, 2, calls, "a cloud cache expiring before the next collection should be collected again")
+}
+
 func TestBufferFromCache(t *testing.T) {
 	cache := GetCache()
 	defer newSingleCache()
@@ -171,6 +183,41 @@ func TestBufferFromCache(t *testing.T) {
 
 }
 
+func TestBufferFromCacheSharedMetricFamily(t *testing.T) {
+	cache := GetCache()
+	defer newSingleCache()
+	cloudName := "testCloud"
+
+	cloudCache := NewCloudCache()
+	for _, service := range []string{"service-a", "service-b"} {
+		registry := prometheus.NewPedanticRegistry()
+		registry.MustRegister(prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "shared",
+			Help:        "Help shared",
+			ConstLabels: prometheus.Labels{"service": service},
+		}))
+		mfs, _ := registry.Gather()
+		for _, mf := range mfs {
+			cloudCache.SetMetricFamilyCache(
+				service+"/"+*mf.Name, MetricFamilyCache{MF: mf, Service: service},
+			)
+		}
+	}
+	cache.SetCloudCache(cloudName, cloudCache)
+
+	buf, err := BufferFromCache(cloudName, []string{"service-a", "service-b"}, slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{})))
+	if err != nil {
+		t.Error(err)
+	}
+
+	parser := expfmt.NewTextParser(model.UTF8Validation)
+	metricFamilies, err := parser.TextToMetricFamilies(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, metricFamilies["shared"].Metric, 2, "The metrics of both services should be merged")
+}
+
 func TestWriteCacheToResponse(t *testing.T) {
 	cache := GetCache()
 	defer newSingleCache()
@@ -227,6 +274,40 @@ func TestWriteCacheToResponse(t *testing.T) {
 }
 
 // TestFlushExpiredCloudCaches tests flushing of expired cloud caches.
+func TestGathererStateSet(t *testing.T) {
+	cache := GetCache()
+	defer newSingleCache()
+	cloudName := "testCloud"
+	serviceName := "testService"
+
+	status := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "server_status", Help: "Server status"}, []string{"id", "server_status"})
+	status.WithLabelValues("a", "ACTIVE").Set(1)
+	status.WithLabelValues("a", "ERROR").Set(0)
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(status)
+	mfs, err := registry.Gather()
+	assert.NoError(t, err)
+
+	cloudCache := NewCloudCache()
+	for _, mf := range mfs {
+		cloudCache.SetMetricFamilyCache(*mf.Name, MetricFamilyCache{MF: mf, Service: serviceName})
+	}
+	cache.SetCloudCache(cloudName, cloudCache)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	rr := httptest.NewRecorder()
+	exporters.HandlerFor(Gatherer(cloudName, []string{serviceName}), promhttp.HandlerOpts{}, true).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "# TYPE server_status stateset\n", "the cached status metrics should have the StateSet type")
+	assert.Contains(t, rr.Body.String(), `server_status{id="a",server_status="ACTIVE"} 1`)
+
+	mfs, err = Gatherer("otherCloud", []string{serviceName}).Gather()
+	assert.NoError(t, err)
+	assert.Empty(t, mfs, "a cloud without cache should have no metrics")
+}
+
 func TestFlushExpiredCloudCaches(t *testing.T) {
 	cache := GetCache()
 	defer newSingleCache()
@@ -0,0 +1,411 @@
+/*
+This package implements the exporter configuration file given with --config.file.
+The file sets the collection options of every cloud in its global section, and overrides them per cloud
+in its clouds section. The options left unset keep the value of the command line flags.
+
+An example of a configuration file:
+
+```yaml
+global:
+  disabled_services: [gnocchi]
+  disabled_metrics: [nova-server_status]
+  endpoint_type: internal
+  cache_ttl: 5m
+  metric_deny: [openstack_neutron_port]
+  label_rules:
+    - metrics: openstack_neutron_network
+      action: labeldrop
+      regex: provider_.*
+
+clouds:
+  big-cloud:
+    disabled_services: [gnocchi, dns]
+    tenant_id: 0a1b2c3d
+    nova_metadata_extra_labels: [cost_center=cost-center, owner]
+    extra_labels:
+      cinder.volume: [cost_center=cost-center]
+      neutron.port: [owner]
+    cache_ttl: 15m
+    multi_region: true
+    external_labels:
+      datacenter: dc1
+```
+*/
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/openstack-exporter/openstack-exporter/exporters"
+	"github.com/openstack-exporter/openstack-exporter/utils"
+	"gopkg.in/yaml.v3"
+)
+
+var validEndpointTypes = []string{"public", "publicURL", "internal", "internalURL", "admin", "adminURL"}
+
+// Modes of the status metrics.
+const (
+	StatusMetricsIndex    = "index"
+	StatusMetricsStateSet = "stateset"
+)
+
+// Options are the collection options of a cloud.
+type Options struct {
+	EnabledServices []string
+	DisabledMetrics []string
+	EndpointType    string
+	DomainID        string
+	TenantID        string
+	// LabelMappings maps the metadata, properties or tags of the resources to labels.
+	LabelMappings *utils.ResourceLabelMappingFlag
+	CacheTTL      time.Duration
+	// RegionLabel adds the region of the cloud as a region label to the metrics.
+	RegionLabel bool
+	// MultiRegion collects the services in every region of the Keystone catalog, with a region label.
+	MultiRegion bool
+	// MetricFilter selects the exported metrics and labels, nil exports everything.
+	MetricFilter *utils.MetricFilter
+	// StateSetStatus sends the status metrics as one series per status, instead of the index of the status.
+	StateSetStatus bool
+	// ExternalLabels are added to the series of the cloud sent by remote write.
+	ExternalLabels map[string]string
+}
+
+// ExporterOptions returns the options of the exporter of a service in a region of cloud, with the
+// collection options of the cloud and the other options of defaults, set by the command line flags.
+func (o Options) ExporterOptions(defaults exporters.ExporterOptions, service, cloud, region string) exporters.ExporterOptions {
+	options := defaults
+	options.Service = service
+	options.Cloud = cloud
+	options.Region = region
+	options.DisabledMetrics = o.DisabledMetrics
+	options.EndpointType = o.EndpointType
+	options.StateSetStatus = o.StateSetStatus
+	options.DomainID = o.DomainID
+	options.TenantID = o.TenantID
+	options.LabelMappings = o.LabelMappings
+	options.MetricFilter = o.MetricFilter
+	return options
+}
+
+// Section holds the options set by the global section or by a cloud of the configuration file.
+// The options left unset keep the value inherited from the command line flags or the global section.
+type Section struct {
+	// EnabledServices replaces the list of the enabled services.
+	EnabledServices []string `yaml:"enabled_services"`
+	// DisabledServices are removed from the enabled services.
+	DisabledServices []string `yaml:"disabled_services"`
+	// DisabledMetrics replaces the disabled metrics, in the --disable-metric format: service-metric.
+	DisabledMetrics []string `yaml:"disabled_metrics"`
+	EndpointType    *string  `yaml:"endpoint_type"`
+	DomainID        *string  `yaml:"domain_id"`
+	TenantID        *string  `yaml:"tenant_id"`
+	// NovaMetadataExtraLabels replaces the mappings of the --nova.metadata-extra-labels format: label=key or key.
+	NovaMetadataExtraLabels []string `yaml:"nova_metadata_extra_labels"`
+	// ExtraLabels replaces the mappings of the --extra-labels resources it lists, in the label=key or key format.
+	ExtraLabels map[string][]string `yaml:"extra_labels"`
+	CacheTTL    *time.Duration      `yaml:"cache_ttl"`
+	RegionLabel *bool               `yaml:"region_label"`
+	MultiRegion *bool               `yaml:"multi_region"`
+	// MetricAllow and MetricDeny replace the patterns of --metric.allow and --metric.deny.
+	MetricAllow []string `yaml:"metric_allow"`
+	MetricDeny  []string `yaml:"metric_deny"`
+	// LabelRules replaces the label rules.
+	LabelRules []LabelRule `yaml:"label_rules"`
+	// SeriesLimit replaces the default series limit of --metric.series-limit, and SeriesLimits
+	// the series limits of the metrics they match.
+	SeriesLimit  *int          `yaml:"series_limit"`
+	SeriesLimits []SeriesLimit `yaml:"series_limits"`
+	// StatusMetrics replaces the --status-metrics mode: index or stateset.
+	StatusMetrics *string `yaml:"status_metrics"`
+	// ExternalLabels sets the external labels of the remote write, the other labels keep their value.
+	ExternalLabels map[string]string `yaml:"external_labels"`
+
+	labelMappings *utils.ResourceLabelMappingFlag
+	metricAllow   []*regexp.Regexp
+	metricDeny    []*regexp.Regexp
+	labelRules    []utils.LabelRule
+	seriesLimits  []utils.SeriesLimit
+}
+
+// LabelRule drops the labels matching Regex, or keeps only them, from the metrics matching Metrics.
+type LabelRule struct {
+	// Metrics is a pattern selecting the metrics by name, empty selects every metric.
+	Metrics string `yaml:"metrics"`
+	// Action is labeldrop or labelkeep.
+	Action string `yaml:"action"`
+	Regex  string `yaml:"regex"`
+}
+
+// SeriesLimit is the series limit of the metrics matching Metrics, 0 means no limit.
+type SeriesLimit struct {
+	Metrics string `yaml:"metrics"`
+	Limit   int    `yaml:"limit"`
+}
+
+// File is the content of the configuration file.
+type File struct {
+	Global Section             `yaml:"global"`
+	Clouds map[string]*Section `yaml:"clouds"`
+}
+
+// Load reads and validates the configuration file at path.
+// The enabled and disabled services must be part of knownServices.
+func Load(path string, knownServices []string) (*File, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	file, err := Parse(content, knownServices)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// Parse parses and validates the content of a configuration file.
+func Parse(content []byte, knownServices []string) (*File, error) {
+	file := &File{}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	if err := file.Global.validate("global", knownServices); err != nil {
+		return nil, err
+	}
+	for cloud, section := range file.Clouds {
+		if section == nil {
+			section = &Section{}
+			file.Clouds[cloud] = section
+		}
+		if err := section.validate(fmt.Sprintf("clouds.%s", cloud), knownServices); err != nil {
+			return nil, err
+		}
+	}
+	return file, nil
+}
+
+func (s *Section) validate(path string, knownServices []string) error {
+	for _, services := range []struct {
+		field string
+		names []string
+	}{
+		{"enabled_services", s.EnabledServices},
+		{"disabled_services", s.DisabledServices},
+	} {
+		for _, service := range services.names {
+			if !slices.Contains(knownServices, service) {
+				return fmt.Errorf("%s.%s: unknown service %q, must be one of %v", path, services.field, service, knownServices)
+			}
+		}
+	}
+
+	for _, metric := range s.DisabledMetrics {
+		if service, name, ok := strings.Cut(metric, "-"); !ok || service == "" || name == "" {
+			return fmt.Errorf("%s.disabled_metrics: invalid metric %q, must be in the format service-metric", path, metric)
+		}
+	}
+
+	if s.EndpointType != nil && !slices.Contains(validEndpointTypes, *s.EndpointType) {
+		return fmt.Errorf("%s.endpoint_type: invalid endpoint type %q, must be one of %v", path, *s.EndpointType, validEndpointTypes)
+	}
+
+	if s.NovaMetadataExtraLabels != nil || s.ExtraLabels != nil {
+		s.labelMappings = &utils.ResourceLabelMappingFlag{Mappings: make(map[string]*utils.LabelMappingFlag)}
+	}
+	if s.NovaMetadataExtraLabels != nil {
+		mapping := new(utils.LabelMappingFlag)
+		for _, value := range s.NovaMetadataExtraLabels {
+			if err := mapping.Set(value); err != nil {
+				return fmt.Errorf("%s.nova_metadata_extra_labels: %w", path, err)
+			}
+		}
+		s.labelMappings.Mappings["nova.server"] = mapping
+	}
+	for _, resource := range slices.Sorted(maps.Keys(s.ExtraLabels)) {
+		if err := s.labelMappings.Set(resource + ":"); err != nil {
+			return fmt.Errorf("%s.extra_labels: %w", path, err)
+		}
+		for _, mapping := range s.ExtraLabels[resource] {
+			if err := s.labelMappings.Set(resource + ":" + mapping); err != nil {
+				return fmt.Errorf("%s.extra_labels: %w", path, err)
+			}
+		}
+	}
+
+	var err error
+	if s.metricAllow, err = utils.CompilePatterns(s.MetricAllow); err != nil {
+		return fmt.Errorf("%s.metric_allow: %w", path, err)
+	}
+	if s.metricDeny, err = utils.CompilePatterns(s.MetricDeny); err != nil {
+		return fmt.Errorf("%s.metric_deny: %w", path, err)
+	}
+	if s.LabelRules != nil {
+		s.labelRules = []utils.LabelRule{}
+		for i, rule := range s.LabelRules {
+			labelRule, err := utils.NewLabelRule(rule.Metrics, rule.Action, rule.Regex)
+			if err != nil {
+				return fmt.Errorf("%s.label_rules[%d]: %w", path, i, err)
+			}
+			s.labelRules = append(s.labelRules, labelRule)
+		}
+	}
+
+	if s.SeriesLimit != nil && *s.SeriesLimit < 0 {
+		return fmt.Errorf("%s.series_limit: must be positive, got %d", path, *s.SeriesLimit)
+	}
+	if s.SeriesLimits != nil {
+		s.seriesLimits = []utils.SeriesLimit{}
+		for i, limit := range s.SeriesLimits {
+			metrics, err := utils.CompilePattern(limit.Metrics)
+			if err != nil {
+				return fmt.Errorf("%s.series_limits[%d]: %w", path, i, err)
+			}
+			if limit.Limit < 0 {
+				return fmt.Errorf("%s.series_limits[%d]: limit must be positive, got %d", path, i, limit.Limit)
+			}
+			s.seriesLimits = append(s.seriesLimits, utils.SeriesLimit{Metrics: metrics, Limit: limit.Limit})
+		}
+	}
+
+	if s.StatusMetrics != nil && *s.StatusMetrics != StatusMetricsIndex && *s.StatusMetrics != StatusMetricsStateSet {
+		return fmt.Errorf("%s.status_metrics: invalid mode %q, must be one of %s or %s", path, *s.StatusMetrics, StatusMetricsIndex, StatusMetricsStateSet)
+	}
+
+	externalLabels := new(utils.ExternalLabelsFlag)
+	for _, name := range slices.Sorted(maps.Keys(s.ExternalLabels)) {
+		if err := externalLabels.Set(name + "=" + s.ExternalLabels[name]); err != nil {
+			return fmt.Errorf("%s.external_labels: %w", path, err)
+		}
+	}
+
+	if s.CacheTTL != nil && *s.CacheTTL <= 0 {
+		return fmt.Errorf("%s.cache_ttl: must be greater than 0, got %s", path, *s.CacheTTL)
+	}
+	return nil
+}
+
+// apply overrides the options with the ones set in the section.
+func (s *Section) apply(options Options) Options {
+	if s.EnabledServices != nil {
+		options.EnabledServices = slices.Clone(s.EnabledServices)
+	}
+	if s.DisabledServices != nil {
+		options.EnabledServices = slices.DeleteFunc(slices.Clone(options.EnabledServices), func(service string) bool {
+			return slices.Contains(s.DisabledServices, service)
+		})
+	}
+	if s.DisabledMetrics != nil {
+		options.DisabledMetrics = slices.Clone(s.DisabledMetrics)
+	}
+	if s.EndpointType != nil {
+		options.EndpointType = *s.EndpointType
+	}
+	if s.DomainID != nil {
+		options.DomainID = *s.DomainID
+	}
+	if s.TenantID != nil {
+		options.TenantID = *s.TenantID
+	}
+	if s.labelMappings != nil {
+		mappings := options.LabelMappings.Clone()
+		for resource, mapping := range s.labelMappings.Mappings {
+			mappings.Mappings[resource] = mapping
+		}
+		options.LabelMappings = mappings
+	}
+	if s.CacheTTL != nil {
+		options.CacheTTL = *s.CacheTTL
+	}
+	if s.RegionLabel != nil {
+		options.RegionLabel = *s.RegionLabel
+	}
+	if s.MultiRegion != nil {
+		options.MultiRegion = *s.MultiRegion
+	}
+	if s.MetricAllow != nil || s.MetricDeny != nil || s.LabelRules != nil || s.SeriesLimit != nil || s.SeriesLimits != nil {
+		filter := utils.MetricFilter{}
+		if options.MetricFilter != nil {
+			filter = *options.MetricFilter
+		}
+		if s.MetricAllow != nil {
+			filter.Allow = s.metricAllow
+		}
+		if s.MetricDeny != nil {
+			filter.Deny = s.metricDeny
+		}
+		if s.LabelRules != nil {
+			filter.LabelRules = s.labelRules
+		}
+		if s.SeriesLimit != nil {
+			filter.SeriesLimit = *s.SeriesLimit
+		}
+		if s.SeriesLimits != nil {
+			filter.SeriesLimits = s.seriesLimits
+		}
+		options.MetricFilter = &filter
+	}
+	if s.StatusMetrics != nil {
+		options.StateSetStatus = *s.StatusMetrics == StatusMetricsStateSet
+	}
+	if s.ExternalLabels != nil {
+		externalLabels := maps.Clone(options.ExternalLabels)
+		if externalLabels == nil {
+			externalLabels = make(map[string]string)
+		}
+		maps.Copy(externalLabels, s.ExternalLabels)
+		options.ExternalLabels = externalLabels
+	}
+	return options
+}
+
+// Options returns the options of a cloud: the defaults overridden by the global section,
+// then by the section of the cloud. A nil File returns the defaults.
+func (f *File) Options(cloud string, defaults Options) Options {
+	if f == nil {
+		return defaults
+	}
+
+	options := f.Global.apply(defaults)
+	if section, ok := f.Clouds[cloud]; ok {
+		options = section.apply(options)
+	}
+	return options
+}
+
+// CloudNames returns the names of the clouds configured in the file.
+func (f *File) CloudNames() []string {
+	if f == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(f.Clouds))
+	for cloud := range f.Clouds {
+		names = append(names, cloud)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// MinCacheTTL returns the shortest cache TTL of the global section and of the clouds.
+func (f *File) MinCacheTTL(defaults Options) time.Duration {
+	ttl := f.Options("", defaults).CacheTTL
+	for _, cloud := range f.CloudNames() {
+		ttl = min(ttl, f.Options(cloud, defaults).CacheTTL)
+	}
+	return ttl
+}
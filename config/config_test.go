@@ -0,0 +1,221 @@
+package config
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/openstack-exporter/openstack-exporter/exporters"
+	"github.com/openstack-exporter/openstack-exporter/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var knownServices = []string{"compute", "network", "dns", "gnocchi"}
+
+const testConfig = `
+global:
+  disabled_services: [gnocchi]
+  disabled_metrics: [nova-server_status]
+  endpoint_type: internal
+  cache_ttl: 5m
+  metric_allow: [openstack_neutron_.*]
+  series_limit: 1000
+  status_metrics: stateset
+  external_labels:
+    env: prod
+  label_rules:
+    - metrics: openstack_neutron_network
+      action: labeldrop
+      regex: provider_.*
+
+clouds:
+  big-cloud:
+    disabled_services: [gnocchi, dns]
+    tenant_id: 0a1b2c3d
+    nova_metadata_extra_labels: [cost_center=cost-center, owner]
+    extra_labels:
+      cinder.volume: [cost_center=cost-center]
+    cache_ttl: 15m
+    multi_region: true
+    metric_deny: [openstack_neutron_port]
+    series_limits:
+      - metrics: openstack_neutron_network
+        limit: 5000
+    external_labels:
+      datacenter: dc1
+  small-cloud:
+    enabled_services: [compute]
+    status_metrics: index
+    disabled_metrics: []
+`
+
+func defaultOptions() Options {
+	return Options{
+		EnabledServices: knownServices,
+		DisabledMetrics: []string{"neutron-port"},
+		EndpointType:    "public",
+		DomainID:        "default",
+		LabelMappings:   new(utils.ResourceLabelMappingFlag),
+		CacheTTL:        time.Minute,
+	}
+}
+
+func TestOptions(t *testing.T) {
+	file, err := Parse([]byte(testConfig), knownServices)
+	require.NoError(t, err)
+
+	options := file.Options("other-cloud", defaultOptions())
+	assert.Equal(t, []string{"compute", "network", "dns"}, options.EnabledServices)
+	assert.Equal(t, []string{"nova-server_status"}, options.DisabledMetrics)
+	assert.Equal(t, "internal", options.EndpointType)
+	assert.Equal(t, "default", options.DomainID, "the options missing in the file should keep the flag value")
+	assert.Equal(t, 5*time.Minute, options.CacheTTL)
+	assert.True(t, options.MetricFilter.MetricAllowed("openstack_neutron_port"))
+	assert.False(t, options.MetricFilter.MetricAllowed("openstack_nova_flavor"))
+	assert.Equal(t, []int{0}, options.MetricFilter.KeptLabels("openstack_neutron_network", []string{"id", "provider_network_type"}))
+
+	options = file.Options("big-cloud", defaultOptions())
+	assert.Equal(t, []string{"compute", "network"}, options.EnabledServices)
+	assert.Equal(t, "0a1b2c3d", options.TenantID)
+	assert.Equal(t, []string{"cost_center", "owner"}, options.LabelMappings.Get("nova.server").Labels)
+	assert.Equal(t, []string{"cost-center", "owner"}, options.LabelMappings.Get("nova.server").Keys)
+	assert.Equal(t, []string{"cost_center"}, options.LabelMappings.Get("cinder.volume").Labels)
+	assert.Equal(t, 15*time.Minute, options.CacheTTL)
+	assert.True(t, options.MultiRegion)
+	assert.False(t, options.MetricFilter.MetricAllowed("openstack_neutron_port"))
+	assert.True(t, options.MetricFilter.MetricAllowed("openstack_neutron_ports"), "the cloud should keep the global allow list")
+	assert.Equal(t, 5000, options.MetricFilter.MetricSeriesLimit("openstack_neutron_network", true))
+	assert.True(t, options.StateSetStatus)
+	assert.Equal(t, 1000, options.MetricFilter.MetricSeriesLimit("openstack_neutron_subnet", true))
+	assert.Equal(t, map[string]string{"env": "prod", "datacenter": "dc1"}, options.ExternalLabels, "the cloud should add its external labels to the global ones")
+
+	options = file.Options("small-cloud", defaultOptions())
+	assert.Equal(t, []string{"compute"}, options.EnabledServices)
+	assert.False(t, options.StateSetStatus, "the cloud should override the global status metrics mode")
+	assert.Equal(t, []string{}, options.DisabledMetrics)
+
+	assert.Equal(t, []string{"big-cloud", "small-cloud"}, file.CloudNames())
+	assert.Equal(t, 5*time.Minute, file.MinCacheTTL(defaultOptions()))
+
+	var noFile *File
+	assert.Equal(t, defaultOptions(), noFile.Options("big-cloud", defaultOptions()))
+}
+
+func TestExporterOptions(t *testing.T) {
+	file, err := Parse([]byte(testConfig), knownServices)
+	require.NoError(t, err)
+
+	options := file.Options("big-cloud", defaultOptions())
+	defaults := exporters.ExporterOptions{Prefix: "openstack", CollectTime: true, CollectConcurrency: 4}
+	exporterOptions := options.ExporterOptions(defaults, "compute", "big-cloud", "RegionOne")
+	assert.Equal(t, "compute", exporterOptions.Service)
+	assert.Equal(t, "big-cloud", exporterOptions.Cloud)
+	assert.Equal(t, "RegionOne", exporterOptions.Region)
+	assert.Equal(t, "openstack", exporterOptions.Prefix, "the options of the flags should be kept")
+	assert.True(t, exporterOptions.CollectTime)
+	assert.Equal(t, 4, exporterOptions.CollectConcurrency)
+	assert.Equal(t, "internal", exporterOptions.EndpointType)
+	assert.Equal(t, "0a1b2c3d", exporterOptions.TenantID)
+	assert.True(t, exporterOptions.StateSetStatus)
+	assert.Same(t, options.MetricFilter, exporterOptions.MetricFilter)
+	assert.Same(t, options.LabelMappings, exporterOptions.LabelMappings)
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{
+			name:    "unknown field",
+			content: "global:\n  disabled_service: [dns]\n",
+			err:     "field disabled_service not found",
+		},
+		{
+			name:    "unknown service",
+			content: "clouds:\n  cloud:\n    enabled_services: [nova]\n",
+			err:     `clouds.cloud.enabled_services: unknown service "nova"`,
+		},
+		{
+			name:    "invalid metric",
+			content: "global:\n  disabled_metrics: [snapshots]\n",
+			err:     `global.disabled_metrics: invalid metric "snapshots"`,
+		},
+		{
+			name:    "invalid endpoint type",
+			content: "global:\n  endpoint_type: private\n",
+			err:     `global.endpoint_type: invalid endpoint type "private"`,
+		},
+		{
+			name:    "invalid label",
+			content: "clouds:\n  cloud:\n    nova_metadata_extra_labels: [cost-center]\n",
+			err:     "clouds.cloud.nova_metadata_extra_labels: bad label name: cost-center",
+		},
+		{
+			name:    "unknown extra labels resource",
+			content: "global:\n  extra_labels:\n    cinder.snapshot: [owner]\n",
+			err:     "global.extra_labels: unknown resource: cinder.snapshot",
+		},
+		{
+			name:    "invalid extra label",
+			content: "global:\n  extra_labels:\n    neutron.port: [owner, owner]\n",
+			err:     "global.extra_labels: neutron.port: duplicate label: owner",
+		},
+		{
+			name:    "invalid metric pattern",
+			content: "global:\n  metric_deny: [\"openstack_(\"]\n",
+			err:     `global.metric_deny: invalid pattern "openstack_("`,
+		},
+		{
+			name:    "invalid label rule",
+			content: "clouds:\n  cloud:\n    label_rules: [{action: replace, regex: id}]\n",
+			err:     `clouds.cloud.label_rules[0]: invalid label rule action "replace"`,
+		},
+		{
+			name:    "invalid series limit",
+			content: "clouds:\n  cloud:\n    series_limits: [{metrics: openstack_neutron_port, limit: -1}]\n",
+			err:     "clouds.cloud.series_limits[0]: limit must be positive",
+		},
+		{
+			name:    "invalid status metrics mode",
+			content: "global:\n  status_metrics: enum\n",
+			err:     `global.status_metrics: invalid mode "enum"`,
+		},
+		{
+			name:    "invalid external label",
+			content: "clouds:\n  cloud:\n    external_labels:\n      data-center: dc1\n",
+			err:     "clouds.cloud.external_labels: bad label name: data-center",
+		},
+		{
+			name:    "invalid cache ttl",
+			content: "global:\n  cache_ttl: 0s\n",
+			err:     "global.cache_ttl: must be greater than 0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse([]byte(test.content), knownServices)
+			assert.ErrorContains(t, err, test.err)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	configFile := path.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("global:\n  endpoint_type: private\n"), 0o600))
+
+	_, err := Load(configFile, knownServices)
+	assert.ErrorContains(t, err, "invalid config file "+configFile)
+
+	_, err = Load(path.Join(t.TempDir(), "missing.yaml"), knownServices)
+	assert.ErrorContains(t, err, "failed to read config file")
+
+	require.NoError(t, os.WriteFile(configFile, []byte(""), 0o600))
+	file, err := Load(configFile, knownServices)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultOptions(), file.Options("cloud", defaultOptions()))
+}
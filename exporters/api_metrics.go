@@ -0,0 +1,222 @@
+package exporters
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unknownService is the service label of the requests to an endpoint unknown to the exporters.
+const unknownService = "unknown"
+
+// idSegment matches the path segments holding a resource ID: UUIDs, with or without dashes, and numbers.
+var idSegment = regexp.MustCompile(`^([0-9a-fA-F]{8}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{12}|[0-9]+)$`)
+
+// versionSegment matches the version segments of the endpoints, i.e: v2.1.
+var versionSegment = regexp.MustCompile(`^v[0-9.]+$`)
+
+// requestIDHeaders are the headers of the ID of a request in the logs of the OpenStack services.
+var requestIDHeaders = []string{"X-Openstack-Request-Id", "X-Compute-Request-Id"}
+
+// apiMetrics are the metrics of the requests to the OpenStack APIs, nil until InstrumentAPIRequests is called.
+var apiMetrics *APIMetrics
+
+// APIMetrics collects the metrics of the requests of the exporters to the OpenStack APIs.
+type APIMetrics struct {
+	requests     *prometheus.CounterVec
+	duration     *prometheus.HistogramVec
+	breakerState *prometheus.GaugeVec
+}
+
+// InstrumentAPIRequests instruments the transport of the provider clients authenticated afterwards,
+// and returns the collector of the metrics of their requests. It must be called before the exporters
+// are enabled.
+func InstrumentAPIRequests(prefix string) *APIMetrics {
+	apiMetrics = &APIMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: prefix + "_api_requests_total",
+			Help: "Total number of requests to the OpenStack APIs, by path template and status code",
+		}, []string{"cloud", "service", "method", "path_template", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    prefix + "_api_request_duration_seconds",
+			Help:    "Duration of the requests to the OpenStack APIs in seconds",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"cloud", "service", "method", "path_template"}),
+		breakerState: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: prefix + "_api_circuit_breaker_state",
+			Help: "State of the circuit breaker of the requests to the OpenStack API of a service in a region (0=closed, 1=open, 2=half-open)",
+		}, []string{"cloud", "region", "service"}),
+	}
+	return apiMetrics
+}
+
+func (m *APIMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.requests.Describe(ch)
+	m.duration.Describe(ch)
+	m.breakerState.Describe(ch)
+}
+
+func (m *APIMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.requests.Collect(ch)
+	m.duration.Collect(ch)
+	m.breakerState.Collect(ch)
+}
+
+// apiInstrumentation instruments the requests of the provider clients of a cloud, and applies their
+// retries and circuit breakers. The requests are attributed to the service and the region of the
+// longest endpoint prefixing their URL.
+type apiInstrumentation struct {
+	metrics *APIMetrics
+	config  APIRequestConfig
+	cloud   string
+	logger  *slog.Logger
+
+	mu        sync.RWMutex
+	endpoints map[string]apiEndpoint
+	breakers  map[apiEndpoint]*circuitBreaker
+}
+
+// apiEndpoint is the service and the region of an endpoint, the region being empty for the
+// endpoints of the whole cloud, i.e: the identity endpoint.
+type apiEndpoint struct {
+	service string
+	region  string
+}
+
+// newAPIInstrumentation returns the instrumentation of the requests to cloud, nil if the requests
+// are neither instrumented nor retried.
+func newAPIInstrumentation(cloud string, logger *slog.Logger) *apiInstrumentation {
+	if apiMetrics == nil && apiRequestConfig == (APIRequestConfig{}) {
+		return nil
+	}
+	return &apiInstrumentation{
+		metrics:   apiMetrics,
+		config:    apiRequestConfig,
+		cloud:     cloud,
+		logger:    logger,
+		endpoints: make(map[string]apiEndpoint),
+		breakers:  make(map[apiEndpoint]*circuitBreaker),
+	}
+}
+
+// addEndpoint attributes the requests to endpoint to service in region. The requests to the root of
+// the endpoint, without its version and project ID, i.e: the version discovery, are attributed to
+// service as well unless another service has this endpoint. The root keeps a path: the root of
+// the host would catch the version discovery of every other service behind it.
+func (a *apiInstrumentation) addEndpoint(endpoint, service, region string) {
+	if a == nil || endpoint == "" {
+		return
+	}
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.endpoints[endpoint+"/"] = apiEndpoint{service: service, region: region}
+
+	host := 0
+	if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+		host = len(u.Scheme + "://" + u.Host)
+	}
+	root := endpoint
+	for {
+		i := strings.LastIndex(root, "/")
+		if i <= host || strings.HasSuffix(root[:i], "/") {
+			break
+		}
+		if segment := root[i+1:]; !versionSegment.MatchString(segment) && !idSegment.MatchString(segment) {
+			break
+		}
+		root = root[:i]
+	}
+	if _, ok := a.endpoints[root+"/"]; !ok {
+		a.endpoints[root+"/"] = apiEndpoint{service: service, region: region}
+	}
+}
+
+// service returns the service and the region of a request and the template of its path, the path
+// relative to the endpoint of the service with the IDs replaced by {id}.
+func (a *apiInstrumentation) service(u *url.URL) (apiEndpoint, string) {
+	target := u.Scheme + "://" + u.Host + strings.TrimSuffix(u.Path, "/") + "/"
+	service, endpoint := apiEndpoint{service: unknownService}, ""
+	a.mu.RLock()
+	for prefix, e := range a.endpoints {
+		if len(prefix) > len(endpoint) && strings.HasPrefix(target, prefix) {
+			service, endpoint = e, prefix
+		}
+	}
+	a.mu.RUnlock()
+
+	if endpoint == "" {
+		return service, pathTemplate(u.Path)
+	}
+	return service, pathTemplate(strings.TrimPrefix(target, endpoint))
+}
+
+// pathTemplate returns the path with its ID segments replaced by {id}, i.e: servers/{id}/os-interface.
+func pathTemplate(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		if idSegment.MatchString(segment) {
+			segments[i] = "{id}"
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// wrap returns next instrumented, with the retries and circuit breakers of apiRequestConfig.
+func (a *apiInstrumentation) wrap(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		service, template := a.service(req.URL)
+		return a.retry(req, service, func() (*http.Response, error) {
+			return a.roundTrip(next, req, service.service, template)
+		})
+	})
+}
+
+// roundTrip sends a request with next, and records it.
+func (a *apiInstrumentation) roundTrip(next http.RoundTripper, req *http.Request, service, template string) (*http.Response, error) {
+	if a.metrics == nil {
+		return next.RoundTrip(req)
+	}
+	start := timeNow()
+	resp, err := next.RoundTrip(req)
+	a.metrics.duration.WithLabelValues(a.cloud, service, req.Method, template).Observe(timeNow().Sub(start).Seconds())
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	a.metrics.requests.WithLabelValues(a.cloud, service, req.Method, template, code).Inc()
+
+	switch {
+	case err != nil:
+		a.logger.Warn("OpenStack API request failed", "cloud", a.cloud, "service", service, "method", req.Method, "path", req.URL.Path, "err", err)
+	case resp.StatusCode == http.StatusUnauthorized:
+		// The provider clients authenticate again when their token expires.
+		a.logger.Debug("OpenStack API request unauthorized", "cloud", a.cloud, "service", service, "method", req.Method, "path", req.URL.Path, "request_id", requestID(resp))
+	case resp.StatusCode >= http.StatusBadRequest:
+		a.logger.Warn("OpenStack API request failed", "cloud", a.cloud, "service", service, "method", req.Method, "path", req.URL.Path, "code", resp.StatusCode, "request_id", requestID(resp))
+	}
+	return resp, err
+}
+
+// requestID returns the ID of the request of resp in the logs of the service.
+func requestID(resp *http.Response) string {
+	for _, header := range requestIDHeaders {
+		if id := resp.Header.Get(header); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,106 @@
+package exporters
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIInstrumentation(t *testing.T) {
+	metrics := InstrumentAPIRequests("openstack")
+	t.Cleanup(func() { apiMetrics = nil })
+	var logs bytes.Buffer
+	api := newAPIInstrumentation("mycloud", slog.New(slog.NewTextHandler(&logs, nil)))
+	api.addEndpoint("http://test.cloud:35357/", "identity", "")
+	api.addEndpoint("http://test.cloud/compute/v2.1", "compute", "RegionOne")
+	api.addEndpoint("http://test.cloud/volumes/v3/4bd5ad63d5ba4bd3a00a2e7e79f8f8e8/", "volume", "RegionOne")
+
+	client := &http.Client{Transport: api.wrap(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Host == "down.cloud" {
+			return nil, errors.New("connection refused")
+		}
+		resp := &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: http.NoBody, Request: req}
+		if strings.HasPrefix(req.URL.Path, "/compute/v2.1/servers/") && req.URL.Path != "/compute/v2.1/servers/detail" {
+			resp.StatusCode = http.StatusNotFound
+			resp.Header.Set("X-Openstack-Request-Id", "req-0f1e2d3c")
+		}
+		return resp, nil
+	}))}
+
+	for _, url := range []string{
+		"http://test.cloud:35357/v3/projects/4bd5ad63d5ba4bd3a00a2e7e79f8f8e8",
+		"http://test.cloud/compute/v2.1/servers/detail?all_tenants=true",
+		"http://test.cloud/compute/v2.1/servers/detail?all_tenants=true&marker=1",
+		"http://test.cloud/compute/v2.1/servers/0b6ee2f4-8f05-4d4b-a57c-c9d3bb4f8a3b/os-interface",
+		"http://test.cloud/volumes/v3/4bd5ad63d5ba4bd3a00a2e7e79f8f8e8/volumes/detail",
+		"http://test.cloud/compute-extra/v1/items/42",
+		"http://down.cloud/compute/v2.1/servers",
+	} {
+		resp, err := client.Get(url)
+		if err == nil {
+			resp.Body.Close()
+		}
+	}
+
+	expected := `
+# HELP openstack_api_requests_total Total number of requests to the OpenStack APIs, by path template and status code
+# TYPE openstack_api_requests_total counter
+openstack_api_requests_total{cloud="mycloud",code="200",method="GET",path_template="/compute-extra/v1/items/{id}",service="unknown"} 1
+openstack_api_requests_total{cloud="mycloud",code="200",method="GET",path_template="/servers/detail",service="compute"} 2
+openstack_api_requests_total{cloud="mycloud",code="200",method="GET",path_template="/v3/projects/{id}",service="identity"} 1
+openstack_api_requests_total{cloud="mycloud",code="200",method="GET",path_template="/volumes/detail",service="volume"} 1
+openstack_api_requests_total{cloud="mycloud",code="404",method="GET",path_template="/servers/{id}/os-interface",service="compute"} 1
+openstack_api_requests_total{cloud="mycloud",code="error",method="GET",path_template="/compute/v2.1/servers",service="unknown"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(metrics, strings.NewReader(expected), "openstack_api_requests_total"))
+	assert.Equal(t, 6, testutil.CollectAndCount(metrics, "openstack_api_request_duration_seconds"))
+
+	assert.Contains(t, logs.String(), "code=404 request_id=req-0f1e2d3c")
+	assert.Contains(t, logs.String(), `err="connection refused"`)
+	assert.Equal(t, 2, strings.Count(logs.String(), "OpenStack API request failed"), "only the failed requests should be logged")
+}
+
+func TestAPIInstrumentationSharedHost(t *testing.T) {
+	InstrumentAPIRequests("openstack")
+	t.Cleanup(func() { apiMetrics = nil })
+	api := newAPIInstrumentation("mycloud", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	api.addEndpoint("http://test.cloud/v3", "identity", "")
+	api.addEndpoint("http://test.cloud/compute/v2.1", "compute", "RegionOne")
+	api.addEndpoint("http://test.cloud/image", "image", "RegionOne")
+
+	for rawURL, expected := range map[string][2]string{
+		"http://test.cloud/v3/auth/tokens":               {"identity", "/auth/tokens"},
+		"http://test.cloud/compute/":                     {"compute", "/"},
+		"http://test.cloud/compute/v2.1/servers":         {"compute", "/servers"},
+		"http://test.cloud/image/v2/images":              {"image", "/v2/images"},
+		"http://test.cloud/volumes/":                     {unknownService, "/volumes"},
+		"http://test.cloud/placement/resource_providers": {unknownService, "/placement/resource_providers"},
+	} {
+		u, err := url.Parse(rawURL)
+		require.NoError(t, err)
+		endpoint, template := api.service(u)
+		assert.Equal(t, expected, [2]string{endpoint.service, template}, rawURL)
+	}
+}
+
+func TestPathTemplate(t *testing.T) {
+	for path, expected := range map[string]string{
+		"":                  "/",
+		"/servers/detail":   "/servers/detail",
+		"/v2.0/ports":       "/v2.0/ports",
+		"/flavors/m1.small": "/flavors/m1.small",
+		"servers/0b6ee2f4-8f05-4d4b-a57c-c9d3bb4f8a3b/":       "/servers/{id}",
+		"/projects/4bd5ad63d5ba4bd3a00a2e7e79f8f8e8/users/12": "/projects/{id}/users/{id}",
+	} {
+		assert.Equal(t, expected, pathTemplate(path), path)
+	}
+}
@@ -0,0 +1,256 @@
+package exporters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrCircuitOpen is the error of the requests to a service whose circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// APIRequestConfig configures the retries and the circuit breakers of the requests to the OpenStack APIs.
+type APIRequestConfig struct {
+	// MaxRetries is the number of retries of the GET requests failing with a server error or a rate limit.
+	MaxRetries int
+	// MinBackoff and MaxBackoff bound the jittered exponential backoff between the retries of a request.
+	// A Retry-After longer than MaxBackoff is not waited for, the response is returned as is.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// BreakerFailures is the number of consecutive failures of the requests to a service opening its
+	// circuit breaker, 0 to disable the circuit breakers.
+	BreakerFailures int
+	// BreakerTimeout is the time the circuit breaker of a service stays open, failing the requests to
+	// the service fast, before letting a request probe the service again.
+	BreakerTimeout time.Duration
+}
+
+// apiRequestConfig is the configuration of the requests of the provider clients, see ConfigureAPIRequests.
+var apiRequestConfig APIRequestConfig
+
+// ConfigureAPIRequests sets the retries and the circuit breakers of the requests of the provider clients
+// authenticated afterwards. It must be called before the exporters are enabled.
+func ConfigureAPIRequests(config APIRequestConfig) error {
+	if config.MaxRetries < 0 {
+		return fmt.Errorf("invalid number of retries %d, must be positive", config.MaxRetries)
+	}
+	if config.MaxRetries > 0 && (config.MinBackoff <= 0 || config.MaxBackoff < config.MinBackoff) {
+		return fmt.Errorf("invalid retry backoff, the min backoff %s must be positive and not exceed the max backoff %s", config.MinBackoff, config.MaxBackoff)
+	}
+	if config.BreakerFailures < 0 {
+		return fmt.Errorf("invalid number of circuit breaker failures %d, must be positive", config.BreakerFailures)
+	}
+	if config.BreakerFailures > 0 && config.BreakerTimeout <= 0 {
+		return fmt.Errorf("invalid circuit breaker timeout %s, must be positive", config.BreakerTimeout)
+	}
+	apiRequestConfig = config
+	return nil
+}
+
+// retry sends a request with send, retrying it on server errors and rate limits, through the
+// circuit breaker of its service in its region.
+func (a *apiInstrumentation) retry(req *http.Request, endpoint apiEndpoint, send func() (*http.Response, error)) (*http.Response, error) {
+	breaker := a.breaker(endpoint)
+	for attempt := 0; ; attempt++ {
+		if !breaker.allow() {
+			if endpoint.region != "" {
+				return nil, fmt.Errorf("%w for the %s API of cloud %s in region %s", ErrCircuitOpen, endpoint.service, a.cloud, endpoint.region)
+			}
+			return nil, fmt.Errorf("%w for the %s API of cloud %s", ErrCircuitOpen, endpoint.service, a.cloud)
+		}
+		resp, err := send()
+		breaker.record(resp, err)
+
+		delay, ok := a.retryDelay(req, resp, err, attempt)
+		if !ok || breaker.isOpen() {
+			return resp, err
+		}
+		a.logger.Debug("Retrying the OpenStack API request", "cloud", a.cloud, "region", endpoint.region, "service", endpoint.service, "method", req.Method, "path", req.URL.Path, "code", resp.StatusCode, "retry", attempt+1, "backoff", delay)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryDelay returns the time to wait before retrying a request, false if it must not be retried.
+// Only the GET requests without body are retried, as they are idempotent.
+func (a *apiInstrumentation) retryDelay(req *http.Request, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if err != nil || attempt >= a.config.MaxRetries {
+		return 0, false
+	}
+	if req.Method != http.MethodGet || (req.Body != nil && req.Body != http.NoBody) {
+		return 0, false
+	}
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < http.StatusInternalServerError {
+		return 0, false
+	}
+
+	backoff := a.config.MinBackoff
+	for i := 0; i < attempt && backoff < a.config.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, a.config.MaxBackoff)
+	// Full jitter on the upper half, so that the clients of a cloud don't retry in lockstep.
+	delay := backoff/2 + rand.N(backoff/2+1)
+	if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+		if retryAfter > a.config.MaxBackoff {
+			return 0, false
+		}
+		delay = max(delay, retryAfter)
+	}
+	if deadline, ok := req.Context().Deadline(); ok && timeNow().Add(delay).After(deadline) {
+		return 0, false
+	}
+	return delay, true
+}
+
+// parseRetryAfter returns the delay of a Retry-After header, in seconds or as an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(timeNow()), 0), true
+	}
+	return 0, false
+}
+
+// States of the circuit breakers, the values of their metric.
+const (
+	breakerClosed = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker fails the requests to a service in a region fast once failures consecutive requests failed.
+// After timeout, a single request probes the service: the breaker closes if it succeeds and opens
+// again otherwise. A nil circuit breaker lets every request through.
+type circuitBreaker struct {
+	failures int
+	timeout  time.Duration
+	// setState reports the state of the breaker.
+	setState func(state int)
+
+	mu          sync.Mutex
+	state       int
+	consecutive int
+	openedAt    time.Time
+	probing     bool
+}
+
+// breaker returns the circuit breaker of a service in a region, nil if the circuit breakers are
+// disabled. The regions of a cloud share its provider clients, a service down in one region must
+// not fail the requests to the others.
+func (a *apiInstrumentation) breaker(endpoint apiEndpoint) *circuitBreaker {
+	if a.config.BreakerFailures == 0 {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	breaker, ok := a.breakers[endpoint]
+	if !ok {
+		var gauge prometheus.Gauge
+		if a.metrics != nil {
+			gauge = a.metrics.breakerState.WithLabelValues(a.cloud, endpoint.region, endpoint.service)
+		}
+		breaker = &circuitBreaker{failures: a.config.BreakerFailures, timeout: a.config.BreakerTimeout, setState: func(state int) {
+			if gauge != nil {
+				gauge.Set(float64(state))
+			}
+			switch state {
+			case breakerOpen:
+				a.logger.Warn("Circuit breaker opened, failing the requests to the OpenStack API fast", "cloud", a.cloud, "region", endpoint.region, "service", endpoint.service, "timeout", a.config.BreakerTimeout)
+			case breakerClosed:
+				a.logger.Info("Circuit breaker closed", "cloud", a.cloud, "region", endpoint.region, "service", endpoint.service)
+			}
+		}}
+		if gauge != nil {
+			gauge.Set(breakerClosed)
+		}
+		a.breakers[endpoint] = breaker
+	}
+	return breaker
+}
+
+// allow tells whether a request can be sent to the service.
+func (b *circuitBreaker) allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerOpen:
+		if timeNow().Sub(b.openedAt) < b.timeout {
+			return false
+		}
+		b.transition(breakerHalfOpen)
+		b.probing = true
+		return true
+	case breakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	}
+	return true
+}
+
+// record records the outcome of a request allowed by the breaker. The requests fail because of the
+// service on errors and server errors, the canceled requests tell nothing about the service.
+func (b *circuitBreaker) record(resp *http.Response, err error) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	if err == nil && resp.StatusCode < http.StatusInternalServerError {
+		b.consecutive = 0
+		b.transition(breakerClosed)
+		return
+	}
+	b.consecutive++
+	if b.state == breakerHalfOpen || b.consecutive >= b.failures {
+		b.openedAt = timeNow()
+		b.transition(breakerOpen)
+	}
+}
+
+func (b *circuitBreaker) isOpen() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == breakerOpen
+}
+
+func (b *circuitBreaker) transition(state int) {
+	if b.state != state {
+		b.state = state
+		b.setState(state)
+	}
+}
@@ -0,0 +1,149 @@
+package exporters
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRetriedClient returns a client of a cloud whose compute API answers with the given status
+// codes, the last one being repeated, and the number of requests it received.
+func newRetriedClient(t *testing.T, config APIRequestConfig, header http.Header, codes ...int) (*http.Client, *atomic.Int32) {
+	require.NoError(t, ConfigureAPIRequests(config))
+	InstrumentAPIRequests("openstack")
+	t.Cleanup(func() { apiMetrics, apiRequestConfig = nil, APIRequestConfig{} })
+
+	api := newAPIInstrumentation("mycloud", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	api.addEndpoint("http://test.cloud/compute/v2.1", "compute", "RegionOne")
+	var requests atomic.Int32
+	client := &http.Client{Transport: api.wrap(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		i := int(requests.Add(1)) - 1
+		if codes[min(i, len(codes)-1)] == 0 {
+			return nil, errors.New("connection refused")
+		}
+		return &http.Response{StatusCode: codes[min(i, len(codes)-1)], Header: header, Body: http.NoBody, Request: req}, nil
+	}))}
+	return client, &requests
+}
+
+func TestAPIRetries(t *testing.T) {
+	config := APIRequestConfig{MaxRetries: 2, MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+
+	client, requests := newRetriedClient(t, config, nil, http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK)
+	resp, err := client.Get("http://test.cloud/compute/v2.1/servers/detail")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "the server errors and rate limits should be retried")
+	assert.Equal(t, int32(3), requests.Load())
+	expected := `
+# HELP openstack_api_requests_total Total number of requests to the OpenStack APIs, by path template and status code
+# TYPE openstack_api_requests_total counter
+openstack_api_requests_total{cloud="mycloud",code="200",method="GET",path_template="/servers/detail",service="compute"} 1
+openstack_api_requests_total{cloud="mycloud",code="429",method="GET",path_template="/servers/detail",service="compute"} 1
+openstack_api_requests_total{cloud="mycloud",code="503",method="GET",path_template="/servers/detail",service="compute"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(apiMetrics, strings.NewReader(expected), "openstack_api_requests_total"), "every attempt should be counted")
+
+	client, requests = newRetriedClient(t, config, nil, http.StatusBadGateway)
+	resp, err = client.Get("http://test.cloud/compute/v2.1/servers/detail")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode, "the last response should be returned once the retries are exhausted")
+	assert.Equal(t, int32(3), requests.Load())
+
+	client, requests = newRetriedClient(t, config, nil, http.StatusServiceUnavailable)
+	resp, err = client.Post("http://test.cloud/compute/v2.1/servers", "application/json", strings.NewReader("{}"))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, int32(1), requests.Load(), "the requests other than GET should not be retried")
+
+	client, requests = newRetriedClient(t, config, nil, http.StatusNotFound)
+	_, err = client.Get("http://test.cloud/compute/v2.1/servers/detail")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), requests.Load(), "the client errors should not be retried")
+
+	client, requests = newRetriedClient(t, config, http.Header{"Retry-After": {"60"}}, http.StatusTooManyRequests)
+	_, err = client.Get("http://test.cloud/compute/v2.1/servers/detail")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), requests.Load(), "a Retry-After longer than the max backoff should not be waited for")
+
+	config.MaxBackoff = 2 * time.Second
+	client, requests = newRetriedClient(t, config, http.Header{"Retry-After": {"1"}}, http.StatusServiceUnavailable, http.StatusOK)
+	start := time.Now()
+	_, err = client.Get("http://test.cloud/compute/v2.1/servers/detail")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+	assert.GreaterOrEqual(t, time.Since(start), time.Second, "the Retry-After should be honored")
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = time.Now })
+
+	client, requests := newRetriedClient(t, APIRequestConfig{BreakerFailures: 2, BreakerTimeout: 30 * time.Second}, nil, 0, http.StatusInternalServerError, 0, http.StatusOK)
+	state := func(expected int) {
+		t.Helper()
+		assert.Equal(t, float64(expected), testutil.ToFloat64(apiMetrics.breakerState.WithLabelValues("mycloud", "RegionOne", "compute")))
+	}
+	get := func() error {
+		resp, err := client.Get("http://test.cloud/compute/v2.1/servers/detail")
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	assert.Error(t, get())
+	state(breakerClosed)
+	assert.NoError(t, get())
+	state(breakerOpen)
+	assert.ErrorIs(t, get(), ErrCircuitOpen)
+	assert.Equal(t, int32(2), requests.Load(), "the requests should fail fast while the breaker is open")
+
+	now = now.Add(30 * time.Second)
+	assert.Error(t, get(), "the probe should reach the service")
+	state(breakerOpen)
+	assert.ErrorIs(t, get(), ErrCircuitOpen, "the breaker should open again when the probe fails")
+
+	now = now.Add(30 * time.Second)
+	assert.NoError(t, get())
+	state(breakerClosed)
+	assert.NoError(t, get())
+	assert.Equal(t, int32(5), requests.Load())
+}
+
+func TestCircuitBreakerRegions(t *testing.T) {
+	require.NoError(t, ConfigureAPIRequests(APIRequestConfig{BreakerFailures: 1, BreakerTimeout: time.Minute}))
+	InstrumentAPIRequests("openstack")
+	t.Cleanup(func() { apiMetrics, apiRequestConfig = nil, APIRequestConfig{} })
+
+	api := newAPIInstrumentation("mycloud", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	api.addEndpoint("http://one.test.cloud/compute/v2.1", "compute", "RegionOne")
+	api.addEndpoint("http://two.test.cloud/compute/v2.1", "compute", "RegionTwo")
+	client := &http.Client{Transport: api.wrap(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Host == "one.test.cloud" {
+			return nil, errors.New("connection refused")
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	}))}
+
+	_, err := client.Get("http://one.test.cloud/compute/v2.1/servers/detail")
+	assert.Error(t, err)
+	_, err = client.Get("http://one.test.cloud/compute/v2.1/servers/detail")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.ErrorContains(t, err, "in region RegionOne")
+
+	resp, err := client.Get("http://two.test.cloud/compute/v2.1/servers/detail")
+	require.NoError(t, err, "the service down in a region should not open the breaker of the other regions")
+	resp.Body.Close()
+	assert.Equal(t, float64(breakerOpen), testutil.ToFloat64(apiMetrics.breakerState.WithLabelValues("mycloud", "RegionOne", "compute")))
+	assert.Equal(t, float64(breakerClosed), testutil.ToFloat64(apiMetrics.breakerState.WithLabelValues("mycloud", "RegionTwo", "compute")))
+}
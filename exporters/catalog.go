@@ -0,0 +1,117 @@
+package exporters
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Formats of the catalog of the metrics.
+const (
+	CatalogJSON     = "json"
+	CatalogMarkdown = "markdown"
+)
+
+// CatalogMetric describes a metric of the catalog of the metrics of the registered exporters.
+type CatalogMetric struct {
+	Service           string   `json:"service"`
+	Name              string   `json:"name"`
+	FQName            string   `json:"fq_name"`
+	Labels            []string `json:"labels"`
+	Type              string   `json:"type"`
+	Unit              string   `json:"unit,omitempty"`
+	Help              string   `json:"help"`
+	Slow              bool     `json:"slow"`
+	DeprecatedVersion string   `json:"deprecated_version,omitempty"`
+	States            []string `json:"states,omitempty"`
+}
+
+// Catalog returns the metrics of the registered exporters, in the registration order of the
+// services and the declaration order of their metrics. The labels are the default ones, without
+// the labels mapped by --extra-labels nor the region label.
+func Catalog(prefix string) []CatalogMetric {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	metrics := []CatalogMetric{}
+	for _, service := range serviceTypes {
+		catalog, ok := catalogs[service]
+		if !ok {
+			continue
+		}
+		for _, metric := range catalog.metrics {
+			help := metric.Help
+			if help == "" {
+				help = metric.Name
+			}
+			labels := metric.Labels
+			if labels == nil {
+				labels = []string{}
+			}
+			metrics = append(metrics, CatalogMetric{
+				Service:           service,
+				Name:              metric.Name,
+				FQName:            prometheus.BuildFQName(prefix, catalog.name, metric.Name),
+				Labels:            labels,
+				Type:              typeName(metric.Type),
+				Unit:              metric.Unit,
+				Help:              help,
+				Slow:              metric.Slow,
+				DeprecatedVersion: metric.DeprecatedVersion,
+				States:            metric.States,
+			})
+		}
+	}
+	return metrics
+}
+
+// typeName returns the name of a metric type, as in the TYPE lines of the text format.
+func typeName(valueType prometheus.ValueType) string {
+	switch valueType {
+	case prometheus.CounterValue:
+		return "counter"
+	case prometheus.UntypedValue:
+		return "untyped"
+	}
+	return "gauge"
+}
+
+// WriteCatalog writes the catalog of the metrics in the given format, json or markdown.
+func WriteCatalog(w io.Writer, format string, metrics []CatalogMetric) error {
+	switch format {
+	case CatalogJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(metrics)
+	case CatalogMarkdown:
+		return writeCatalogMarkdown(w, metrics)
+	}
+	return fmt.Errorf("unknown catalog format %q, must be one of %s or %s", format, CatalogJSON, CatalogMarkdown)
+}
+
+func writeCatalogMarkdown(w io.Writer, metrics []CatalogMetric) error {
+	var b strings.Builder
+	b.WriteString("| Name | Service | Type | Labels | Slow | Deprecated | Help |\n")
+	b.WriteString("|------|---------|------|--------|------|------------|------|\n")
+	for _, metric := range metrics {
+		labels := make([]string, 0, len(metric.Labels))
+		for _, label := range metric.Labels {
+			labels = append(labels, "`"+label+"`")
+		}
+		slow := ""
+		if metric.Slow {
+			slow = "yes"
+		}
+		metricType := metric.Type
+		if metric.Unit != "" {
+			metricType += " (" + metric.Unit + ")"
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s | %s | %s |\n", metric.FQName, metric.Service, metricType,
+			strings.Join(labels, ", "), slow, metric.DeprecatedVersion, strings.ReplaceAll(metric.Help, "|", `\|`))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,46 @@
+package exporters
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalog(t *testing.T) {
+	catalog := Catalog("openstack")
+
+	fqNames := map[string]CatalogMetric{}
+	for _, metric := range catalog {
+		assert.NotEqual(t, metric.Name, metric.Help, "metric %s should have a help text", metric.FQName)
+		assert.NotContains(t, fqNames, metric.FQName, "metric %s should be listed once", metric.FQName)
+		fqNames[metric.FQName] = metric
+	}
+	assert.Equal(t, "network", catalog[0].Service, "the services should be in their registration order")
+	assert.Equal(t, CatalogMetric{
+		Service: "load-balancer",
+		Name:    "stats_bytes_in_total",
+		FQName:  "openstack_loadbalancer_stats_bytes_in_total",
+		Labels:  []string{"id", "name", "project_id", "operating_status", "provisioning_status", "provider", "vip_address"},
+		Type:    "counter",
+		Unit:    "bytes",
+		Help:    "Total number of bytes received by the load balancer",
+	}, fqNames["openstack_loadbalancer_stats_bytes_in_total"])
+	assert.True(t, fqNames["openstack_glance_image_bytes"].Slow)
+	assert.Equal(t, "1.4", fqNames["openstack_cinder_volume_status"].DeprecatedVersion)
+	assert.Equal(t, "1.7", fqNames["openstack_loadbalancer_stats_bytes_in"].DeprecatedVersion)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCatalog(&buf, CatalogJSON, catalog))
+	var decoded []CatalogMetric
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, catalog, decoded)
+
+	buf.Reset()
+	require.NoError(t, WriteCatalog(&buf, CatalogMarkdown, catalog))
+	assert.Contains(t, buf.String(), "| `openstack_glance_image_bytes` | image | gauge (bytes) | `id`, `name`, `tenant_id` | yes |  | Image size in bytes |\n")
+
+	assert.ErrorContains(t, WriteCatalog(&buf, "yaml", catalog), `unknown catalog format "yaml"`)
+}
@@ -0,0 +1,131 @@
+package exporters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+)
+
+// ServiceCheck is the cheap list request made by the check of a service, and the role the exporter
+// of the service needs to collect its metrics.
+type ServiceCheck struct {
+	// Path is the path of the request, relative to the resource base of the service client.
+	Path string
+	Role string
+}
+
+// checks are the requests of the checks of the services, by service type.
+var checks = map[string]ServiceCheck{
+	"network":         {Path: "networks?limit=1", Role: "admin"},
+	"compute":         {Path: "os-services", Role: "admin"},
+	"image":           {Path: "images?limit=1", Role: "admin"},
+	"volume":          {Path: "volumes?all_tenants=1&limit=1", Role: "admin"},
+	"identity":        {Path: "projects?limit=1", Role: "admin"},
+	"object-store":    {Path: "?format=json&limit=1", Role: "reader"},
+	"load-balancer":   {Path: "lbaas/loadbalancers?limit=1", Role: "admin"},
+	"container-infra": {Path: "clusters?limit=1", Role: "admin"},
+	"dns":             {Path: "zones?limit=1", Role: "admin"},
+	"baremetal":       {Path: "nodes?limit=1", Role: "admin"},
+	"gnocchi":         {Path: "metric?limit=1", Role: "admin"},
+	"database":        {Path: "instances?limit=1", Role: "admin"},
+	"orchestration":   {Path: "stacks?limit=1", Role: "admin"},
+	"placement":       {Path: "resource_providers", Role: "admin"},
+	"sharev2":         {Path: "shares?limit=1", Role: "admin"},
+}
+
+// RegisterCheck sets the request of the check of a service type. The services without one
+// are only checked for their endpoint.
+func RegisterCheck(serviceType string, check ServiceCheck) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	checks[serviceType] = check
+}
+
+// CheckResult is the result of a step of the check of a cloud.
+type CheckResult struct {
+	// Service is the service type checked, or authentication for the authentication to the cloud.
+	Service  string
+	Endpoint string
+	Role     string
+	Latency  time.Duration
+	Err      error
+}
+
+// CheckCloud checks that the exporter can collect the services of a cloud: it authenticates to
+// the cloud, resolves the endpoint of each service and makes a cheap list request to the service.
+// When the authentication fails, the services are not checked.
+func CheckCloud(ctx context.Context, cloud, endpointType string, services []string, logger *slog.Logger) []CheckResult {
+	start := time.Now()
+	clients, err := newCloudClients(ctx, cloud, logger)
+	results := []CheckResult{{Service: "authentication", Latency: time.Since(start), Err: err}}
+	if err != nil {
+		return results
+	}
+	results[0].Endpoint = clients.provider.IdentityEndpoint
+
+	for _, service := range services {
+		results = append(results, checkService(clients, service, endpointType))
+	}
+	return results
+}
+
+// checkService checks the endpoint of a service and makes its check request.
+func checkService(clients *cloudClients, service, endpointType string) CheckResult {
+	factoriesMu.RLock()
+	check, ok := checks[service]
+	factoriesMu.RUnlock()
+	result := CheckResult{Service: service, Role: check.Role}
+
+	start := time.Now()
+	opts := clients.opts
+	client, err := NewServiceClient(service, &opts, clients.provider, endpointType)
+	if err != nil {
+		result.Latency, result.Err = time.Since(start), fmt.Errorf("no %s endpoint: %w", endpointType, err)
+		return result
+	}
+	result.Endpoint = client.Endpoint
+	clients.api.addEndpoint(client.Endpoint, service, opts.RegionName)
+	if !ok {
+		result.Latency = time.Since(start)
+		return result
+	}
+
+	_, err = client.Get(client.ServiceURL(check.Path), nil, &gophercloud.RequestOpts{OkCodes: []int{http.StatusOK, http.StatusNoContent}})
+	result.Latency = time.Since(start)
+	if err != nil {
+		result.Err = err
+		var unauthorized gophercloud.ErrDefault401
+		var forbidden gophercloud.ErrDefault403
+		if errors.As(err, &unauthorized) || errors.As(err, &forbidden) {
+			result.Err = fmt.Errorf("access denied, the exporter needs the %s role: %w", check.Role, err)
+		}
+	}
+	return result
+}
+
+// WriteCheckResults writes the results as a table, and returns false if a check failed.
+func WriteCheckResults(w io.Writer, results []CheckResult) (bool, error) {
+	passed := true
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "SERVICE\tSTATUS\tLATENCY\tROLE\tDETAIL")
+	for _, result := range results {
+		status, detail := "PASS", result.Endpoint
+		if result.Err != nil {
+			passed = false
+			status, detail = "FAIL", result.Err.Error()
+		}
+		role := result.Role
+		if role == "" {
+			role = "-"
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", result.Service, status, result.Latency.Round(time.Millisecond), role, detail)
+	}
+	return passed, table.Flush()
+}
@@ -0,0 +1,62 @@
+package exporters
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCloud(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	data, err := os.ReadFile(path.Join(baseFixturePath, "tokens.json"))
+	require.NoError(t, err)
+	httpmock.RegisterResponder("POST", "http://test.cloud:35357/v3/auth/tokens",
+		httpmock.NewBytesResponder(201, data).HeaderSet(map[string][]string{"X-Subject-Token": {"token"}}))
+	httpmock.RegisterResponder("GET", "http://test.cloud/neutron/v2.0/networks?limit=1", httpmock.NewStringResponder(200, `{"networks": []}`))
+	httpmock.RegisterResponder("GET", "http://test.cloud/compute/os-services", httpmock.NewStringResponder(403, `{"forbidden": {}}`))
+	httpmock.RegisterResponder("GET", "http://test.cloud/identity/v3/projects?limit=1", httpmock.NewStringResponder(200, `{"projects": []}`))
+	t.Setenv("OS_CLIENT_CONFIG_FILE", path.Join(baseFixturePath, "test_config.yaml"))
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	results := CheckCloud(context.Background(), cloudName, "public", []string{"network", "compute", "key-manager", "identity"}, logger)
+	require.Len(t, results, 5)
+	assert.Equal(t, "authentication", results[0].Service)
+	assert.NoError(t, results[0].Err)
+	assert.NoError(t, results[1].Err)
+	assert.Equal(t, "http://test.cloud/neutron/", results[1].Endpoint)
+	assert.ErrorContains(t, results[2].Err, "access denied, the exporter needs the admin role")
+	assert.ErrorContains(t, results[3].Err, "no public endpoint")
+	assert.NoError(t, results[4].Err, "the check of the identity should list a single project")
+
+	var buf bytes.Buffer
+	passed, err := WriteCheckResults(&buf, results)
+	require.NoError(t, err)
+	assert.False(t, passed)
+	assert.Regexp(t, `(?m)^network +PASS +\S+ +admin +http://test.cloud/neutron/$`, buf.String())
+	assert.Regexp(t, `(?m)^compute +FAIL `, buf.String())
+
+	passed, err = WriteCheckResults(&buf, results[:2])
+	require.NoError(t, err)
+	assert.True(t, passed)
+}
+
+func TestCheckCloudAuthentication(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("POST", "http://test.cloud:35357/v3/auth/tokens", httpmock.NewStringResponder(401, `{}`))
+	t.Setenv("OS_CLIENT_CONFIG_FILE", path.Join(baseFixturePath, "test_config.yaml"))
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	results := CheckCloud(context.Background(), cloudName, "public", []string{"network"}, logger)
+	require.Len(t, results, 1, "the services should not be checked when the authentication fails")
+	assert.Error(t, results[0].Err)
+}
@@ -1,6 +1,7 @@
 package exporters
 
 import (
+	"context"
 	"errors"
 	"strconv"
 	"strings"
@@ -56,22 +57,22 @@ func mapVolumeStatus(volStatus string) int {
 }
 
 var defaultCinderMetrics = []Metric{
-	{Name: "volumes", Fn: ListVolumes},
-	{Name: "snapshots", Fn: ListSnapshots},
-	{Name: "agent_state", Labels: []string{"uuid", "hostname", "service", "adminState", "zone", "disabledReason"}, Fn: ListCinderAgentState},
-	{Name: "volume_gb", Labels: []string{"id", "name", "status", "availability_zone", "bootable", "tenant_id", "user_id", "volume_type", "server_id"}, Fn: nil},
-	{Name: "volume_status", Labels: []string{"id", "name", "status", "bootable", "tenant_id", "size", "volume_type", "server_id"}, Fn: ListVolumesStatus, Slow: false, DeprecatedVersion: "1.4"},
-	{Name: "volume_status_counter", Labels: []string{"status"}, Fn: nil},
-	{Name: "pool_capacity_free_gb", Labels: []string{"name", "volume_backend_name", "vendor_name"}, Fn: ListCinderPoolCapacityFree},
-	{Name: "pool_capacity_total_gb", Labels: []string{"name", "volume_backend_name", "vendor_name"}, Fn: nil},
-	{Name: "limits_volume_max_gb", Labels: []string{"tenant", "tenant_id"}, Fn: ListVolumeLimits, Slow: true},
-	{Name: "limits_volume_used_gb", Labels: []string{"tenant", "tenant_id"}, Fn: nil, Slow: true},
-	{Name: "limits_backup_max_gb", Labels: []string{"tenant", "tenant_id"}, Fn: nil, Slow: true},
-	{Name: "limits_backup_used_gb", Labels: []string{"tenant", "tenant_id"}, Fn: nil, Slow: true},
-	{Name: "volume_type_quota_gigabytes", Labels: []string{"tenant", "tenant_id", "volume_type"}, Fn: nil, Slow: true},
+	{Name: "volumes", Fn: ListVolumes, Help: "Total number of volumes", Type: prometheus.GaugeValue},
+	{Name: "volume_gb", Labels: []string{"id", "name", "status", "availability_zone", "bootable", "tenant_id", "user_id", "volume_type", "server_id"}, Fn: nil, Help: "Volume size in GB", Type: prometheus.GaugeValue, Unit: "gigabytes"},
+	{Name: "volume_status_counter", Labels: []string{"status"}, Fn: nil, Help: "Number of volumes by status", Type: prometheus.GaugeValue},
+	{Name: "snapshots", Fn: ListSnapshots, Help: "Total number of snapshots", Type: prometheus.GaugeValue},
+	{Name: "agent_state", Labels: []string{"uuid", "hostname", "service", "adminState", "zone", "disabledReason"}, Fn: ListCinderAgentState, Help: "Agent state (1=up, 0=down)", Type: prometheus.GaugeValue},
+	{Name: "volume_status", Labels: []string{"id", "name", "status", "bootable", "tenant_id", "size", "volume_type", "server_id"}, Fn: ListVolumesStatus, Slow: false, DeprecatedVersion: "1.4", States: volume_status, Help: "Volume status, the index of the status in the known volume statuses", Type: prometheus.GaugeValue},
+	{Name: "pool_capacity_free_gb", Labels: []string{"name", "volume_backend_name", "vendor_name"}, Fn: ListCinderPoolCapacityFree, Help: "Free capacity of the storage pool in GB", Type: prometheus.GaugeValue, Unit: "gigabytes"},
+	{Name: "pool_capacity_total_gb", Labels: []string{"name", "volume_backend_name", "vendor_name"}, Fn: nil, Help: "Total capacity of the storage pool in GB", Type: prometheus.GaugeValue, Unit: "gigabytes"},
+	{Name: "limits_volume_max_gb", Labels: []string{"tenant", "tenant_id"}, Fn: ListVolumeLimits, Slow: true, Help: "Maximum volume size limit of the tenant in GB", Type: prometheus.GaugeValue, Unit: "gigabytes"},
+	{Name: "limits_volume_used_gb", Labels: []string{"tenant", "tenant_id"}, Fn: nil, Slow: true, Help: "Used volume size of the tenant in GB", Type: prometheus.GaugeValue, Unit: "gigabytes"},
+	{Name: "limits_backup_max_gb", Labels: []string{"tenant", "tenant_id"}, Fn: nil, Slow: true, Help: "Maximum backup size limit of the tenant in GB", Type: prometheus.GaugeValue, Unit: "gigabytes"},
+	{Name: "limits_backup_used_gb", Labels: []string{"tenant", "tenant_id"}, Fn: nil, Slow: true, Help: "Used backup size of the tenant in GB", Type: prometheus.GaugeValue, Unit: "gigabytes"},
+	{Name: "volume_type_quota_gigabytes", Labels: []string{"tenant", "tenant_id", "volume_type"}, Fn: nil, Slow: true, Help: "Volume size quota of the tenant for the volume type in GB", Type: prometheus.GaugeValue, Unit: "gigabytes"},
 }
 
-func NewCinderExporter(config *ExporterConfig, logger *slog.Logger) (*CinderExporter, error) {
+func NewCinderExporter(ctx context.Context, config *ExporterConfig, logger *slog.Logger) (*CinderExporter, error) {
 	exporter := CinderExporter{
 		BaseOpenStackExporter{
 			Name:           "cinder",
@@ -81,18 +82,25 @@ func NewCinderExporter(config *ExporterConfig, logger *slog.Logger) (*CinderExpo
 	}
 
 	for _, metric := range defaultCinderMetrics {
+		if metric.Name == "volume_gb" || metric.Name == "volume_status" {
+			labels, err := exporter.mappedLabels("cinder.volume", metric.Labels)
+			if err != nil {
+				return nil, err
+			}
+			metric.Labels = labels
+		}
 		if exporter.isDeprecatedMetric(&metric) {
 			continue
 		}
 		if !exporter.isSlowMetric(&metric) {
-			exporter.AddMetric(metric.Name, metric.Fn, metric.Labels, metric.DeprecatedVersion, nil)
+			exporter.addMetric(metric, nil)
 		}
 	}
 
 	return &exporter, nil
 }
 
-func ListVolumesStatus(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListVolumesStatus(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	type VolumeWithExt struct {
 		volumes.Volume
 		volumetenants.VolumeTenantExt
@@ -119,20 +127,21 @@ func ListVolumesStatus(exporter *BaseOpenStackExporter, ch chan<- prometheus.Met
 
 	// Volume status metrics
 	for _, volume := range allVolumes {
+		metadataValues := exporter.LabelMappings.Get("cinder.volume").Extract(volume.Metadata)
 		if len(volume.Attachments) > 0 {
-			ch <- prometheus.MustNewConstMetric(exporter.Metrics["volume_status"].Metric,
-				prometheus.GaugeValue, float64(mapVolumeStatus(volume.Status)), volume.ID, volume.Name,
-				volume.Status, volume.Bootable, volume.TenantID, strconv.Itoa(volume.Size), volume.VolumeType, volume.Attachments[0].ServerID)
+			exporter.SendStatus(ch, "volume_status", volume_status, mapVolumeStatus(volume.Status), volume.Status,
+				append([]string{volume.ID, volume.Name,
+					volume.Status, volume.Bootable, volume.TenantID, strconv.Itoa(volume.Size), volume.VolumeType, volume.Attachments[0].ServerID}, metadataValues...)...)
 		} else {
-			ch <- prometheus.MustNewConstMetric(exporter.Metrics["volume_status"].Metric,
-				prometheus.GaugeValue, float64(mapVolumeStatus(volume.Status)), volume.ID, volume.Name,
-				volume.Status, volume.Bootable, volume.TenantID, strconv.Itoa(volume.Size), volume.VolumeType, "")
+			exporter.SendStatus(ch, "volume_status", volume_status, mapVolumeStatus(volume.Status), volume.Status,
+				append([]string{volume.ID, volume.Name,
+					volume.Status, volume.Bootable, volume.TenantID, strconv.Itoa(volume.Size), volume.VolumeType, ""}, metadataValues...)...)
 		}
 	}
 	return nil
 }
 
-func ListVolumes(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListVolumes(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	type VolumeWithExt struct {
 		volumes.Volume
 		volumetenants.VolumeTenantExt
@@ -153,18 +162,19 @@ func ListVolumes(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) e
 	}
 
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["volumes"].Metric,
-		prometheus.GaugeValue, float64(len(allVolumes)))
+		exporter.Metrics["volumes"].Type, float64(len(allVolumes)))
 
 	// Volume_gb metrics
 	for _, volume := range allVolumes {
+		metadataValues := exporter.LabelMappings.Get("cinder.volume").Extract(volume.Metadata)
 		if len(volume.Attachments) > 0 {
 			ch <- prometheus.MustNewConstMetric(exporter.Metrics["volume_gb"].Metric,
-				prometheus.GaugeValue, float64(volume.Size), volume.ID, volume.Name,
-				volume.Status, volume.AvailabilityZone, volume.Bootable, volume.TenantID, volume.UserID, volume.VolumeType, volume.Attachments[0].ServerID)
+				exporter.Metrics["volume_gb"].Type, float64(volume.Size), append([]string{volume.ID, volume.Name,
+					volume.Status, volume.AvailabilityZone, volume.Bootable, volume.TenantID, volume.UserID, volume.VolumeType, volume.Attachments[0].ServerID}, metadataValues...)...)
 		} else {
 			ch <- prometheus.MustNewConstMetric(exporter.Metrics["volume_gb"].Metric,
-				prometheus.GaugeValue, float64(volume.Size), volume.ID, volume.Name,
-				volume.Status, volume.AvailabilityZone, volume.Bootable, volume.TenantID, volume.UserID, volume.VolumeType, "")
+				exporter.Metrics["volume_gb"].Type, float64(volume.Size), append([]string{volume.ID, volume.Name,
+					volume.Status, volume.AvailabilityZone, volume.Bootable, volume.TenantID, volume.UserID, volume.VolumeType, ""}, metadataValues...)...)
 		}
 	}
 
@@ -199,7 +209,7 @@ func ListVolumes(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) e
 	for status, count := range volume_status_counter {
 		ch <- prometheus.MustNewConstMetric(
 			exporter.Metrics["volume_status_counter"].Metric,
-			prometheus.GaugeValue,
+			exporter.Metrics["volume_status_counter"].Type,
 			float64(count),
 			status)
 	}
@@ -207,7 +217,7 @@ func ListVolumes(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) e
 	return nil
 }
 
-func ListSnapshots(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListSnapshots(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allSnapshots []snapshots.Snapshot
 
 	allPagesSnapshot, err := snapshots.List(exporter.Client, snapshots.ListOpts{AllTenants: true}).AllPages()
@@ -221,12 +231,12 @@ func ListSnapshots(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric)
 	}
 
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["snapshots"].Metric,
-		prometheus.GaugeValue, float64(len(allSnapshots)))
+		exporter.Metrics["snapshots"].Type, float64(len(allSnapshots)))
 
 	return nil
 }
 
-func ListCinderAgentState(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListCinderAgentState(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 
 	var allServices []services.Service
 
@@ -253,13 +263,13 @@ func ListCinderAgentState(exporter *BaseOpenStackExporter, ch chan<- prometheus.
 		}
 
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["agent_state"].Metric,
-			prometheus.CounterValue, float64(state), id, service.Host, service.Binary, service.Status, service.Zone, service.DisabledReason)
+			exporter.Metrics["agent_state"].Type, float64(state), id, service.Host, service.Binary, service.Status, service.Zone, service.DisabledReason)
 	}
 
 	return nil
 }
 
-func ListCinderPoolCapacityFree(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListCinderPoolCapacityFree(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	listOpts := schedulerstats.ListOpts{
 		Detail: true,
 	}
@@ -275,15 +285,15 @@ func ListCinderPoolCapacityFree(exporter *BaseOpenStackExporter, ch chan<- prome
 	}
 
 	for _, stat := range allStats {
-		ch <- prometheus.MustNewConstMetric(exporter.Metrics["pool_capacity_free_gb"].Metric, prometheus.GaugeValue,
+		ch <- prometheus.MustNewConstMetric(exporter.Metrics["pool_capacity_free_gb"].Metric, exporter.Metrics["pool_capacity_free_gb"].Type,
 			float64(stat.Capabilities.FreeCapacityGB), stat.Name, stat.Capabilities.VolumeBackendName, stat.Capabilities.VendorName)
-		ch <- prometheus.MustNewConstMetric(exporter.Metrics["pool_capacity_total_gb"].Metric, prometheus.GaugeValue,
+		ch <- prometheus.MustNewConstMetric(exporter.Metrics["pool_capacity_total_gb"].Metric, exporter.Metrics["pool_capacity_total_gb"].Type,
 			float64(stat.Capabilities.TotalCapacityGB), stat.Name, stat.Capabilities.VolumeBackendName, stat.Capabilities.VendorName)
 	}
 	return nil
 }
 
-func ListVolumeLimits(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListVolumeLimits(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allProjects []projects.Project
 	var eo gophercloud.EndpointOpts
 
@@ -303,12 +313,7 @@ func ListVolumeLimits(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metr
 		return err
 	}
 
-	allPagesProject, err := projects.List(c, projects.ListOpts{DomainID: exporter.DomainID}).AllPages()
-	if err != nil {
-		return err
-	}
-
-	allProjects, err = projects.ExtractProjects(allPagesProject)
+	allProjects, err = listProjects(ctx, c, exporter.DomainID)
 	if err != nil {
 		return err
 	}
@@ -333,22 +338,22 @@ func ListVolumeLimits(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metr
 				volumeType := strings.TrimPrefix(key, "gigabytes_")
 				if quotaValue, ok := value.(float64); ok {
 					ch <- prometheus.MustNewConstMetric(exporter.Metrics["volume_type_quota_gigabytes"].Metric,
-						prometheus.GaugeValue, quotaValue, p.Name, p.ID, volumeType)
+						exporter.Metrics["volume_type_quota_gigabytes"].Type, quotaValue, p.Name, p.ID, volumeType)
 				}
 			}
 		}
 
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["limits_volume_max_gb"].Metric,
-			prometheus.GaugeValue, float64(limits.Gigabytes.Limit), p.Name, p.ID)
+			exporter.Metrics["limits_volume_max_gb"].Type, float64(limits.Gigabytes.Limit), p.Name, p.ID)
 
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["limits_volume_used_gb"].Metric,
-			prometheus.GaugeValue, float64(limits.Gigabytes.InUse), p.Name, p.ID)
+			exporter.Metrics["limits_volume_used_gb"].Type, float64(limits.Gigabytes.InUse), p.Name, p.ID)
 
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["limits_backup_max_gb"].Metric,
-			prometheus.GaugeValue, float64(limits.BackupGigabytes.Limit), p.Name, p.ID)
+			exporter.Metrics["limits_backup_max_gb"].Type, float64(limits.BackupGigabytes.Limit), p.Name, p.ID)
 
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["limits_backup_used_gb"].Metric,
-			prometheus.GaugeValue, float64(limits.BackupGigabytes.InUse), p.Name, p.ID)
+			exporter.Metrics["limits_backup_used_gb"].Type, float64(limits.BackupGigabytes.InUse), p.Name, p.ID)
 	}
 
 	return nil
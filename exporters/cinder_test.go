@@ -12,12 +12,20 @@ type CinderTestSuite struct {
 }
 
 var cinderExpectedUp = `
-# HELP openstack_cinder_agent_state agent_state
-# TYPE openstack_cinder_agent_state counter
+# HELP openstack_collector_success Whether the last collection of the metric from OpenStack API succeeded
+# TYPE openstack_collector_success gauge
+openstack_collector_success{metric="agent_state",service="cinder"} 1
+openstack_collector_success{metric="limits_volume_max_gb",service="cinder"} 1
+openstack_collector_success{metric="pool_capacity_free_gb",service="cinder"} 1
+openstack_collector_success{metric="snapshots",service="cinder"} 1
+openstack_collector_success{metric="volume_status",service="cinder"} 1
+openstack_collector_success{metric="volumes",service="cinder"} 1
+# HELP openstack_cinder_agent_state Agent state (1=up, 0=down)
+# TYPE openstack_cinder_agent_state gauge
 openstack_cinder_agent_state{adminState="enabled",disabledReason="",hostname="devstack@lvmdriver-1",service="cinder-volume",uuid="3649e0f6-de80-ab6e-4f1c-351042d2f7fe",zone="nova"} 1
 openstack_cinder_agent_state{adminState="enabled",disabledReason="Test1",hostname="devstack",service="cinder-scheduler",uuid="3649e0f6-de80-ab6e-4f1c-351042d2f7fe",zone="nova"} 1
 openstack_cinder_agent_state{adminState="enabled",disabledReason="Test2",hostname="devstack",service="cinder-backup",uuid="3649e0f6-de80-ab6e-4f1c-351042d2f7fe",zone="nova"} 1
-# HELP openstack_cinder_limits_backup_max_gb limits_backup_max_gb
+# HELP openstack_cinder_limits_backup_max_gb Maximum backup size limit of the tenant in GB
 # TYPE openstack_cinder_limits_backup_max_gb gauge
 openstack_cinder_limits_backup_max_gb{tenant="admin",tenant_id="0c4e939acacf4376bdcd1129f1a054ad"} 1000
 openstack_cinder_limits_backup_max_gb{tenant="alt_demo",tenant_id="fdb8424c4e4f4c0ba32c52e2de3bd80e"} 1000
@@ -27,7 +35,7 @@ openstack_cinder_limits_backup_max_gb{tenant="service",tenant_id="3d594eb0f04741
 openstack_cinder_limits_backup_max_gb{tenant="swifttenanttest1",tenant_id="43ebde53fc314b1c9ea2b8c5dc744927"} 1000
 openstack_cinder_limits_backup_max_gb{tenant="swifttenanttest2",tenant_id="2db68fed84324f29bb73130c6c2094fb"} 1000
 openstack_cinder_limits_backup_max_gb{tenant="swifttenanttest4",tenant_id="4b1eb781a47440acb8af9850103e537f"} 1000
-# HELP openstack_cinder_limits_backup_used_gb limits_backup_used_gb
+# HELP openstack_cinder_limits_backup_used_gb Used backup size of the tenant in GB
 # TYPE openstack_cinder_limits_backup_used_gb gauge
 openstack_cinder_limits_backup_used_gb{tenant="admin",tenant_id="0c4e939acacf4376bdcd1129f1a054ad"} 0
 openstack_cinder_limits_backup_used_gb{tenant="alt_demo",tenant_id="fdb8424c4e4f4c0ba32c52e2de3bd80e"} 0
@@ -37,7 +45,7 @@ openstack_cinder_limits_backup_used_gb{tenant="service",tenant_id="3d594eb0f0474
 openstack_cinder_limits_backup_used_gb{tenant="swifttenanttest1",tenant_id="43ebde53fc314b1c9ea2b8c5dc744927"} 0
 openstack_cinder_limits_backup_used_gb{tenant="swifttenanttest2",tenant_id="2db68fed84324f29bb73130c6c2094fb"} 0
 openstack_cinder_limits_backup_used_gb{tenant="swifttenanttest4",tenant_id="4b1eb781a47440acb8af9850103e537f"} 0
-# HELP openstack_cinder_limits_volume_max_gb limits_volume_max_gb
+# HELP openstack_cinder_limits_volume_max_gb Maximum volume size limit of the tenant in GB
 # TYPE openstack_cinder_limits_volume_max_gb gauge
 openstack_cinder_limits_volume_max_gb{tenant="admin",tenant_id="0c4e939acacf4376bdcd1129f1a054ad"} 1000
 openstack_cinder_limits_volume_max_gb{tenant="alt_demo",tenant_id="fdb8424c4e4f4c0ba32c52e2de3bd80e"} 1000
@@ -47,7 +55,7 @@ openstack_cinder_limits_volume_max_gb{tenant="service",tenant_id="3d594eb0f04741
 openstack_cinder_limits_volume_max_gb{tenant="swifttenanttest1",tenant_id="43ebde53fc314b1c9ea2b8c5dc744927"} 1000
 openstack_cinder_limits_volume_max_gb{tenant="swifttenanttest2",tenant_id="2db68fed84324f29bb73130c6c2094fb"} 1000
 openstack_cinder_limits_volume_max_gb{tenant="swifttenanttest4",tenant_id="4b1eb781a47440acb8af9850103e537f"} 1000
-# HELP openstack_cinder_limits_volume_used_gb limits_volume_used_gb
+# HELP openstack_cinder_limits_volume_used_gb Used volume size of the tenant in GB
 # TYPE openstack_cinder_limits_volume_used_gb gauge
 openstack_cinder_limits_volume_used_gb{tenant="admin",tenant_id="0c4e939acacf4376bdcd1129f1a054ad"} 0
 openstack_cinder_limits_volume_used_gb{tenant="alt_demo",tenant_id="fdb8424c4e4f4c0ba32c52e2de3bd80e"} 0
@@ -57,27 +65,27 @@ openstack_cinder_limits_volume_used_gb{tenant="service",tenant_id="3d594eb0f0474
 openstack_cinder_limits_volume_used_gb{tenant="swifttenanttest1",tenant_id="43ebde53fc314b1c9ea2b8c5dc744927"} 0
 openstack_cinder_limits_volume_used_gb{tenant="swifttenanttest2",tenant_id="2db68fed84324f29bb73130c6c2094fb"} 0
 openstack_cinder_limits_volume_used_gb{tenant="swifttenanttest4",tenant_id="4b1eb781a47440acb8af9850103e537f"} 0
-# HELP openstack_cinder_pool_capacity_free_gb pool_capacity_free_gb
+# HELP openstack_cinder_pool_capacity_free_gb Free capacity of the storage pool in GB
 # TYPE openstack_cinder_pool_capacity_free_gb gauge
 openstack_cinder_pool_capacity_free_gb{name="i666testhost@FastPool01",vendor_name="EMC",volume_backend_name="VNX_Pool"} 636.316
-# HELP openstack_cinder_pool_capacity_total_gb pool_capacity_total_gb
+# HELP openstack_cinder_pool_capacity_total_gb Total capacity of the storage pool in GB
 # TYPE openstack_cinder_pool_capacity_total_gb gauge
 openstack_cinder_pool_capacity_total_gb{name="i666testhost@FastPool01",vendor_name="EMC",volume_backend_name="VNX_Pool"} 1692.429
-# HELP openstack_cinder_snapshots snapshots
+# HELP openstack_cinder_snapshots Total number of snapshots
 # TYPE openstack_cinder_snapshots gauge
 openstack_cinder_snapshots 1
-# HELP openstack_cinder_up up
+# HELP openstack_cinder_up Whether the OpenStack API of the service is up (1=up, 0=down)
 # TYPE openstack_cinder_up gauge
 openstack_cinder_up 1
-# HELP openstack_cinder_volume_gb volume_gb
+# HELP openstack_cinder_volume_gb Volume size in GB
 # TYPE openstack_cinder_volume_gb gauge
 openstack_cinder_volume_gb{availability_zone="nova",bootable="false",id="6edbc2f4-1507-44f8-ac0d-eed1d2608d38",name="test-volume-attachments",server_id="f4fda93b-06e0-4743-8117-bc8bcecd651b",status="in-use",tenant_id="bab7d5c60cd041a0a36f7c4b6e1dd978",user_id="32779452fcd34ae1a53a797ac8a1e064",volume_type="lvmdriver-1"} 2
 openstack_cinder_volume_gb{availability_zone="nova",bootable="true",id="173f7b48-c4c1-4e70-9acc-086b39073506",name="test-volume",server_id="",status="available",tenant_id="bab7d5c60cd041a0a36f7c4b6e1dd978",user_id="32779452fcd34ae1a53a797ac8a1e064",volume_type="lvmdriver-1"} 1
-# HELP openstack_cinder_volume_status volume_status
+# HELP openstack_cinder_volume_status Volume status, the index of the status in the known volume statuses
 # TYPE openstack_cinder_volume_status gauge
 openstack_cinder_volume_status{bootable="false",id="6edbc2f4-1507-44f8-ac0d-eed1d2608d38",name="test-volume-attachments",server_id="f4fda93b-06e0-4743-8117-bc8bcecd651b",size="2",status="in-use",tenant_id="bab7d5c60cd041a0a36f7c4b6e1dd978",volume_type="lvmdriver-1"} 5
 openstack_cinder_volume_status{bootable="true",id="173f7b48-c4c1-4e70-9acc-086b39073506",name="test-volume",server_id="",size="1",status="available",tenant_id="bab7d5c60cd041a0a36f7c4b6e1dd978",volume_type="lvmdriver-1"} 1
-# HELP openstack_cinder_volume_status_counter volume_status_counter
+# HELP openstack_cinder_volume_status_counter Number of volumes by status
 # TYPE openstack_cinder_volume_status_counter gauge
 openstack_cinder_volume_status_counter{status="attaching"} 0
 openstack_cinder_volume_status_counter{status="available"} 1
@@ -99,7 +107,7 @@ openstack_cinder_volume_status_counter{status="reserved"} 0
 openstack_cinder_volume_status_counter{status="restoring-backup"} 0
 openstack_cinder_volume_status_counter{status="retyping"} 0
 openstack_cinder_volume_status_counter{status="uploading"} 0
-# HELP openstack_cinder_volume_type_quota_gigabytes volume_type_quota_gigabytes
+# HELP openstack_cinder_volume_type_quota_gigabytes Volume size quota of the tenant for the volume type in GB
 # TYPE openstack_cinder_volume_type_quota_gigabytes gauge
 openstack_cinder_volume_type_quota_gigabytes{tenant="admin",tenant_id="0c4e939acacf4376bdcd1129f1a054ad",volume_type="lvmdriver-1"} 1000
 openstack_cinder_volume_type_quota_gigabytes{tenant="alt_demo",tenant_id="fdb8424c4e4f4c0ba32c52e2de3bd80e",volume_type="lvmdriver-1"} 1000
@@ -109,7 +117,7 @@ openstack_cinder_volume_type_quota_gigabytes{tenant="service",tenant_id="3d594eb
 openstack_cinder_volume_type_quota_gigabytes{tenant="swifttenanttest1",tenant_id="43ebde53fc314b1c9ea2b8c5dc744927",volume_type="lvmdriver-1"} 1000
 openstack_cinder_volume_type_quota_gigabytes{tenant="swifttenanttest2",tenant_id="2db68fed84324f29bb73130c6c2094fb",volume_type="lvmdriver-1"} 1000
 openstack_cinder_volume_type_quota_gigabytes{tenant="swifttenanttest4",tenant_id="4b1eb781a47440acb8af9850103e537f",volume_type="lvmdriver-1"} 1000
-# HELP openstack_cinder_volumes volumes
+# HELP openstack_cinder_volumes Total number of volumes
 # TYPE openstack_cinder_volumes gauge
 openstack_cinder_volumes 2
 `
@@ -0,0 +1,302 @@
+package exporters
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/gophercloud/gophercloud"
+	tokens2 "github.com/gophercloud/gophercloud/openstack/identity/v2/tokens"
+	tokens3 "github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
+	gophercloudv2 "github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/utils/openstack/clientconfig"
+	clientconfigv2 "github.com/gophercloud/utils/v2/openstack/clientconfig"
+	"github.com/openstack-exporter/openstack-exporter/utils"
+)
+
+// cloudClients are the authenticated provider clients of a cloud, shared by all the service clients of the cloud.
+type cloudClients struct {
+	opts       clientconfig.ClientOpts
+	optsV2     clientconfigv2.ClientOpts
+	provider   *gophercloud.ProviderClient
+	providerV2 *gophercloudv2.ProviderClient
+	// region is the region set by clouds.yaml or OS_REGION_NAME, if any.
+	region string
+	// api instruments the requests of the provider clients, nil if they are not instrumented.
+	api *apiInstrumentation
+}
+
+// sharedCloud holds the provider clients and the exporters of a cloud kept across scrapes.
+type sharedCloud struct {
+	mu      sync.Mutex
+	clients *cloudClients
+	// authenticating is closed once the authentication of the provider clients in progress ends,
+	// nil when none is in progress.
+	authenticating chan struct{}
+	exporters      map[string]sharedExporter
+}
+
+// sharedExporter is an exporter kept across scrapes, with the options it was built with.
+type sharedExporter struct {
+	options  ExporterOptions
+	exporter OpenStackExporter
+}
+
+// ExporterOptions are the options the exporter of a service is built with by EnableExporter and
+// NewExporter. A shared exporter is rebuilt when they change, i.e: when the configuration is reloaded.
+type ExporterOptions struct {
+	Service string
+	Prefix  string
+	Cloud   string
+	// Region selects the endpoints of the region when not empty, and is added as a region label to the metrics.
+	Region                   string
+	DisabledMetrics          []string
+	EndpointType             string
+	CollectTime              bool
+	DisableSlowMetrics       bool
+	DisableDeprecatedMetrics bool
+	DisableCinderAgentUUID   bool
+	StateSetStatus           bool
+	DomainID                 string
+	TenantID                 string
+	LabelMappings            *utils.ResourceLabelMappingFlag
+	MetricFilter             *utils.MetricFilter
+	CollectConcurrency       int
+	CloudCollectConcurrency  int
+	// UUIDGenFunc generates the UUIDs of the exporter, uuid.GenerateUUID when nil. It is not compared
+	// by equal, only the tests set it.
+	UUIDGenFunc func() (string, error)
+}
+
+// equal returns true when the exporters built with o and other are the same.
+func (o ExporterOptions) equal(other ExporterOptions) bool {
+	return o.Service == other.Service &&
+		o.Prefix == other.Prefix &&
+		o.Cloud == other.Cloud &&
+		o.Region == other.Region &&
+		slices.Equal(o.DisabledMetrics, other.DisabledMetrics) &&
+		o.EndpointType == other.EndpointType &&
+		o.CollectTime == other.CollectTime &&
+		o.DisableSlowMetrics == other.DisableSlowMetrics &&
+		o.DisableDeprecatedMetrics == other.DisableDeprecatedMetrics &&
+		o.DisableCinderAgentUUID == other.DisableCinderAgentUUID &&
+		o.StateSetStatus == other.StateSetStatus &&
+		o.DomainID == other.DomainID &&
+		o.TenantID == other.TenantID &&
+		o.LabelMappings.Clone().String() == other.LabelMappings.Clone().String() &&
+		o.MetricFilter.Equal(other.MetricFilter) &&
+		o.CollectConcurrency == other.CollectConcurrency &&
+		o.CloudCollectConcurrency == other.CloudCollectConcurrency
+}
+
+var (
+	sharedClouds         = make(map[string]*sharedCloud)
+	sharedCloudsChecksum [sha256.Size]byte
+	sharedCloudsMu       sync.Mutex
+)
+
+// RefreshClouds drops the exporters and the provider clients of every cloud when the content of
+// clouds.yaml changed since the previous call, so they are rebuilt from the new configuration.
+// It is called once at the beginning of each scrape.
+func RefreshClouds(logger *slog.Logger) {
+	_, content, err := clientconfig.FindAndReadCloudsYAML()
+	if err != nil {
+		return
+	}
+	checksum := sha256.Sum256(content)
+
+	sharedCloudsMu.Lock()
+	defer sharedCloudsMu.Unlock()
+	if checksum != sharedCloudsChecksum {
+		if len(sharedClouds) > 0 {
+			logger.Info("Cloud configuration changed, rebuilding the exporters")
+		}
+		sharedClouds = make(map[string]*sharedCloud)
+		sharedCloudsChecksum = checksum
+	}
+}
+
+// getSharedCloud returns the shared state of a cloud.
+func getSharedCloud(cloud string) *sharedCloud {
+	sharedCloudsMu.Lock()
+	defer sharedCloudsMu.Unlock()
+
+	shared, ok := sharedClouds[cloud]
+	if !ok {
+		shared = &sharedCloud{exporters: make(map[string]sharedExporter)}
+		sharedClouds[cloud] = shared
+	}
+	return shared
+}
+
+// ResetExporters drops the exporters and the provider clients kept across scrapes,
+// the next EnableExporter calls authenticate again and rebuild them.
+func ResetExporters() {
+	sharedCloudsMu.Lock()
+	defer sharedCloudsMu.Unlock()
+	sharedClouds = make(map[string]*sharedCloud)
+}
+
+// cloudClients returns the provider clients of the cloud, authenticating them on the first call.
+// The authentication doesn't hold s.mu, so the exporters already built are not blocked by a slow
+// Keystone. The concurrent callers wait for the authentication in progress, or for ctx to be done,
+// and authenticate again if it failed.
+func (s *sharedCloud) cloudClients(ctx context.Context, cloud string, logger *slog.Logger) (*cloudClients, error) {
+	for {
+		s.mu.Lock()
+		if clients := s.clients; clients != nil {
+			s.mu.Unlock()
+			return clients, nil
+		}
+		if s.authenticating == nil {
+			done := make(chan struct{})
+			s.authenticating = done
+			s.mu.Unlock()
+
+			clients, err := newCloudClients(ctx, cloud, logger)
+
+			s.mu.Lock()
+			if err == nil {
+				s.clients = clients
+			}
+			s.authenticating = nil
+			s.mu.Unlock()
+			close(done)
+			return clients, err
+		}
+		authenticating := s.authenticating
+		s.mu.Unlock()
+
+		select {
+		case <-authenticating:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// EnableExporter returns the exporter of a service for a cloud. The exporter and the authenticated
+// provider clients of the cloud are created once and reused by the next calls, the provider clients
+// re-authenticate by themselves once their token expires. The exporter is rebuilt when it is enabled
+// with other options.
+func EnableExporter(ctx context.Context, options ExporterOptions, logger *slog.Logger) (*OpenStackExporter, error) {
+	key := options.Region + "/" + options.Service
+	shared := getSharedCloud(options.Cloud)
+	shared.mu.Lock()
+	cached, ok := shared.exporters[key]
+	shared.mu.Unlock()
+	if ok && cached.options.equal(options) {
+		return &cached.exporter, nil
+	}
+
+	clients, err := shared.cloudClients(ctx, options.Cloud, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	exporter, err := newExporter(ctx, clients, options, logger)
+	if err != nil {
+		return nil, err
+	}
+	shared.mu.Lock()
+	shared.exporters[key] = sharedExporter{options: options, exporter: exporter}
+	shared.mu.Unlock()
+	return &exporter, nil
+}
+
+// CollectedRegions returns the regions to collect the services of a cloud from, given to EnableExporter.
+// With multiRegion, these are all the regions of the Keystone catalog having endpoints of endpointType.
+// With regionLabel only, this is the region set by clouds.yaml or OS_REGION_NAME, or the only region
+// of the catalog. Otherwise, a single empty region collects the services without region label.
+func CollectedRegions(ctx context.Context, cloud string, multiRegion, regionLabel bool, endpointType string, logger *slog.Logger) ([]string, error) {
+	if !multiRegion && !regionLabel {
+		return []string{""}, nil
+	}
+
+	clients, err := getSharedCloud(cloud).cloudClients(ctx, cloud, logger)
+	if err != nil {
+		return nil, err
+	}
+	if !multiRegion && clients.region != "" {
+		return []string{clients.region}, nil
+	}
+
+	regions, err := catalogRegions(clients.provider, endpointType)
+	if err != nil {
+		return nil, err
+	}
+	if len(regions) == 0 {
+		return nil, fmt.Errorf("no region found in the catalog of cloud %s", cloud)
+	}
+	if !multiRegion && len(regions) > 1 {
+		return nil, fmt.Errorf("cloud %s has several regions %v, set its region_name to label its metrics", cloud, regions)
+	}
+	return regions, nil
+}
+
+// catalogRegions returns the sorted regions of the endpoints of endpointType in the catalog of the
+// token of the provider client.
+func catalogRegions(provider *gophercloud.ProviderClient, endpointType string) ([]string, error) {
+	availability := GetEndpointType(endpointType)
+	var regions []string
+
+	switch result := provider.GetAuthResult().(type) {
+	case interface {
+		ExtractServiceCatalog() (*tokens3.ServiceCatalog, error)
+	}:
+		catalog, err := result.ExtractServiceCatalog()
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range catalog.Entries {
+			for _, endpoint := range entry.Endpoints {
+				if endpoint.Interface != string(availability) {
+					continue
+				}
+				region := endpoint.RegionID
+				if region == "" {
+					region = endpoint.Region
+				}
+				regions = append(regions, region)
+			}
+		}
+	case interface {
+		ExtractServiceCatalog() (*tokens2.ServiceCatalog, error)
+	}:
+		catalog, err := result.ExtractServiceCatalog()
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range catalog.Entries {
+			for _, endpoint := range entry.Endpoints {
+				regions = append(regions, endpoint.Region)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unable to read the service catalog from the authentication result %T", result)
+	}
+
+	regions = slices.DeleteFunc(regions, func(region string) bool { return region == "" })
+	slices.Sort(regions)
+	return slices.Compact(regions), nil
+}
+
+// cloudRegion returns the region set for a cloud, by order of precedence in the client options,
+// in the clouds.yaml entry of the cloud or in the OS_REGION_NAME environment variable.
+func cloudRegion(opts *clientconfig.ClientOpts, cloud *clientconfig.Cloud) string {
+	if opts.RegionName != "" {
+		return opts.RegionName
+	}
+	if cloud.RegionName != "" {
+		return cloud.RegionName
+	}
+	envPrefix := "OS_"
+	if opts.EnvPrefix != "" {
+		envPrefix = opts.EnvPrefix
+	}
+	return os.Getenv(envPrefix + "REGION_NAME")
+}
@@ -0,0 +1,148 @@
+package exporters
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Reasons used to classify the errors of a metric collection.
+const (
+	errorReasonUnauthorized = "unauthorized"
+	errorReasonForbidden    = "forbidden"
+	errorReasonNotFound     = "not_found"
+	errorReasonServerError  = "server_error"
+	errorReasonTimeout      = "timeout"
+	errorReasonCanceled     = "canceled"
+	errorReasonCircuitOpen  = "circuit_open"
+	errorReasonOther        = "other"
+)
+
+// timeNow is replaced in tests to get predictable error timestamps.
+var timeNow = time.Now
+
+type collectorErrorKey struct {
+	cloud   string
+	region  string
+	service string
+	metric  string
+}
+
+type collectorErrorStats struct {
+	counts    map[string]float64
+	lastError time.Time
+}
+
+// The error counters are global as they have to survive the exporters, which are rebuilt when
+// their options change, when clouds.yaml changes, or on ResetExporters.
+var (
+	collectorErrors   = make(map[collectorErrorKey]*collectorErrorStats)
+	collectorErrorsMu sync.Mutex
+)
+
+// statusCodeError is implemented by the response code errors of gophercloud v1 and v2.
+type statusCodeError interface {
+	GetStatusCode() int
+}
+
+// classifyError returns the reason of a metric collection failure.
+func classifyError(err error) string {
+	if errors.Is(err, ErrCircuitOpen) {
+		return errorReasonCircuitOpen
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errorReasonTimeout
+	}
+	if errors.Is(err, context.Canceled) {
+		return errorReasonCanceled
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return errorReasonTimeout
+	}
+
+	var codeErr statusCodeError
+	if errors.As(err, &codeErr) {
+		switch code := codeErr.GetStatusCode(); {
+		case code == 401:
+			return errorReasonUnauthorized
+		case code == 403:
+			return errorReasonForbidden
+		case code == 404:
+			return errorReasonNotFound
+		case code == 408 || code == 504:
+			return errorReasonTimeout
+		case code >= 500:
+			return errorReasonServerError
+		}
+	}
+	return errorReasonOther
+}
+
+// recordCollectorError counts a failed collection and returns a copy of the updated stats.
+func recordCollectorError(key collectorErrorKey, reason string) collectorErrorStats {
+	collectorErrorsMu.Lock()
+	defer collectorErrorsMu.Unlock()
+
+	stats, ok := collectorErrors[key]
+	if !ok {
+		stats = &collectorErrorStats{counts: make(map[string]float64)}
+		collectorErrors[key] = stats
+	}
+	stats.counts[reason]++
+	stats.lastError = timeNow()
+	return stats.copy()
+}
+
+// getCollectorErrors returns a copy of the stats of a metric, if it failed at least once.
+func getCollectorErrors(key collectorErrorKey) (collectorErrorStats, bool) {
+	collectorErrorsMu.Lock()
+	defer collectorErrorsMu.Unlock()
+
+	stats, ok := collectorErrors[key]
+	if !ok {
+		return collectorErrorStats{}, false
+	}
+	return stats.copy(), true
+}
+
+func (s *collectorErrorStats) copy() collectorErrorStats {
+	counts := make(map[string]float64, len(s.counts))
+	for reason, count := range s.counts {
+		counts[reason] = count
+	}
+	return collectorErrorStats{counts: counts, lastError: s.lastError}
+}
+
+// reportCollection sends the success, error counters and last error time of a metric collection.
+func (exporter *BaseOpenStackExporter) reportCollection(metricName string, err error, ch chan<- prometheus.Metric) {
+	key := collectorErrorKey{cloud: exporter.Cloud, region: exporter.Region, service: exporter.GetName(), metric: metricName}
+
+	var stats collectorErrorStats
+	var failedOnce bool
+	success := 1.0
+	if err != nil {
+		success = 0
+		stats, failedOnce = recordCollectorError(key, classifyError(err)), true
+	} else {
+		stats, failedOnce = getCollectorErrors(key)
+	}
+
+	ch <- prometheus.MustNewConstMetric(exporter.Metrics["collector_success"].Metric,
+		prometheus.GaugeValue, success, metricName)
+	if !failedOnce {
+		return
+	}
+
+	for reason, count := range stats.counts {
+		ch <- prometheus.MustNewConstMetric(exporter.Metrics["collector_errors_total"].Metric,
+			prometheus.CounterValue, count, metricName, reason)
+	}
+	ch <- prometheus.MustNewConstMetric(exporter.Metrics["collector_last_error_timestamp_seconds"].Metric,
+		prometheus.GaugeValue, float64(stats.lastError.UnixNano())/1e9, metricName)
+}
@@ -1,6 +1,7 @@
 package exporters
 
 import (
+	"context"
 	"log/slog"
 	"strconv"
 
@@ -43,13 +44,13 @@ type ContainerInfraExporter struct {
 }
 
 var defaultContainerInfraMetrics = []Metric{
-	{Name: "total_clusters", Fn: ListAllClusters},
-	{Name: "cluster_masters", Labels: []string{"uuid", "name", "stack_id", "status", "node_count", "project_id"}, Fn: nil},
-	{Name: "cluster_nodes", Labels: []string{"uuid", "name", "stack_id", "status", "master_count", "project_id"}, Fn: nil},
-	{Name: "cluster_status", Labels: []string{"uuid", "name", "stack_id", "status", "node_count", "master_count", "project_id"}, Fn: nil},
+	{Name: "total_clusters", Fn: ListAllClusters, Help: "Total number of clusters", Type: prometheus.GaugeValue},
+	{Name: "cluster_masters", Labels: []string{"uuid", "name", "stack_id", "status", "node_count", "project_id"}, Fn: nil, Help: "Number of cluster master nodes", Type: prometheus.GaugeValue},
+	{Name: "cluster_nodes", Labels: []string{"uuid", "name", "stack_id", "status", "master_count", "project_id"}, Fn: nil, Help: "Number of cluster worker nodes", Type: prometheus.GaugeValue},
+	{Name: "cluster_status", Labels: []string{"uuid", "name", "stack_id", "status", "node_count", "master_count", "project_id"}, Fn: nil, States: cluster_status, Help: "Cluster status, the index of the status in the known cluster statuses", Type: prometheus.GaugeValue},
 }
 
-func NewContainerInfraExporter(config *ExporterConfig, logger *slog.Logger) (*ContainerInfraExporter, error) {
+func NewContainerInfraExporter(ctx context.Context, config *ExporterConfig, logger *slog.Logger) (*ContainerInfraExporter, error) {
 	exporter := ContainerInfraExporter{
 		BaseOpenStackExporter{
 			Name:           "container_infra",
@@ -62,13 +63,13 @@ func NewContainerInfraExporter(config *ExporterConfig, logger *slog.Logger) (*Co
 			continue
 		}
 		if !exporter.isSlowMetric(&metric) {
-			exporter.AddMetric(metric.Name, metric.Fn, metric.Labels, metric.DeprecatedVersion, nil)
+			exporter.addMetric(metric, nil)
 		}
 	}
 	return &exporter, nil
 }
 
-func ListAllClusters(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListAllClusters(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allClusters []clusters.Cluster
 	allPagesClusters, err := clusters.List(exporter.Client, clusters.ListOpts{}).AllPages()
 	if err != nil {
@@ -79,17 +80,17 @@ func ListAllClusters(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metri
 		return err
 	}
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["total_clusters"].Metric,
-		prometheus.GaugeValue, float64(len(allClusters)))
+		exporter.Metrics["total_clusters"].Type, float64(len(allClusters)))
 	// Cluster status metrics
 	for _, cluster := range allClusters {
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["cluster_masters"].Metric,
-			prometheus.GaugeValue, float64(cluster.MasterCount), cluster.UUID, cluster.Name,
+			exporter.Metrics["cluster_masters"].Type, float64(cluster.MasterCount), cluster.UUID, cluster.Name,
 			cluster.StackID, cluster.Status, strconv.Itoa(cluster.NodeCount), cluster.ProjectID)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["cluster_nodes"].Metric,
-			prometheus.GaugeValue, float64(cluster.NodeCount), cluster.UUID, cluster.Name,
+			exporter.Metrics["cluster_nodes"].Type, float64(cluster.NodeCount), cluster.UUID, cluster.Name,
 			cluster.StackID, cluster.Status, strconv.Itoa(cluster.MasterCount), cluster.ProjectID)
-		ch <- prometheus.MustNewConstMetric(exporter.Metrics["cluster_status"].Metric,
-			prometheus.GaugeValue, float64(mapClusterStatus(cluster.Status)), cluster.UUID, cluster.Name,
+		exporter.SendStatus(ch, "cluster_status", cluster_status, mapClusterStatus(cluster.Status), cluster.Status,
+			cluster.UUID, cluster.Name,
 			cluster.StackID, cluster.Status, strconv.Itoa(cluster.NodeCount), strconv.Itoa(cluster.MasterCount), cluster.ProjectID)
 	}
 	return nil
@@ -12,19 +12,22 @@ type ContainerInfraTestSuite struct {
 }
 
 var containerInfraExpectedUp = `
-# HELP openstack_container_infra_cluster_masters cluster_masters
+# HELP openstack_collector_success Whether the last collection of the metric from OpenStack API succeeded
+# TYPE openstack_collector_success gauge
+openstack_collector_success{metric="total_clusters",service="container_infra"} 1
+# HELP openstack_container_infra_cluster_masters Number of cluster master nodes
 # TYPE openstack_container_infra_cluster_masters gauge
 openstack_container_infra_cluster_masters{name="k8s",node_count="1",project_id="0cbd49cbf76d405d9c86562e1d579bd3",stack_id="31c1ee6c-081e-4f39-9f0f-f1d87a7defa1",status="CREATE_FAILED",uuid="273c39d5-fa17-4372-b6b1-93a572de2cef"} 1
-# HELP openstack_container_infra_cluster_nodes cluster_nodes
+# HELP openstack_container_infra_cluster_nodes Number of cluster worker nodes
 # TYPE openstack_container_infra_cluster_nodes gauge
 openstack_container_infra_cluster_nodes{master_count="1",name="k8s",project_id="0cbd49cbf76d405d9c86562e1d579bd3",stack_id="31c1ee6c-081e-4f39-9f0f-f1d87a7defa1",status="CREATE_FAILED",uuid="273c39d5-fa17-4372-b6b1-93a572de2cef"} 1
-# HELP openstack_container_infra_cluster_status cluster_status
+# HELP openstack_container_infra_cluster_status Cluster status, the index of the status in the known cluster statuses
 # TYPE openstack_container_infra_cluster_status gauge
 openstack_container_infra_cluster_status{master_count="1",name="k8s",node_count="1",project_id="0cbd49cbf76d405d9c86562e1d579bd3",stack_id="31c1ee6c-081e-4f39-9f0f-f1d87a7defa1",status="CREATE_FAILED",uuid="273c39d5-fa17-4372-b6b1-93a572de2cef"} 1
-# HELP openstack_container_infra_total_clusters total_clusters
+# HELP openstack_container_infra_total_clusters Total number of clusters
 # TYPE openstack_container_infra_total_clusters gauge
 openstack_container_infra_total_clusters 1
-# HELP openstack_container_infra_up up
+# HELP openstack_container_infra_up Whether the OpenStack API of the service is up (1=up, 0=down)
 # TYPE openstack_container_infra_up gauge
 openstack_container_infra_up 1
 `
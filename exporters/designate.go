@@ -1,6 +1,7 @@
 package exporters
 
 import (
+	"context"
 	"log/slog"
 	"strings"
 
@@ -46,13 +47,13 @@ func mapRecordsetStatus(recordsetStatus string) int {
 }
 
 var defaultDesignateMetrics = []Metric{
-	{Name: "zones", Fn: ListZonesAndRecordsets},
-	{Name: "zone_status", Labels: []string{"id", "name", "status", "tenant_id", "type"}, Fn: nil},
-	{Name: "recordsets", Labels: []string{"zone_id", "zone_name", "tenant_id"}, Fn: nil},
-	{Name: "recordsets_status", Labels: []string{"id", "name", "status", "zone_id", "zone_name", "type"}, Fn: nil},
+	{Name: "zones", Fn: ListZonesAndRecordsets, Help: "Total number of DNS zones", Type: prometheus.GaugeValue},
+	{Name: "zone_status", Labels: []string{"id", "name", "status", "tenant_id", "type"}, Fn: nil, States: zone_status, Help: "DNS zone status, the index of the status in the known zone statuses", Type: prometheus.GaugeValue},
+	{Name: "recordsets", Labels: []string{"zone_id", "zone_name", "tenant_id"}, Fn: nil, Help: "Number of recordsets of the DNS zone", Type: prometheus.GaugeValue},
+	{Name: "recordsets_status", Labels: []string{"id", "name", "status", "zone_id", "zone_name", "type"}, Fn: nil, States: recordset_status, Help: "Recordset status, the index of the status in the known recordset statuses", Type: prometheus.GaugeValue},
 }
 
-func NewDesignateExporter(config *ExporterConfig, logger *slog.Logger) (*DesignateExporter, error) {
+func NewDesignateExporter(ctx context.Context, config *ExporterConfig, logger *slog.Logger) (*DesignateExporter, error) {
 	exporter := DesignateExporter{
 		BaseOpenStackExporter{
 			ExporterConfig: *config,
@@ -68,14 +69,14 @@ func NewDesignateExporter(config *ExporterConfig, logger *slog.Logger) (*Designa
 			continue
 		}
 		if !exporter.isSlowMetric(&metric) {
-			exporter.AddMetric(metric.Name, metric.Fn, metric.Labels, metric.DeprecatedVersion, nil)
+			exporter.addMetric(metric, nil)
 		}
 	}
 
 	return &exporter, nil
 }
 
-func ListZonesAndRecordsets(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListZonesAndRecordsets(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	allPagesZones, err := zones.List(exporter.Client, zones.ListOpts{}).AllPages()
 	if err != nil {
 		return err
@@ -87,7 +88,7 @@ func ListZonesAndRecordsets(exporter *BaseOpenStackExporter, ch chan<- prometheu
 	}
 
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["zones"].Metric,
-		prometheus.GaugeValue, float64(len(allZones)))
+		exporter.Metrics["zones"].Type, float64(len(allZones)))
 
 	// Collect recordsets for zone and write metrics for zones and recordsets
 	for _, zone := range allZones {
@@ -103,16 +104,16 @@ func ListZonesAndRecordsets(exporter *BaseOpenStackExporter, ch chan<- prometheu
 		}
 
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["recordsets"].Metric,
-			prometheus.GaugeValue, float64(len(allRecordsets)), zone.ID, zone.Name, zone.ProjectID)
+			exporter.Metrics["recordsets"].Type, float64(len(allRecordsets)), zone.ID, zone.Name, zone.ProjectID)
 
 		for _, recordset := range allRecordsets {
-			ch <- prometheus.MustNewConstMetric(exporter.Metrics["recordsets_status"].Metric,
-				prometheus.GaugeValue, float64(mapRecordsetStatus(recordset.Status)), recordset.ID, recordset.Name,
+			exporter.SendStatus(ch, "recordsets_status", recordset_status, mapRecordsetStatus(recordset.Status), recordset.Status,
+				recordset.ID, recordset.Name,
 				recordset.Status, recordset.ZoneID, recordset.ZoneName, recordset.Type)
 		}
 
-		ch <- prometheus.MustNewConstMetric(exporter.Metrics["zone_status"].Metric,
-			prometheus.GaugeValue, float64(mapZoneStatus(zone.Status)), zone.ID, zone.Name,
+		exporter.SendStatus(ch, "zone_status", zone_status, mapZoneStatus(zone.Status), zone.Status,
+			zone.ID, zone.Name,
 			zone.Status, zone.ProjectID, zone.Type)
 
 	}
@@ -12,19 +12,22 @@ type DesignateTestSuite struct {
 }
 
 var designateExpectedUp = `
-# HELP openstack_designate_recordsets recordsets
+# HELP openstack_collector_success Whether the last collection of the metric from OpenStack API succeeded
+# TYPE openstack_collector_success gauge
+openstack_collector_success{metric="zones",service="designate"} 1
+# HELP openstack_designate_recordsets Number of recordsets of the DNS zone
 # TYPE openstack_designate_recordsets gauge
 openstack_designate_recordsets{tenant_id="4335d1f0-f793-11e2-b778-0800200c9a66",zone_id="a86dba58-0043-4cc6-a1bb-69d5e86f3ca3",zone_name="example.org."} 1
-# HELP openstack_designate_recordsets_status recordsets_status
+# HELP openstack_designate_recordsets_status Recordset status, the index of the status in the known recordset statuses
 # TYPE openstack_designate_recordsets_status gauge
 openstack_designate_recordsets_status{id="f7b10e9b-0cae-4a91-b162-562bc6096648",name="example.org.",status="PENDING",type="A",zone_id="2150b1bf-dee2-4221-9d85-11f7886fb15f",zone_name="example.com."} 0
-# HELP openstack_designate_up up
+# HELP openstack_designate_up Whether the OpenStack API of the service is up (1=up, 0=down)
 # TYPE openstack_designate_up gauge
 openstack_designate_up 1
-# HELP openstack_designate_zone_status zone_status
+# HELP openstack_designate_zone_status DNS zone status, the index of the status in the known zone statuses
 # TYPE openstack_designate_zone_status gauge
 openstack_designate_zone_status{id="a86dba58-0043-4cc6-a1bb-69d5e86f3ca3",name="example.org.",status="ACTIVE",tenant_id="4335d1f0-f793-11e2-b778-0800200c9a66",type="PRIMARY"} 1
-# HELP openstack_designate_zones zones
+# HELP openstack_designate_zones Total number of DNS zones
 # TYPE openstack_designate_zones gauge
 openstack_designate_zones 1
 `
@@ -1,11 +1,15 @@
 package exporters
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
+	"maps"
 	"net/http"
 	"os"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"log/slog"
@@ -20,12 +24,22 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+// Metric describes a metric of an exporter. The metrics without Fn are collected by the Fn
+// of the closest metric declared before them having one.
 type Metric struct {
 	Name              string
 	Labels            []string
 	Fn                ListFunc
 	Slow              bool
 	DeprecatedVersion string
+	// States are the known statuses of a status metric, see SendStatus.
+	States []string
+	// Help is the description of the metric, the name of the metric when empty.
+	Help string
+	// Type is the type of the metric, a gauge when unset.
+	Type prometheus.ValueType
+	// Unit is the unit of the metric values (i.e: bytes, seconds), empty when unitless.
+	Unit string
 }
 
 const (
@@ -43,26 +57,37 @@ type OpenStackExporter interface {
 	prometheus.Collector
 
 	GetName() string
+	CollectContext(ctx context.Context, ch chan<- prometheus.Metric)
 	AddMetric(name string, fn ListFunc, labels []string, deprecatedVersion string, constLabels prometheus.Labels)
 	MetricIsDisabled(name string) bool
 }
 
-func EnableExporter(service, prefix, cloud string, disabledMetrics []string, endpointType string, collectTime bool, disableSlowMetrics bool, disableDeprecatedMetrics bool, disableCinderAgentUUID bool, domainID string, tenantID string, novaMetadataMapping *utils.LabelMappingFlag, uuidGenFunc func() (string, error), logger *slog.Logger) (*OpenStackExporter, error) {
-	exporter, err := NewExporter(service, prefix, cloud, disabledMetrics, endpointType, collectTime, disableSlowMetrics, disableDeprecatedMetrics, disableCinderAgentUUID, domainID, tenantID, novaMetadataMapping, uuidGenFunc, logger)
-	if err != nil {
-		return nil, err
-	}
-	return &exporter, nil
-}
-
 type PrometheusMetric struct {
 	Metric *prometheus.Desc
 	Fn     ListFunc
+	// Type is the type of the values sent for Metric, and Unit their unit, set on the gathered
+	// families by WithUnits.
+	Type prometheus.ValueType
+	Unit string
+
+	fqName string
+	// group is the name of the metric whose Fn collects this metric.
+	group string
+	// filtered metrics are still collected by their group, but not exported.
+	filtered bool
+	// labels are the variable labels of Metric. When the label rules drop some of them,
+	// kept are the indexes of the exported labels and exportedDesc the exported description.
+	labels       []string
+	kept         []int
+	exportedDesc *prometheus.Desc
+	// seriesLimit is the maximum number of series exported by the metric, 0 means no limit.
+	seriesLimit int
 }
 
 type ExporterConfig struct {
 	Client                   *gophercloud.ServiceClient
 	ClientV2                 *gophercloudv2.ServiceClient
+	Cloud                    string
 	Prefix                   string
 	DisabledMetrics          []string
 	CollectTime              bool
@@ -72,7 +97,19 @@ type ExporterConfig struct {
 	DisableCinderAgentUUID   bool
 	DomainID                 string
 	TenantID                 string
-	NovaMetadataMapping      *utils.LabelMappingFlag
+	LabelMappings            *utils.ResourceLabelMappingFlag
+	// MetricFilter selects the exported metrics and labels, nil exports everything.
+	MetricFilter *utils.MetricFilter
+	// CollectConcurrency is the maximum number of ListFuncs of one exporter running at the same time.
+	CollectConcurrency int
+	// CloudCollectConcurrency is the maximum number of ListFuncs running at the same time across
+	// all the exporters of the same cloud. Zero or a negative value disables the limit.
+	CloudCollectConcurrency int
+	// Region is the region of the endpoints of the clients, added as a region label to the metrics.
+	// It's empty when the region label is disabled.
+	Region string
+	// StateSetStatus sends the status metrics as one series per status, instead of the index of the status.
+	StateSetStatus bool
 }
 
 type BaseOpenStackExporter struct {
@@ -80,9 +117,14 @@ type BaseOpenStackExporter struct {
 	Name    string
 	Metrics map[string]*PrometheusMetric
 	logger  *slog.Logger
+
+	// lastGroup is the last metric added with a Fn, collecting the next metrics without one.
+	lastGroup string
+	// descs maps the descriptions of the collected metrics to the metrics, to filter them.
+	descs map[*prometheus.Desc]*PrometheusMetric
 }
 
-type ListFunc func(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error
+type ListFunc func(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error
 
 var (
 	endpointOpts   = make(map[string]gophercloud.EndpointOpts)
@@ -92,6 +134,27 @@ var (
 	endpointOptsV2   map[string]gophercloudv2.EndpointOpts
 	endpointOptsV2Mu sync.Mutex
 )
+var (
+	cloudSemaphores   = make(map[string]chan struct{})
+	cloudSemaphoresMu sync.Mutex
+)
+
+// cloudSemaphore returns the semaphore shared by all the exporters of a cloud,
+// or nil if the number of concurrent collections per cloud is not limited.
+func cloudSemaphore(cloud string, size int) chan struct{} {
+	if size <= 0 {
+		return nil
+	}
+
+	cloudSemaphoresMu.Lock()
+	defer cloudSemaphoresMu.Unlock()
+	if sem, ok := cloudSemaphores[cloud]; ok && cap(sem) == size {
+		return sem
+	}
+	sem := make(chan struct{}, size)
+	cloudSemaphores[cloud] = sem
+	return sem
+}
 
 func (exporter *BaseOpenStackExporter) GetName() string {
 	return fmt.Sprintf("%s_%s", exporter.Prefix, exporter.Name)
@@ -106,16 +169,33 @@ func (exporter *BaseOpenStackExporter) MetricIsDisabled(name string) bool {
 	return false
 }
 
+// sendIfEnabled sends a value of the metric, unless the metric was not added, i.e: when it is disabled.
+func (exporter *BaseOpenStackExporter) sendIfEnabled(ch chan<- prometheus.Metric, name string, value float64, labelValues ...string) {
+	metric, ok := exporter.Metrics[name]
+	if !ok {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(metric.Metric, metric.Type, value, labelValues...)
+}
+
 func (exporter *BaseOpenStackExporter) Describe(ch chan<- *prometheus.Desc) {
 	for _, metric := range exporter.Metrics {
+		if metric.filtered {
+			continue
+		}
+		if metric.exportedDesc != nil {
+			ch <- metric.exportedDesc
+			continue
+		}
 		ch <- metric.Metric
 	}
 }
 
-func (exporter *BaseOpenStackExporter) RunCollection(metric *PrometheusMetric, metricName string, ch chan<- prometheus.Metric, logger *slog.Logger) error {
+func (exporter *BaseOpenStackExporter) RunCollection(ctx context.Context, metric *PrometheusMetric, metricName string, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	exporter.logger.Info("Collecting metrics for exporter", "exporter", exporter.GetName(), "metrics", metricName)
 	now := time.Now()
-	err := metric.Fn(exporter, ch)
+	err := exporter.collectFiltered(ctx, metric.Fn, ch)
+	exporter.reportCollection(metricName, err, ch)
 	if err != nil {
 		return fmt.Errorf("failed to collect metric: %s, error: %s", metricName, err)
 	}
@@ -128,24 +208,72 @@ func (exporter *BaseOpenStackExporter) RunCollection(metric *PrometheusMetric, m
 }
 
 func (exporter *BaseOpenStackExporter) Collect(ch chan<- prometheus.Metric) {
-	metricsDown := 0
+	exporter.CollectContext(context.Background(), ch)
+}
+
+// CollectContext is like Collect, but stops the collection as soon as ctx is done.
+// The OpenStack API requests issued by the ListFuncs are bound to ctx, and the resources
+// they list are shared through the snapshot of ctx, see WithSnapshot.
+func (exporter *BaseOpenStackExporter) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
+	if !hasSnapshot(ctx) {
+		ctx = WithSnapshot(ctx)
+	}
+
+	var wg sync.WaitGroup
+	var metricsDown atomic.Int32
 	metricsCount := len(exporter.Metrics)
 
+	workers := exporter.CollectConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+	exporterSem := make(chan struct{}, workers)
+	cloudSem := cloudSemaphore(exporter.Cloud, exporter.CloudCollectConcurrency)
+	scoped := exporter.withContext(ctx)
+
 	for name, metric := range exporter.Metrics {
 		if metric.Fn == nil {
 			exporter.logger.Debug("No function handler set for metric", "metric", name)
 			metricsCount--
 			continue
 		}
+		if !exporter.groupExported(name) {
+			exporter.logger.Debug("All the metrics collected with metric are filtered, not collecting them", "metric", name)
+			metricsCount--
+			continue
+		}
 
-		if err := exporter.RunCollection(metric, name, ch, exporter.logger); err != nil {
-			exporter.logger.Error("Failed to collect metric for exporter", "exporter", exporter.Name, "error", err)
-			metricsDown++
+		if err := acquire(ctx, exporterSem); err != nil {
+			exporter.logger.Error("Failed to collect metric for exporter", "exporter", exporter.Name, "metric", name, "error", err)
+			exporter.reportCollection(name, err, ch)
+			metricsDown.Add(1)
+			continue
 		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-exporterSem }()
+
+			if cloudSem != nil {
+				if err := acquire(ctx, cloudSem); err != nil {
+					exporter.logger.Error("Failed to collect metric for exporter", "exporter", exporter.Name, "metric", name, "error", err)
+					exporter.reportCollection(name, err, ch)
+					metricsDown.Add(1)
+					return
+				}
+				defer func() { <-cloudSem }()
+			}
+
+			if err := scoped.RunCollection(ctx, metric, name, ch, exporter.logger); err != nil {
+				exporter.logger.Error("Failed to collect metric for exporter", "exporter", exporter.Name, "error", err)
+				metricsDown.Add(1)
+			}
+		}()
 	}
+	wg.Wait()
 
 	//If all metrics collections fails for a given service, we'll flag it as down.
-	if metricsDown >= metricsCount {
+	if int(metricsDown.Load()) >= metricsCount {
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["up"].Metric, prometheus.GaugeValue, 0)
 	} else {
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["up"].Metric, prometheus.GaugeValue, 1)
@@ -153,6 +281,50 @@ func (exporter *BaseOpenStackExporter) Collect(ch chan<- prometheus.Metric) {
 
 }
 
+// acquire takes a slot of the semaphore, unless ctx is done first.
+func acquire(ctx context.Context, sem chan struct{}) error {
+	select {
+	case sem <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	// Both cases can be ready at once, never start a collection once ctx is done.
+	if err := ctx.Err(); err != nil {
+		<-sem
+		return err
+	}
+	return nil
+}
+
+// withContext returns a copy of the exporter whose clients send their requests with ctx.
+func (exporter *BaseOpenStackExporter) withContext(ctx context.Context) *BaseOpenStackExporter {
+	scoped := *exporter
+	if exporter.Client != nil {
+		scoped.Client = serviceClientWithContext(ctx, exporter.Client)
+	}
+	return &scoped
+}
+
+// contextCollector binds an OpenStackExporter collection to a context.
+type contextCollector struct {
+	ctx      context.Context
+	exporter OpenStackExporter
+}
+
+// WithContext returns a prometheus.Collector running the exporter collection with ctx,
+// so the OpenStack API requests are abandoned once ctx is done.
+func WithContext(ctx context.Context, exporter OpenStackExporter) prometheus.Collector {
+	return &contextCollector{ctx: ctx, exporter: exporter}
+}
+
+func (c *contextCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.exporter.Describe(ch)
+}
+
+func (c *contextCollector) Collect(ch chan<- prometheus.Metric) {
+	c.exporter.CollectContext(c.ctx, ch)
+}
+
 func (exporter *BaseOpenStackExporter) isSlowMetric(metric *Metric) bool {
 	return exporter.DisableSlowMetrics && metric.Slow
 }
@@ -161,7 +333,51 @@ func (exporter *BaseOpenStackExporter) isDeprecatedMetric(metric *Metric) bool {
 	return exporter.DisableDeprecatedMetrics && len(metric.DeprecatedVersion) > 0
 }
 
+// mappedLabels returns the labels of a metric of the resource (i.e: cinder.volume), followed by
+// the labels mapped from the metadata, properties or tags of the resource.
+func (exporter *BaseOpenStackExporter) mappedLabels(resource string, labels []string) ([]string, error) {
+	mapping := exporter.LabelMappings.Get(resource)
+	for _, label := range mapping.Labels {
+		if slices.Contains(labels, label) {
+			return nil, fmt.Errorf("%s: %w: %s", resource, utils.ErrLabelDup, label)
+		}
+	}
+	return append(slices.Clone(labels), mapping.Labels...), nil
+}
+
+// NewBaseOpenStackExporter returns the base of the exporter of a service, name being the name of
+// the exporter in the fully qualified names of its metrics. The exporters of other packages embed
+// it, and add their metrics with AddMetricDefinition.
+func NewBaseOpenStackExporter(name string, config *ExporterConfig, logger *slog.Logger) *BaseOpenStackExporter {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &BaseOpenStackExporter{Name: name, ExporterConfig: *config, logger: logger}
+}
+
+// AddMetricDefinition adds a metric described by its Metric definition, with its help text, type,
+// unit and states.
+func (exporter *BaseOpenStackExporter) AddMetricDefinition(definition Metric, constLabels prometheus.Labels) {
+	exporter.addMetric(definition, constLabels)
+}
+
 func (exporter *BaseOpenStackExporter) AddMetric(name string, fn ListFunc, labels []string, deprecatedVersion string, constLabels prometheus.Labels) {
+	exporter.addMetric(Metric{Name: name, Fn: fn, Labels: labels, DeprecatedVersion: deprecatedVersion}, constLabels)
+}
+
+// addMetric adds a metric described by its Metric definition, with its help text, type and unit.
+func (exporter *BaseOpenStackExporter) addMetric(definition Metric, constLabels prometheus.Labels) {
+	name, fn, deprecatedVersion := definition.Name, definition.Fn, definition.DeprecatedVersion
+	labels := exporter.statusLabels(&definition)
+	help := definition.Help
+	if help == "" {
+		help = name
+	}
+	help = exporter.statusHelp(&definition, help)
+	valueType := definition.Type
+	if valueType == 0 {
+		valueType = prometheus.GaugeValue
+	}
 
 	if exporter.MetricIsDisabled(name) {
 		exporter.logger.Warn("metric has been disabled for exporter, not collecting metrics", "metric", name, "exporter", exporter.Name)
@@ -172,36 +388,109 @@ func (exporter *BaseOpenStackExporter) AddMetric(name string, fn ListFunc, label
 		exporter.logger.Warn("metric has been deprecated on exporter in version and it will be removed in next release", "metric", name, "exporter", exporter.Name, "version", deprecatedVersion)
 	}
 
+	constLabels = maps.Clone(constLabels)
+	if constLabels == nil {
+		constLabels = prometheus.Labels{}
+	}
+	// Some metrics already report the region of the resources as a variable label.
+	if exporter.Region != "" && !slices.Contains(labels, "region") {
+		constLabels["region"] = exporter.Region
+	}
+
 	if exporter.Metrics == nil {
 		exporter.Metrics = make(map[string]*PrometheusMetric)
 		exporter.Metrics["up"] = &PrometheusMetric{
 			Metric: prometheus.NewDesc(
 				prometheus.BuildFQName(exporter.GetName(), "", "up"),
-				"up", nil, constLabels),
-			Fn: nil,
+				"Whether the OpenStack API of the service is up (1=up, 0=down)", nil, constLabels),
+			Fn:   nil,
+			Type: prometheus.GaugeValue,
 		}
 		exporter.Metrics["openstack_metric_collect_seconds"] = &PrometheusMetric{
 			Metric: prometheus.NewDesc(
-				"openstack_metric_collect_seconds", "Time needed to collect metric from OpenStack API", []string{"openstack_metric"}, prometheus.Labels{"openstack_service": exporter.GetName()}),
-			Fn: nil,
+				"openstack_metric_collect_seconds", "Time needed to collect metric from OpenStack API", []string{"openstack_metric"}, exporter.serviceLabels("openstack_service", exporter.GetName())),
+			Fn:   nil,
+			Type: prometheus.GaugeValue,
+			Unit: "seconds",
 		}
+		exporter.Metrics["collector_success"] = &PrometheusMetric{
+			Metric: prometheus.NewDesc(
+				prometheus.BuildFQName(exporter.Prefix, "collector", "success"),
+				"Whether the last collection of the metric from OpenStack API succeeded", []string{"metric"}, exporter.serviceLabels("service", exporter.Name)),
+			Fn:   nil,
+			Type: prometheus.GaugeValue,
+		}
+		exporter.Metrics["collector_errors_total"] = &PrometheusMetric{
+			Metric: prometheus.NewDesc(
+				prometheus.BuildFQName(exporter.Prefix, "collector", "errors_total"),
+				"Number of failed collections of the metric from OpenStack API by reason", []string{"metric", "reason"}, exporter.serviceLabels("service", exporter.Name)),
+			Fn:   nil,
+			Type: prometheus.CounterValue,
+		}
+		exporter.Metrics["collector_last_error_timestamp_seconds"] = &PrometheusMetric{
+			Metric: prometheus.NewDesc(
+				prometheus.BuildFQName(exporter.Prefix, "collector", "last_error_timestamp_seconds"),
+				"Time of the last failed collection of the metric from OpenStack API", []string{"metric"}, exporter.serviceLabels("service", exporter.Name)),
+			Fn:   nil,
+			Type: prometheus.GaugeValue,
+			Unit: "seconds",
+		}
+		exporter.Metrics["series_limit_exceeded"] = &PrometheusMetric{
+			Metric: prometheus.NewDesc(
+				prometheus.BuildFQName(exporter.Prefix, "", "series_limit_exceeded"),
+				"Number of series of the metric not exported as they exceeded its series limit", []string{"metric"}, exporter.serviceLabels("service", exporter.Name)),
+			Fn:   nil,
+			Type: prometheus.GaugeValue,
+		}
+		setUnit("openstack_metric_collect_seconds", "seconds")
+		setUnit(prometheus.BuildFQName(exporter.Prefix, "collector", "last_error_timestamp_seconds"), "seconds")
 	}
 
-	if constLabels == nil {
-		constLabels = prometheus.Labels{}
-	}
-
-	// @TODO: get the region. constLabels["region"] = exporter.
-
 	if _, ok := exporter.Metrics[name]; !ok {
 		exporter.logger.Info("Adding metric to exporter", "metric", name, "exporter", exporter.Name)
-		exporter.Metrics[name] = &PrometheusMetric{
-			Metric: prometheus.NewDesc(
-				prometheus.BuildFQName(exporter.GetName(), "", name),
-				name, labels, constLabels),
-			Fn: fn,
+		fqName := prometheus.BuildFQName(exporter.GetName(), "", name)
+		if fn != nil {
+			exporter.lastGroup = name
+		}
+		metric := &PrometheusMetric{
+			Metric:      prometheus.NewDesc(fqName, help, labels, constLabels),
+			Fn:          fn,
+			Type:        valueType,
+			Unit:        definition.Unit,
+			fqName:      fqName,
+			group:       exporter.lastGroup,
+			filtered:    !exporter.MetricFilter.MetricAllowed(fqName),
+			labels:      labels,
+			kept:        exporter.MetricFilter.KeptLabels(fqName, labels),
+			seriesLimit: exporter.MetricFilter.MetricSeriesLimit(fqName, perResource(labels)),
 		}
+		if metric.filtered {
+			exporter.logger.Info("metric is filtered for exporter, not exporting it", "metric", name, "exporter", exporter.Name)
+		}
+		if metric.kept != nil {
+			exportedLabels := make([]string, 0, len(metric.kept))
+			for _, i := range metric.kept {
+				exportedLabels = append(exportedLabels, labels[i])
+			}
+			metric.exportedDesc = prometheus.NewDesc(fqName, help, exportedLabels, constLabels)
+		}
+		exporter.Metrics[name] = metric
+		setUnit(fqName, definition.Unit)
+
+		if exporter.descs == nil {
+			exporter.descs = make(map[*prometheus.Desc]*PrometheusMetric)
+		}
+		exporter.descs[metric.Metric] = metric
+	}
+}
+
+// serviceLabels returns the const labels of the metrics describing the collection of the exporter.
+func (exporter *BaseOpenStackExporter) serviceLabels(name, value string) prometheus.Labels {
+	labels := prometheus.Labels{name: value}
+	if exporter.Region != "" {
+		labels["region"] = exporter.Region
 	}
+	return labels
 }
 
 // took from here:
@@ -231,9 +520,16 @@ func pathOrContents(poc string) ([]byte, bool, error) {
 	return []byte(poc), false, nil
 }
 
-func NewExporter(name, prefix, cloud string, disabledMetrics []string, endpointType string, collectTime bool, disableSlowMetrics bool, disableDeprecatedMetrics bool, disableCinderAgentUUID bool, domainID string, tenantID string, novaMetadataMapping *utils.LabelMappingFlag, uuidGenFunc func() (string, error), logger *slog.Logger) (OpenStackExporter, error) {
-	var exporter OpenStackExporter
-	var err error
+func NewExporter(ctx context.Context, options ExporterOptions, logger *slog.Logger) (OpenStackExporter, error) {
+	clients, err := newCloudClients(ctx, options.Cloud, logger)
+	if err != nil {
+		return nil, err
+	}
+	return newExporter(ctx, clients, options, logger)
+}
+
+// newCloudClients parses the cloud configuration and authenticates the provider clients of the cloud.
+func newCloudClients(ctx context.Context, cloud string, logger *slog.Logger) (*cloudClients, error) {
 	var transport *http.Transport
 	var tlsConfig tls.Config
 
@@ -281,16 +577,54 @@ func NewExporter(name, prefix, cloud string, disabledMetrics []string, endpointT
 		transport = &http.Transport{TLSClientConfig: &tlsConfig}
 	}
 
-	client, err := NewServiceClient(name, &opts, transport, endpointType)
+	api := newAPIInstrumentation(cloud, logger)
+	provider, err := authenticatedClient(&opts, transport, api)
 	if err != nil {
 		return nil, err
 	}
 
-	clientV2, err := NewServiceClientV2(name, &optsv2, transport, endpointType)
+	providerV2, err := authenticatedClientV2(ctx, &optsv2, transport, api)
 	if err != nil {
 		return nil, err
 	}
 
+	return &cloudClients{
+		opts:       opts,
+		optsV2:     optsv2,
+		provider:   provider,
+		providerV2: providerV2,
+		region:     cloudRegion(&opts, config),
+		api:        api,
+	}, nil
+}
+
+// newExporter creates the exporter of a service with the provider clients of its cloud.
+// A non empty region overrides the region of the cloud.
+func newExporter(ctx context.Context, clients *cloudClients, options ExporterOptions, logger *slog.Logger) (OpenStackExporter, error) {
+	factory, ok := lookupFactory(options.Service)
+	if !ok {
+		return nil, fmt.Errorf("couldn't find a handler for %s exporter", options.Service)
+	}
+
+	opts, optsV2 := clients.opts, clients.optsV2
+	if options.Region != "" {
+		opts.RegionName = options.Region
+		optsV2.RegionName = options.Region
+	}
+
+	client, err := NewServiceClient(options.Service, &opts, clients.provider, options.EndpointType)
+	if err != nil {
+		return nil, err
+	}
+
+	clientV2, err := NewServiceClientV2(options.Service, &optsV2, clients.providerV2, options.EndpointType)
+	if err != nil {
+		return nil, err
+	}
+	clients.api.addEndpoint(client.Endpoint, options.Service, opts.RegionName)
+	clients.api.addEndpoint(clientV2.Endpoint, options.Service, optsV2.RegionName)
+
+	uuidGenFunc := options.UUIDGenFunc
 	if uuidGenFunc == nil {
 		uuidGenFunc = uuid.GenerateUUID
 	}
@@ -298,56 +632,23 @@ func NewExporter(name, prefix, cloud string, disabledMetrics []string, endpointT
 	exporterConfig := ExporterConfig{
 		Client:                   client,
 		ClientV2:                 clientV2,
-		Prefix:                   prefix,
-		DisabledMetrics:          disabledMetrics,
-		CollectTime:              collectTime,
+		Cloud:                    options.Cloud,
+		Region:                   options.Region,
+		Prefix:                   options.Prefix,
+		DisabledMetrics:          options.DisabledMetrics,
+		CollectTime:              options.CollectTime,
 		UUIDGenFunc:              uuidGenFunc,
-		DisableSlowMetrics:       disableSlowMetrics,
-		DisableDeprecatedMetrics: disableDeprecatedMetrics,
-		DisableCinderAgentUUID:   disableCinderAgentUUID,
-		DomainID:                 domainID,
-		TenantID:                 tenantID,
-		NovaMetadataMapping:      novaMetadataMapping,
-	}
-
-	switch name {
-	case "network":
-		exporter, err = NewNeutronExporter(&exporterConfig, logger)
-	case "compute":
-		exporter, err = NewNovaExporter(&exporterConfig, logger)
-	case "image":
-		exporter, err = NewGlanceExporter(&exporterConfig, logger)
-	case "volume":
-		exporter, err = NewCinderExporter(&exporterConfig, logger)
-	case "identity":
-		exporter, err = NewKeystoneExporter(&exporterConfig, logger)
-	case "object-store":
-		exporter, err = NewObjectStoreExporter(&exporterConfig, logger)
-	case "load-balancer":
-		exporter, err = NewLoadbalancerExporter(&exporterConfig, logger)
-	case "container-infra":
-		exporter, err = NewContainerInfraExporter(&exporterConfig, logger)
-	case "dns":
-		exporter, err = NewDesignateExporter(&exporterConfig, logger)
-	case "baremetal":
-		exporter, err = NewIronicExporter(&exporterConfig, logger)
-	case "gnocchi":
-		exporter, err = NewGnocchiExporter(&exporterConfig, logger)
-	case "database":
-		exporter, err = NewTroveExporter(&exporterConfig, logger)
-	case "orchestration":
-		exporter, err = NewHeatExporter(&exporterConfig, logger)
-	case "placement":
-		exporter, err = NewPlacementExporter(&exporterConfig, logger)
-	case "sharev2":
-		exporter, err = NewManilaExporter(&exporterConfig, logger)
-	default:
-		return nil, fmt.Errorf("couldn't find a handler for %s exporter", name)
-	}
-
-	if err != nil {
-		return nil, err
+		DisableSlowMetrics:       options.DisableSlowMetrics,
+		DisableDeprecatedMetrics: options.DisableDeprecatedMetrics,
+		DisableCinderAgentUUID:   options.DisableCinderAgentUUID,
+		StateSetStatus:           options.StateSetStatus,
+		DomainID:                 options.DomainID,
+		TenantID:                 options.TenantID,
+		LabelMappings:            options.LabelMappings,
+		MetricFilter:             options.MetricFilter,
+		CollectConcurrency:       options.CollectConcurrency,
+		CloudCollectConcurrency:  options.CloudCollectConcurrency,
 	}
 
-	return exporter, nil
+	return factory(ctx, &exporterConfig, logger)
 }
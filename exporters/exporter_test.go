@@ -1,21 +1,39 @@
 package exporters
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"log/slog"
 
+	"github.com/gophercloud/gophercloud"
+	tokens3 "github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
 	"github.com/jarcoal/httpmock"
+	"github.com/openstack-exporter/openstack-exporter/internal/fixtures"
 	"github.com/openstack-exporter/openstack-exporter/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/client_golang/prometheus/testutil/promlint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
-const baseFixturePath = "./fixtures"
+const baseFixturePath = "../internal/fixtures"
 const cloudName = "test.cloud"
 
 type BaseOpenStackTestSuite struct {
@@ -36,8 +54,15 @@ func (suite *BaseOpenStackTestSuite) SetResponseFromFixture(method string, statu
 		StatusCode: statusCode,
 	}
 
+	// Metrics are collected concurrently, serialize the calls to the
+	// responder as its call counter is not goroutine-safe.
+	var mu sync.Mutex
 	responder := httpmock.ResponderFromResponse(response).Times(2)
-	httpmock.RegisterResponder(method, url, responder)
+	httpmock.RegisterResponder(method, url, func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return responder(req)
+	})
 }
 
 func (suite *BaseOpenStackTestSuite) MakeURL(resource string, port string) string {
@@ -51,114 +76,6 @@ func (suite *BaseOpenStackTestSuite) FixturePath(name string) string {
 	return fmt.Sprintf("%s/%s", baseFixturePath, name+".json")
 }
 
-var fixtures map[string]string = map[string]string{
-	"/container-infra/":              "container_infra_api_discovery",
-	"/container-infra/clusters":      "container_infra_clusters",
-	"/compute/":                      "nova_api_discovery",
-	"/compute/v2.1/":                 "nova_api_v2.1",
-	"/compute/os-services":           "nova_os_services",
-	"/compute/os-hypervisors/detail": "nova_os_hypervisors",
-	"/compute/flavors/detail":        "nova_os_flavors",
-	"/compute/os-availability-zone":  "nova_os_availability_zones",
-	"/compute/os-security-groups":    "nova_os_security_groups",
-	"/compute/os-aggregates":         "nova_os_aggregates",
-	"/compute/limits?tenant_id=0c4e939acacf4376bdcd1129f1a054ad": "nova_os_limits",
-	"/compute/limits?tenant_id=0cbd49cbf76d405d9c86562e1d579bd3": "nova_os_limits",
-	"/compute/limits?tenant_id=2db68fed84324f29bb73130c6c2094fb": "nova_os_limits",
-	"/compute/limits?tenant_id=3d594eb0f04741069dbbb521635b21c7": "nova_os_limits",
-	"/compute/limits?tenant_id=43ebde53fc314b1c9ea2b8c5dc744927": "nova_os_limits",
-	"/compute/limits?tenant_id=4b1eb781a47440acb8af9850103e537f": "nova_os_limits",
-	"/compute/limits?tenant_id=5961c443439d4fcebe42643723755e9d": "nova_os_limits",
-	"/compute/limits?tenant_id=fdb8424c4e4f4c0ba32c52e2de3bd80e": "nova_os_limits",
-	"/compute/servers/detail?all_tenants=true":                   "nova_os_servers",
-	"/compute/os-simple-tenant-usage?detailed=1":                 "nova_os_simple_tenant_usage",
-	"/glance/":          "glance_api_discovery",
-	"/glance/v2/images": "glance_images",
-	"/gnocchi/v1/metric?marker=5e9b3ee0-aee1-4461-8849-3f4ae5e30d8d": "gnocchi_metric",
-	"/gnocchi/v1/metric":                         "gnocchi_metric",
-	"/gnocchi/v1/status":                         "gnocchi_status",
-	"/gnocchi/v1/status?details=true":            "gnocchi_status",
-	"/identity/v3/projects":                      "identity_projects",
-	"/identity/v3/domains":                       "identity_domains",
-	"/identity/v3/users":                         "identity_users",
-	"/identity/v3/groups":                        "identity_groups",
-	"/identity/v3/regions":                       "identity_regions",
-	"/neutron/":                                  "neutron_api_discovery",
-	"/neutron/v2.0/floatingips":                  "neutron_floating_ips",
-	"/neutron/v2.0/agents":                       "neutron_agents",
-	"/neutron/v2.0/networks":                     "neutron_networks",
-	"/neutron/v2.0/security-groups":              "neutron_security_groups",
-	"/neutron/v2.0/subnets":                      "neutron_subnets",
-	"/neutron/v2.0/subnetpools":                  "neutron_subnet_pools",
-	"/neutron/v2.0/ports":                        "neutron_ports",
-	"/neutron/v2.0/network-ip-availabilities":    "neutron_network_ip_availabilities",
-	"/neutron/v2.0/routers":                      "neutron_routers",
-	"/neutron/v2.0/agents?binary=ovn-controller": "neutron_ovn_controller_agents",
-	"/neutron/v2.0/routers/f8a44de0-fc8e-45df-93c7-f79bf3b01c95/l3-agents": "neutron_routers_l3_agents",
-	"/neutron/v2.0/routers/9daeb7dd-7e3f-4e44-8c42-c7a0e8c8a42f/l3-agents": "neutron_routers_l3_agents",
-	"/loadbalancer/":                                                       "loadbalancer_api_discovery",
-	"/loadbalancer/v2.0/lbaas/loadbalancers":                               "loadbalancer_loadbalancers",
-	"/loadbalancer/v2.0/lbaas/loadbalancers/607226db-27ef-4d41-ae89-f2a800e9c2db/stats": "loadbalancer_stats",
-	"/loadbalancer/v2.0/octavia/amphorae":                                  "loadbalancer_amphorae",
-	"/loadbalancer/v2.0/lbaas/pools":                                       "loadbalancer_pools",
-	"/ironic/":                                                             "ironic_api_discovery",
-	"/ironic/v1":                                                           "ironic_v1",
-	"/ironic/nodes":                                                        "ironic_nodes",
-	"/ironic/nodes/detail":                                                 "ironic_nodes",
-	"/volumes":                                                             "cinder_api_discovery",
-	"/volumes/":                                                            "cinder_api_discovery",
-	"/volumes/volumes/detail?all_tenants=true":                             "cinder_volumes",
-	"/volumes/snapshots":                                                   "cinder_snapshots",
-	"/volumes/os-services":                                                 "cinder_os_services",
-	"/volumes/scheduler-stats/get_pools?detail=true":                       "cinder_scheduler_stats_pools",
-	"/volumes/os-quota-sets/0c4e939acacf4376bdcd1129f1a054ad?usage=true": "cinder_os_quota_sets_usage",
-	"/volumes/os-quota-sets/0cbd49cbf76d405d9c86562e1d579bd3?usage=true": "cinder_os_quota_sets_usage",
-	"/volumes/os-quota-sets/2db68fed84324f29bb73130c6c2094fb?usage=true": "cinder_os_quota_sets_usage",
-	"/volumes/os-quota-sets/3d594eb0f04741069dbbb521635b21c7?usage=true": "cinder_os_quota_sets_usage",
-	"/volumes/os-quota-sets/43ebde53fc314b1c9ea2b8c5dc744927?usage=true": "cinder_os_quota_sets_usage",
-	"/volumes/os-quota-sets/4b1eb781a47440acb8af9850103e537f?usage=true": "cinder_os_quota_sets_usage",
-	"/volumes/os-quota-sets/5961c443439d4fcebe42643723755e9d?usage=true": "cinder_os_quota_sets_usage",
-	"/volumes/os-quota-sets/fdb8424c4e4f4c0ba32c52e2de3bd80e?usage=true": "cinder_os_quota_sets_usage",
-	"/volumes/os-quota-sets/0c4e939acacf4376bdcd1129f1a054ad": "cinder_os_quota_sets",
-	"/volumes/os-quota-sets/0cbd49cbf76d405d9c86562e1d579bd3": "cinder_os_quota_sets",
-	"/volumes/os-quota-sets/2db68fed84324f29bb73130c6c2094fb": "cinder_os_quota_sets",
-	"/volumes/os-quota-sets/3d594eb0f04741069dbbb521635b21c7": "cinder_os_quota_sets",
-	"/volumes/os-quota-sets/43ebde53fc314b1c9ea2b8c5dc744927": "cinder_os_quota_sets",
-	"/volumes/os-quota-sets/4b1eb781a47440acb8af9850103e537f": "cinder_os_quota_sets",
-	"/volumes/os-quota-sets/5961c443439d4fcebe42643723755e9d": "cinder_os_quota_sets",
-	"/volumes/os-quota-sets/fdb8424c4e4f4c0ba32c52e2de3bd80e": "cinder_os_quota_sets",
-	"/designate/":                                                          "designate_api_discovery",
-	"/designate/v2/zones":                                                  "designate_zones",
-	"/designate/v2/zones/a86dba58-0043-4cc6-a1bb-69d5e86f3ca3/recordsets":  "designate_recordsets",
-	"/database/": "trove_api_discovery",
-	"/database/mgmt/instances?include_clustered=False&deleted=False": "trove_instances",
-	"/orchestration/":               "heat_api_discovery",
-	"/orchestration/stacks":         "heat_stacks",
-	"/placement/":                   "placement_api_discovery",
-	"/placement/resource_providers": "resource_providers",
-	"/placement/resource_providers/b985be15-99bf-4baf-9ef7-3ef166cd7f31/inventories": "resource_provider_1_inventory",
-	"/placement/resource_providers/328c9f0a-5a3c-4ad6-9347-689eb7632d7b/inventories": "resource_provider_2_inventory",
-	"/placement/resource_providers/b985be15-99bf-4baf-9ef7-3ef166cd7f31/usages":      "resource_provider_1_usage",
-	"/placement/resource_providers/328c9f0a-5a3c-4ad6-9347-689eb7632d7b/usages":      "resource_provider_2_usage",
-	"/compute/os-quota-sets/0c4e939acacf4376bdcd1129f1a054ad/detail":                 "nova_quotas_1_usage",
-	"/compute/os-quota-sets/0cbd49cbf76d405d9c86562e1d579bd3/detail":                 "nova_quotas_1_usage",
-	"/compute/os-quota-sets/2db68fed84324f29bb73130c6c2094fb/detail":                 "nova_quotas_1_usage",
-	"/compute/os-quota-sets/3d594eb0f04741069dbbb521635b21c7/detail":                 "nova_quotas_1_usage",
-	"/compute/os-quota-sets/43ebde53fc314b1c9ea2b8c5dc744927/detail":                 "nova_quotas_1_usage",
-	"/compute/os-quota-sets/5961c443439d4fcebe42643723755e9d/detail":                 "nova_quotas_1_usage",
-	"/compute/os-quota-sets/fdb8424c4e4f4c0ba32c52e2de3bd80e/detail":                 "nova_quotas_1_usage",
-	"/compute/os-quota-sets/4b1eb781a47440acb8af9850103e537f/detail":                 "nova_quotas_1_usage",
-	"/neutron/v2.0/quotas/0c4e939acacf4376bdcd1129f1a054ad/details.json":                 "neutron_quotas_1_usage",
-	"/neutron/v2.0/quotas/0cbd49cbf76d405d9c86562e1d579bd3/details.json":                 "neutron_quotas_1_usage",
-	"/neutron/v2.0/quotas/2db68fed84324f29bb73130c6c2094fb/details.json":                 "neutron_quotas_1_usage",
-	"/neutron/v2.0/quotas/3d594eb0f04741069dbbb521635b21c7/details.json":                 "neutron_quotas_1_usage",
-	"/neutron/v2.0/quotas/43ebde53fc314b1c9ea2b8c5dc744927/details.json":                 "neutron_quotas_1_usage",
-	"/neutron/v2.0/quotas/5961c443439d4fcebe42643723755e9d/details.json":                 "neutron_quotas_1_usage",
-	"/neutron/v2.0/quotas/fdb8424c4e4f4c0ba32c52e2de3bd80e/details.json":                 "neutron_quotas_1_usage",
-	"/neutron/v2.0/quotas/4b1eb781a47440acb8af9850103e537f/details.json":                 "neutron_quotas_1_usage",
-	"/shares/v2/shares/detail?all_tenants=true":                                      "manila_shares",
-}
-
 const DEFAULT_UUID = "3649e0f6-de80-ab6e-4f1c-351042d2f7fe"
 
 func (suite *BaseOpenStackTestSuite) SetupTest() {
@@ -170,11 +87,24 @@ func (suite *BaseOpenStackTestSuite) SetupTest() {
 
 	os.Setenv("OS_CLIENT_CONFIG_FILE", path.Join(baseFixturePath, "test_config.yaml"))
 
-	novaMetadataMapping := new(utils.LabelMappingFlag)
+	collectorErrors = make(map[collectorErrorKey]*collectorErrorStats)
+	timeNow = func() time.Time { return time.Unix(1700000000, 0) }
+
+	labelMappings := new(utils.ResourceLabelMappingFlag)
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
-	exporter, err := NewExporter(suite.ServiceName, suite.Prefix, cloudName, []string{}, "public", false, false, false, false, "", "", novaMetadataMapping, func() (string, error) {
-		return DEFAULT_UUID, nil
-	}, logger)
+	options := ExporterOptions{
+		Service:            suite.ServiceName,
+		Prefix:             suite.Prefix,
+		Cloud:              cloudName,
+		DisabledMetrics:    []string{},
+		EndpointType:       "public",
+		LabelMappings:      labelMappings,
+		CollectConcurrency: 4,
+		UUIDGenFunc: func() (string, error) {
+			return DEFAULT_UUID, nil
+		},
+	}
+	exporter, err := NewExporter(context.Background(), options, logger)
 
 	if err != nil {
 		panic(err)
@@ -191,7 +121,7 @@ func (suite *BaseOpenStackTestSuite) teardownFixtures() {
 }
 
 func (suite *BaseOpenStackTestSuite) installFixtures() {
-	for path, fixture := range fixtures {
+	for path, fixture := range fixtures.Requests {
 		suite.SetResponseFromFixture("GET", 200,
 			suite.MakeURL(path, ""),
 			suite.FixturePath(fixture),
@@ -214,6 +144,68 @@ func (suite *BaseOpenStackTestSuite) TearDownTest() {
 	defer httpmock.DeactivateAndReset()
 }
 
+// TestMetricGroups checks that the metrics without Fn are declared after the metric whose Fn collects them,
+// the metric filters rely on it to skip the Fn collecting only filtered metrics.
+func (suite *BaseOpenStackTestSuite) TestMetricGroups() {
+	exporter := reflect.ValueOf(*suite.Exporter).Elem().FieldByName("BaseOpenStackExporter").Addr().Interface().(*BaseOpenStackExporter)
+
+	for name, metric := range exporter.Metrics {
+		if metric.Fn == nil {
+			continue
+		}
+
+		ch := make(chan prometheus.Metric)
+		go func() {
+			defer close(ch)
+			_ = metric.Fn(context.Background(), exporter, ch)
+		}()
+		for collected := range ch {
+			described, ok := exporter.descs[collected.Desc()]
+			if suite.True(ok, "unknown metric %s collected by %s", collected.Desc(), name) {
+				suite.Equal(name, described.group, "metric %s should be declared after %s", collected.Desc(), name)
+			}
+		}
+	}
+}
+
+// lintExceptions are the known lint problems of the metric names and labels, kept for compatibility.
+var lintExceptions = map[string]string{
+	"openstack_cinder_agent_state":                      "label names should be written in 'snake_case' not 'camelCase'",
+	"openstack_neutron_agent_state":                     "label names should be written in 'snake_case' not 'camelCase'",
+	"openstack_nova_agent_state":                        "label names should be written in 'snake_case' not 'camelCase'",
+	"openstack_cinder_limits_backup_max_gb":             "metric names should not contain abbreviated units",
+	"openstack_cinder_limits_backup_used_gb":            "metric names should not contain abbreviated units",
+	"openstack_cinder_limits_volume_max_gb":             "metric names should not contain abbreviated units",
+	"openstack_cinder_limits_volume_used_gb":            "metric names should not contain abbreviated units",
+	"openstack_cinder_pool_capacity_free_gb":            "metric names should not contain abbreviated units",
+	"openstack_cinder_pool_capacity_total_gb":           "metric names should not contain abbreviated units",
+	"openstack_cinder_volume_gb":                        "metric names should not contain abbreviated units",
+	"openstack_nova_server_local_gb":                    "metric names should not contain abbreviated units",
+	"openstack_sharev2_share_gb":                        "metric names should not contain abbreviated units",
+	"openstack_trove_instance_volume_size_gb":           "metric names should not contain abbreviated units",
+	"openstack_trove_instance_volume_used_gb":           "metric names should not contain abbreviated units",
+	"openstack_cinder_volume_type_quota_gigabytes":      `use base unit "bytes" instead of "gigabytes"`,
+	"openstack_neutron_network_ip_availabilities_total": `non-counter metrics should not have "_total" suffix`,
+	"openstack_neutron_subnets_total":                   `non-counter metrics should not have "_total" suffix`,
+	"openstack_placement_resource_total":                `non-counter metrics should not have "_total" suffix`,
+}
+
+// TestMetricLint checks the collected metrics with the lint rules of promtool check metrics.
+func (suite *BaseOpenStackTestSuite) TestMetricLint() {
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(*suite.Exporter)
+	mfs, err := registry.Gather()
+	suite.Require().NoError(err)
+
+	problems, err := promlint.NewWithMetricFamilies(mfs).Lint()
+	suite.Require().NoError(err)
+	for _, problem := range problems {
+		if lintExceptions[problem.Metric] != problem.Text {
+			suite.Failf("lint problem", "%s: %s", problem.Metric, problem.Text)
+		}
+	}
+}
+
 func TestOpenStackSuites(t *testing.T) {
 	suite.Run(t, &CinderTestSuite{BaseOpenStackTestSuite: BaseOpenStackTestSuite{ServiceName: "volume"}})
 	suite.Run(t, &NovaTestSuite{BaseOpenStackTestSuite: BaseOpenStackTestSuite{ServiceName: "compute"}})
@@ -229,4 +221,577 @@ func TestOpenStackSuites(t *testing.T) {
 	suite.Run(t, &HeatTestSuite{BaseOpenStackTestSuite: BaseOpenStackTestSuite{ServiceName: "orchestration"}})
 	suite.Run(t, &PlacementTestSuite{BaseOpenStackTestSuite: BaseOpenStackTestSuite{ServiceName: "placement"}})
 	suite.Run(t, &ManilaTestSuite{BaseOpenStackTestSuite: BaseOpenStackTestSuite{ServiceName: "sharev2"}})
+	suite.Run(t, &ObjectStoreTestSuite{BaseOpenStackTestSuite: BaseOpenStackTestSuite{ServiceName: "object-store"}})
+}
+
+func TestCollectConcurrency(t *testing.T) {
+	var running, maxRunning atomic.Int32
+
+	slowListFunc := func(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			seen := maxRunning.Load()
+			if current <= seen || maxRunning.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}
+	failingListFunc := func(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+		return errors.New("collection failed")
+	}
+
+	for _, tc := range []struct {
+		name               string
+		collectConcurrency int
+		cloudConcurrency   int
+		expectedMax        int32
+	}{
+		{name: "sequential", collectConcurrency: 1, expectedMax: 1},
+		{name: "exporter limit", collectConcurrency: 3, expectedMax: 3},
+		{name: "cloud limit", collectConcurrency: 4, cloudConcurrency: 2, expectedMax: 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			running.Store(0)
+			maxRunning.Store(0)
+
+			exporter := BaseOpenStackExporter{
+				Name: "test",
+				ExporterConfig: ExporterConfig{
+					Cloud:                   tc.name,
+					Prefix:                  "openstack",
+					CollectConcurrency:      tc.collectConcurrency,
+					CloudCollectConcurrency: tc.cloudConcurrency,
+				},
+				logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+			}
+			for i := 0; i < 6; i++ {
+				exporter.AddMetric(fmt.Sprintf("metric_%d", i), slowListFunc, nil, "", nil)
+			}
+			exporter.AddMetric("failing", failingListFunc, nil, "", nil)
+
+			expected := `
+# HELP openstack_test_up Whether the OpenStack API of the service is up (1=up, 0=down)
+# TYPE openstack_test_up gauge
+openstack_test_up 1
+`
+			err := testutil.CollectAndCompare(&exporter, strings.NewReader(expected), "openstack_test_up")
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedMax, maxRunning.Load())
+		})
+	}
+}
+
+func TestCollectContextCanceled(t *testing.T) {
+	var called atomic.Int32
+
+	listFunc := func(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+		called.Add(1)
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	exporter := BaseOpenStackExporter{
+		Name: "test",
+		ExporterConfig: ExporterConfig{
+			Cloud:              "canceled",
+			Prefix:             "openstack",
+			CollectConcurrency: 1,
+		},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	for i := 0; i < 3; i++ {
+		exporter.AddMetric(fmt.Sprintf("metric_%d", i), listFunc, nil, "", nil)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	expected := `
+# HELP openstack_test_up Whether the OpenStack API of the service is up (1=up, 0=down)
+# TYPE openstack_test_up gauge
+openstack_test_up 0
+`
+	err := testutil.CollectAndCompare(WithContext(ctx, &exporter), strings.NewReader(expected), "openstack_test_up")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), called.Load(), "collections queued after the deadline should not run")
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err    error
+		reason string
+	}{
+		{gophercloud.ErrDefault401{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{Actual: 401}}, errorReasonUnauthorized},
+		{gophercloud.ErrDefault403{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{Actual: 403}}, errorReasonForbidden},
+		{fmt.Errorf("listing quotas: %w", gophercloud.ErrDefault404{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{Actual: 404}}), errorReasonNotFound},
+		{gophercloud.ErrDefault503{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{Actual: 503}}, errorReasonServerError},
+		{gophercloud.ErrUnexpectedResponseCode{Actual: 504}, errorReasonTimeout},
+		{fmt.Errorf("listing servers: %w", context.DeadlineExceeded), errorReasonTimeout},
+		{context.Canceled, errorReasonCanceled},
+		{&url.Error{Op: "Get", URL: "http://test.cloud/compute/v2.1/servers/detail", Err: ErrCircuitOpen}, errorReasonCircuitOpen},
+		{errors.New("boom"), errorReasonOther},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.reason, classifyError(test.err), test.err.Error())
+	}
+}
+
+func TestCollectorErrors(t *testing.T) {
+	collectorErrors = make(map[collectorErrorKey]*collectorErrorStats)
+	timeNow = func() time.Time { return time.Unix(1700000000, 0) }
+
+	exporter := BaseOpenStackExporter{
+		Name: "test",
+		ExporterConfig: ExporterConfig{
+			Cloud:              "errors",
+			Prefix:             "openstack",
+			CollectConcurrency: 1,
+		},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	exporter.AddMetric("ok", func(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+		return nil
+	}, nil, "", nil)
+	exporter.AddMetric("failing", func(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+		return gophercloud.ErrDefault403{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{Actual: 403}}
+	}, nil, "", nil)
+
+	// The error counter has to keep counting across collections.
+	testutil.CollectAndCount(&exporter)
+
+	expected := `
+# HELP openstack_collector_errors_total Number of failed collections of the metric from OpenStack API by reason
+# TYPE openstack_collector_errors_total counter
+openstack_collector_errors_total{metric="failing",reason="forbidden",service="test"} 2
+# HELP openstack_collector_last_error_timestamp_seconds Time of the last failed collection of the metric from OpenStack API
+# TYPE openstack_collector_last_error_timestamp_seconds gauge
+openstack_collector_last_error_timestamp_seconds{metric="failing",service="test"} 1.7e+09
+# HELP openstack_collector_success Whether the last collection of the metric from OpenStack API succeeded
+# TYPE openstack_collector_success gauge
+openstack_collector_success{metric="failing",service="test"} 0
+openstack_collector_success{metric="ok",service="test"} 1
+# HELP openstack_test_up Whether the OpenStack API of the service is up (1=up, 0=down)
+# TYPE openstack_test_up gauge
+openstack_test_up 1
+`
+	err := testutil.CollectAndCompare(&exporter, strings.NewReader(expected))
+	assert.NoError(t, err)
+}
+
+// mockCloud writes a clouds.yaml for the test cloud and mocks its API, it returns the path of clouds.yaml
+// and the counter of the token requests.
+func mockCloud(t *testing.T) (string, *atomic.Int32) {
+	config, err := os.ReadFile(path.Join(baseFixturePath, "test_config.yaml"))
+	assert.NoError(t, err)
+	configFile := path.Join(t.TempDir(), "clouds.yaml")
+	assert.NoError(t, os.WriteFile(configFile, config, 0o600))
+	t.Setenv("OS_CLIENT_CONFIG_FILE", configFile)
+
+	token, err := os.ReadFile(path.Join(baseFixturePath, "tokens.json"))
+	assert.NoError(t, err)
+	var tokenRequests atomic.Int32
+	httpmock.RegisterResponder("POST", fmt.Sprintf("http://%s:35357/v3/auth/tokens", cloudName),
+		func(req *http.Request) (*http.Response, error) {
+			tokenRequests.Add(1)
+			response := httpmock.NewBytesResponse(201, token)
+			response.Header.Set("Content-Type", "application/json")
+			response.Header.Set("X-Subject-Token", "1234")
+			return response, nil
+		},
+	)
+
+	for resource, fixture := range fixtures.Requests {
+		data, err := os.ReadFile(path.Join(baseFixturePath, fixture+".json"))
+		assert.NoError(t, err)
+		httpmock.RegisterResponder("GET", fmt.Sprintf("http://%s%s", cloudName, resource), httpmock.NewBytesResponder(200, data))
+	}
+	return configFile, &tokenRequests
+}
+
+func TestEnableExporterReusesClients(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	ResetExporters()
+	defer ResetExporters()
+
+	configFile, tokenRequests := mockCloud(t)
+	config, err := os.ReadFile(configFile)
+	assert.NoError(t, err)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	RefreshClouds(logger)
+	enable := func(service string) OpenStackExporter {
+		exporter, err := EnableExporter(context.Background(), ExporterOptions{Service: service, Prefix: "openstack", Cloud: cloudName, DisabledMetrics: []string{}, EndpointType: "public", LabelMappings: new(utils.ResourceLabelMappingFlag), CollectConcurrency: 4}, logger)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return *exporter
+	}
+
+	volume := enable("volume")
+	enable("network")
+	assert.Same(t, volume, enable("volume"), "the exporter should be reused across scrapes")
+	assert.Equal(t, int32(2), tokenRequests.Load(), "the services of a cloud should share its provider clients")
+
+	// The options of the exporter changed, i.e: by a reload of the configuration.
+	filter := func() *utils.MetricFilter {
+		return &utils.MetricFilter{Deny: []*regexp.Regexp{regexp.MustCompile("^openstack_cinder_volumes$")}}
+	}
+	filtered, err := EnableExporter(context.Background(), ExporterOptions{Service: "volume", Prefix: "openstack", Cloud: cloudName, DisabledMetrics: []string{}, EndpointType: "public", LabelMappings: new(utils.ResourceLabelMappingFlag), MetricFilter: filter(), CollectConcurrency: 4}, logger)
+	require.NoError(t, err)
+	assert.NotSame(t, volume, *filtered, "the exporter should be rebuilt when its options change")
+	again, err := EnableExporter(context.Background(), ExporterOptions{Service: "volume", Prefix: "openstack", Cloud: cloudName, DisabledMetrics: []string{}, EndpointType: "public", LabelMappings: new(utils.ResourceLabelMappingFlag), MetricFilter: filter(), CollectConcurrency: 4}, logger)
+	require.NoError(t, err)
+	assert.Same(t, *filtered, *again, "the exporter should be reused with the same options")
+	volume = enable("volume")
+	assert.NotSame(t, *filtered, volume)
+	assert.Equal(t, int32(2), tokenRequests.Load(), "the provider clients should be kept when the options change")
+
+	// A change of clouds.yaml rebuilds the clients and the exporters.
+	assert.NoError(t, os.WriteFile(configFile, append(config, []byte("# changed\n")...), 0o600))
+	assert.Same(t, volume, enable("volume"), "clouds.yaml should only be compared once per scrape")
+	RefreshClouds(logger)
+	assert.NotSame(t, volume, enable("volume"), "the exporter should be rebuilt once clouds.yaml changed")
+	assert.Equal(t, int32(4), tokenRequests.Load())
+}
+
+func TestEnableExporterWaitsForAuthentication(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	ResetExporters()
+	defer ResetExporters()
+
+	_, tokenRequests := mockCloud(t)
+	tokenResponder, err := httpmock.NewJsonResponder(201, nil)
+	require.NoError(t, err)
+	token, err := os.ReadFile(path.Join(baseFixturePath, "tokens.json"))
+	require.NoError(t, err)
+	started, release := make(chan struct{}), make(chan struct{})
+	httpmock.RegisterResponder("POST", fmt.Sprintf("http://%s:35357/v3/auth/tokens", cloudName),
+		func(req *http.Request) (*http.Response, error) {
+			if tokenRequests.Add(1) == 1 {
+				close(started)
+				<-release
+			}
+			response, _ := tokenResponder(req)
+			response.Body = httpmock.NewRespBodyFromBytes(token)
+			response.Header.Set("X-Subject-Token", "1234")
+			return response, nil
+		},
+	)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	enable := func(ctx context.Context, service string) (*OpenStackExporter, error) {
+		return EnableExporter(ctx, ExporterOptions{Service: service, Prefix: "openstack", Cloud: cloudName, DisabledMetrics: []string{}, EndpointType: "public", LabelMappings: new(utils.ResourceLabelMappingFlag), CollectConcurrency: 4}, logger)
+	}
+
+	volume := make(chan error)
+	go func() {
+		_, err := enable(context.Background(), "volume")
+		volume <- err
+	}()
+	<-started
+
+	// A scrape waiting for the authentication in progress gives up with its context.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = enable(ctx, "network")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	close(release)
+	require.NoError(t, <-volume)
+	_, err = enable(context.Background(), "network")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), tokenRequests.Load(), "the services of a cloud should share its provider clients")
+}
+
+func TestCollectedRegions(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	ResetExporters()
+	defer ResetExporters()
+
+	configFile, _ := mockCloud(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	regions, err := CollectedRegions(context.Background(), cloudName, false, false, "public", logger)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{""}, regions, "the region label should be disabled by default")
+
+	regions, err = CollectedRegions(context.Background(), cloudName, false, true, "public", logger)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"RegionOne"}, regions)
+
+	regions, err = CollectedRegions(context.Background(), cloudName, true, false, "internal", logger)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"RegionOne"}, regions)
+
+	// Without region_name, the region comes from the catalog.
+	config, err := os.ReadFile(configFile)
+	assert.NoError(t, err)
+	config = []byte(strings.Replace(string(config), "region_name: RegionOne", "", 1))
+	assert.NoError(t, os.WriteFile(configFile, config, 0o600))
+
+	regions, err = CollectedRegions(context.Background(), cloudName, false, true, "public", logger)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"RegionOne"}, regions)
+
+	exporter, err := EnableExporter(context.Background(), ExporterOptions{Service: "volume", Prefix: "openstack", Cloud: cloudName, Region: "RegionOne", DisabledMetrics: []string{}, EndpointType: "public", LabelMappings: new(utils.ResourceLabelMappingFlag), CollectConcurrency: 4}, logger)
+	assert.NoError(t, err)
+	assert.Equal(t, "RegionOne", (*exporter).(*CinderExporter).Region)
+}
+
+func TestCatalogRegions(t *testing.T) {
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"token": {"catalog": [
+		{"type": "compute", "endpoints": [
+			{"interface": "public", "region_id": "RegionOne", "url": "http://one/compute"},
+			{"interface": "internal", "region_id": "RegionOne", "url": "http://one.internal/compute"},
+			{"interface": "public", "region_id": "RegionTwo", "url": "http://two/compute"}
+		]},
+		{"type": "network", "endpoints": [
+			{"interface": "public", "region": "RegionThree", "url": "http://three/network"},
+			{"interface": "public", "region_id": "RegionOne", "url": "http://one/network"}
+		]}
+	]}}`), &body))
+	result := tokens3.CreateResult{}
+	result.Body = body
+
+	provider := &gophercloud.ProviderClient{}
+	assert.NoError(t, provider.SetTokenAndAuthResult(result))
+
+	regions, err := catalogRegions(provider, "public")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"RegionOne", "RegionThree", "RegionTwo"}, regions)
+
+	regions, err = catalogRegions(provider, "internal")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"RegionOne"}, regions)
+}
+
+func TestRegionLabel(t *testing.T) {
+	collectorErrors = make(map[collectorErrorKey]*collectorErrorStats)
+
+	exporter := BaseOpenStackExporter{
+		Name: "test",
+		ExporterConfig: ExporterConfig{
+			Cloud:              "regions",
+			Region:             "RegionTwo",
+			Prefix:             "openstack",
+			CollectConcurrency: 1,
+		},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	exporter.AddMetric("total", func(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+		ch <- prometheus.MustNewConstMetric(exporter.Metrics["total"].Metric, prometheus.GaugeValue, 1)
+		return nil
+	}, nil, "", nil)
+	exporter.AddMetric("instance", func(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+		ch <- prometheus.MustNewConstMetric(exporter.Metrics["instance"].Metric, prometheus.GaugeValue, 1, "RegionOne")
+		return nil
+	}, []string{"region"}, "", nil)
+
+	expected := `
+# HELP openstack_collector_success Whether the last collection of the metric from OpenStack API succeeded
+# TYPE openstack_collector_success gauge
+openstack_collector_success{metric="instance",region="RegionTwo",service="test"} 1
+openstack_collector_success{metric="total",region="RegionTwo",service="test"} 1
+# HELP openstack_test_instance instance
+# TYPE openstack_test_instance gauge
+openstack_test_instance{region="RegionOne"} 1
+# HELP openstack_test_total total
+# TYPE openstack_test_total gauge
+openstack_test_total{region="RegionTwo"} 1
+# HELP openstack_test_up Whether the OpenStack API of the service is up (1=up, 0=down)
+# TYPE openstack_test_up gauge
+openstack_test_up{region="RegionTwo"} 1
+`
+	err := testutil.CollectAndCompare(&exporter, strings.NewReader(expected))
+	assert.NoError(t, err)
+}
+
+func TestMetricFilter(t *testing.T) {
+	collectorErrors = make(map[collectorErrorKey]*collectorErrorStats)
+
+	deny, err := utils.CompilePatterns([]string{"openstack_test_port", "openstack_test_router.*"})
+	assert.NoError(t, err)
+	dropStatus, err := utils.NewLabelRule("openstack_test_network", utils.LabelDrop, "status")
+	assert.NoError(t, err)
+
+	exporter := BaseOpenStackExporter{
+		Name: "test",
+		ExporterConfig: ExporterConfig{
+			Cloud:              "filters",
+			Prefix:             "openstack",
+			CollectConcurrency: 1,
+			MetricFilter:       &utils.MetricFilter{Deny: deny, LabelRules: []utils.LabelRule{dropStatus}},
+		},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	var routersListed atomic.Bool
+	exporter.AddMetric("port", func(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+		ch <- prometheus.MustNewConstMetric(exporter.Metrics["port"].Metric, prometheus.GaugeValue, 1, "a")
+		ch <- prometheus.MustNewConstMetric(exporter.Metrics["port"].Metric, prometheus.GaugeValue, 1, "b")
+		ch <- prometheus.MustNewConstMetric(exporter.Metrics["ports"].Metric, prometheus.GaugeValue, 2)
+		return nil
+	}, []string{"id"}, "", nil)
+	exporter.AddMetric("ports", nil, nil, "", nil)
+	exporter.AddMetric("network", func(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+		ch <- prometheus.MustNewConstMetric(exporter.Metrics["network"].Metric, prometheus.GaugeValue, 1, "a", "ACTIVE")
+		return nil
+	}, []string{"id", "status"}, "", nil)
+	exporter.AddMetric("routers", func(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+		routersListed.Store(true)
+		return nil
+	}, nil, "", nil)
+	exporter.AddMetric("router", nil, []string{"id"}, "", nil)
+
+	expected := `
+# HELP openstack_collector_success Whether the last collection of the metric from OpenStack API succeeded
+# TYPE openstack_collector_success gauge
+openstack_collector_success{metric="network",service="test"} 1
+openstack_collector_success{metric="port",service="test"} 1
+# HELP openstack_test_network network
+# TYPE openstack_test_network gauge
+openstack_test_network{id="a"} 1
+# HELP openstack_test_ports ports
+# TYPE openstack_test_ports gauge
+openstack_test_ports 2
+# HELP openstack_test_up Whether the OpenStack API of the service is up (1=up, 0=down)
+# TYPE openstack_test_up gauge
+openstack_test_up 1
+`
+	err = testutil.CollectAndCompare(&exporter, strings.NewReader(expected))
+	assert.NoError(t, err)
+	assert.False(t, routersListed.Load(), "the routers should not be listed when all their metrics are filtered")
+}
+
+func TestMetricFilterMergesSeries(t *testing.T) {
+	collectorErrors = make(map[collectorErrorKey]*collectorErrorStats)
+
+	dropID, err := utils.NewLabelRule("openstack_test_server_status", utils.LabelDrop, "id")
+	assert.NoError(t, err)
+
+	exporter := BaseOpenStackExporter{
+		Name: "test",
+		ExporterConfig: ExporterConfig{
+			Cloud:              "merges",
+			Prefix:             "openstack",
+			CollectConcurrency: 1,
+			MetricFilter:       &utils.MetricFilter{LabelRules: []utils.LabelRule{dropID}},
+		},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	exporter.AddMetric("server_status", func(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+		for _, server := range [][]string{{"a", "ACTIVE"}, {"b", "ERROR"}, {"c", "ACTIVE"}} {
+			ch <- prometheus.MustNewConstMetric(exporter.Metrics["server_status"].Metric, prometheus.GaugeValue, 1, server...)
+		}
+		return nil
+	}, []string{"id", "status"}, "", nil)
+
+	expected := `
+# HELP openstack_collector_success Whether the last collection of the metric from OpenStack API succeeded
+# TYPE openstack_collector_success gauge
+openstack_collector_success{metric="server_status",service="test"} 1
+# HELP openstack_test_server_status server_status
+# TYPE openstack_test_server_status gauge
+openstack_test_server_status{status="ACTIVE"} 2
+openstack_test_server_status{status="ERROR"} 1
+# HELP openstack_test_up Whether the OpenStack API of the service is up (1=up, 0=down)
+# TYPE openstack_test_up gauge
+openstack_test_up 1
+`
+	// The pedantic registry fails the scrape when a series is collected twice.
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(&exporter))
+	err = testutil.GatherAndCompare(registry, strings.NewReader(expected))
+	assert.NoError(t, err, "the series of the servers should be summed once their id is dropped")
+}
+
+func TestSeriesLimit(t *testing.T) {
+	collectorErrors = make(map[collectorErrorKey]*collectorErrorStats)
+
+	ports, err := utils.CompilePattern("openstack_test_port")
+	assert.NoError(t, err)
+
+	exporter := BaseOpenStackExporter{
+		Name: "test",
+		ExporterConfig: ExporterConfig{
+			Cloud:              "limits",
+			Prefix:             "openstack",
+			CollectConcurrency: 1,
+			MetricFilter:       &utils.MetricFilter{SeriesLimit: 2, SeriesLimits: []utils.SeriesLimit{{Metrics: ports, Limit: 1}}},
+		},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	exporter.AddMetric("port", func(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+		for _, id := range []string{"a", "b", "c"} {
+			ch <- prometheus.MustNewConstMetric(exporter.Metrics["port"].Metric, prometheus.GaugeValue, 1, id)
+		}
+		ch <- prometheus.MustNewConstMetric(exporter.Metrics["ports"].Metric, prometheus.GaugeValue, 3)
+		return nil
+	}, []string{"id"}, "", nil)
+	exporter.AddMetric("ports", nil, nil, "", nil)
+	exporter.AddMetric("network", func(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+		ch <- prometheus.MustNewConstMetric(exporter.Metrics["network"].Metric, prometheus.GaugeValue, 1, "a")
+		ch <- prometheus.MustNewConstMetric(exporter.Metrics["network"].Metric, prometheus.GaugeValue, 1, "b")
+		return nil
+	}, []string{"id"}, "", nil)
+	exporter.AddMetric("network_status_counter", func(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+		for _, status := range []string{"ACTIVE", "BUILD", "DOWN"} {
+			ch <- prometheus.MustNewConstMetric(exporter.Metrics["network_status_counter"].Metric, prometheus.GaugeValue, 1, status)
+		}
+		return nil
+	}, []string{"status"}, "", nil)
+
+	expected := `
+# HELP openstack_collector_success Whether the last collection of the metric from OpenStack API succeeded
+# TYPE openstack_collector_success gauge
+openstack_collector_success{metric="network",service="test"} 1
+openstack_collector_success{metric="network_status_counter",service="test"} 1
+openstack_collector_success{metric="port",service="test"} 1
+# HELP openstack_series_limit_exceeded Number of series of the metric not exported as they exceeded its series limit
+# TYPE openstack_series_limit_exceeded gauge
+openstack_series_limit_exceeded{metric="openstack_test_port",service="test"} 3
+# HELP openstack_test_network network
+# TYPE openstack_test_network gauge
+openstack_test_network{id="a"} 1
+openstack_test_network{id="b"} 1
+# HELP openstack_test_network_status_counter network_status_counter
+# TYPE openstack_test_network_status_counter gauge
+openstack_test_network_status_counter{status="ACTIVE"} 1
+openstack_test_network_status_counter{status="BUILD"} 1
+openstack_test_network_status_counter{status="DOWN"} 1
+# HELP openstack_test_ports ports
+# TYPE openstack_test_ports gauge
+openstack_test_ports 3
+# HELP openstack_test_up Whether the OpenStack API of the service is up (1=up, 0=down)
+# TYPE openstack_test_up gauge
+openstack_test_up 1
+`
+	err = testutil.CollectAndCompare(&exporter, strings.NewReader(expected))
+	assert.NoError(t, err, "the default series limit should not apply to the counts")
+}
+
+func TestLabelMappings(t *testing.T) {
+	mappings := new(utils.ResourceLabelMappingFlag)
+	assert.NoError(t, mappings.Set("cinder.volume:cost_center=cost-center"))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	exporter, err := NewCinderExporter(context.Background(), &ExporterConfig{Prefix: "openstack", LabelMappings: mappings}, logger)
+	assert.NoError(t, err)
+	assert.Contains(t, exporter.Metrics["volume_gb"].Metric.String(), `variableLabels: {id,name,status,availability_zone,bootable,tenant_id,user_id,volume_type,server_id,cost_center}`)
+	assert.Contains(t, exporter.Metrics["volumes"].Metric.String(), `variableLabels: {}`, "the mapping should only apply to the metrics of the volumes")
+
+	assert.NoError(t, mappings.Set("neutron.port:status"))
+	_, err = NewNeutronExporter(context.Background(), &ExporterConfig{Prefix: "openstack", LabelMappings: mappings}, logger)
+	assert.ErrorIs(t, err, utils.ErrLabelDup)
+	assert.EqualError(t, err, "neutron.port: duplicate label: status")
 }
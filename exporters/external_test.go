@@ -0,0 +1,77 @@
+package exporters_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/openstack-exporter/openstack-exporter/exporters"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var secretStates = []string{"ACTIVE", "ERROR"}
+
+// secretExporter is an exporter of another package, built from the exported API only.
+type secretExporter struct {
+	*exporters.BaseOpenStackExporter
+}
+
+func newSecretExporter(ctx context.Context, config *exporters.ExporterConfig, logger *slog.Logger) (exporters.OpenStackExporter, error) {
+	exporter := secretExporter{exporters.NewBaseOpenStackExporter("barbican", config, logger)}
+	for _, metric := range []exporters.Metric{
+		{Name: "secrets", Fn: listSecrets, Help: "Number of secrets", Type: prometheus.GaugeValue},
+		{Name: "secret_status", Labels: []string{"id"}, States: secretStates, Help: "Secret status, the index of the status in the known statuses", Type: prometheus.GaugeValue},
+		{Name: "secret_payload_bytes", Labels: []string{"id"}, Help: "Size of the payload of the secret in bytes", Type: prometheus.GaugeValue, Unit: "bytes"},
+		{Name: "secret_expired", Labels: []string{"id"}, DeprecatedVersion: "1.7", Help: "Whether the secret expired", Type: prometheus.GaugeValue},
+		{Name: "orders", Fn: nil, Help: "Number of orders", Type: prometheus.CounterValue},
+	} {
+		exporter.AddMetricDefinition(metric, nil)
+	}
+	return exporter, nil
+}
+
+func listSecrets(ctx context.Context, exporter *exporters.BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+	ch <- prometheus.MustNewConstMetric(exporter.Metrics["secrets"].Metric, exporter.Metrics["secrets"].Type, 2)
+	exporter.SendStatus(ch, "secret_status", secretStates, 1, "ERROR", "a")
+	exporter.SendStatus(ch, "secret_status", secretStates, -1, "PENDING", "b")
+	return nil
+}
+
+func TestExternalExporter(t *testing.T) {
+	config := &exporters.ExporterConfig{
+		Cloud:                    "external",
+		Prefix:                   "openstack",
+		DisabledMetrics:          []string{"barbican-orders"},
+		DisableDeprecatedMetrics: true,
+		CollectConcurrency:       1,
+		StateSetStatus:           true,
+	}
+	exporter, err := newSecretExporter(context.Background(), config, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	assert.Equal(t, "openstack_barbican", exporter.GetName())
+	assert.True(t, exporter.MetricIsDisabled("orders"))
+
+	expected := `
+# HELP openstack_barbican_secret_status Secret status, one series per known status set to 1 for the current status and 0 for the others
+# TYPE openstack_barbican_secret_status gauge
+openstack_barbican_secret_status{id="a",openstack_barbican_secret_status="ACTIVE"} 0
+openstack_barbican_secret_status{id="a",openstack_barbican_secret_status="ERROR"} 1
+openstack_barbican_secret_status{id="b",openstack_barbican_secret_status="ACTIVE"} 0
+openstack_barbican_secret_status{id="b",openstack_barbican_secret_status="ERROR"} 0
+openstack_barbican_secret_status{id="b",openstack_barbican_secret_status="PENDING"} 1
+# HELP openstack_barbican_secrets Number of secrets
+# TYPE openstack_barbican_secrets gauge
+openstack_barbican_secrets 2
+# HELP openstack_barbican_up Whether the OpenStack API of the service is up (1=up, 0=down)
+# TYPE openstack_barbican_up gauge
+openstack_barbican_up 1
+`
+	err = testutil.CollectAndCompare(exporter, strings.NewReader(expected), "openstack_barbican_secret_status", "openstack_barbican_secrets", "openstack_barbican_up")
+	assert.NoError(t, err)
+
+}
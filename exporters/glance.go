@@ -1,6 +1,8 @@
 package exporters
 
 import (
+	"context"
+	"fmt"
 	"strconv"
 
 	"log/slog"
@@ -14,12 +16,12 @@ type GlanceExporter struct {
 }
 
 var defaultGlanceMetrics = []Metric{
-	{Name: "images", Fn: ListImages},
-	{Name: "image_bytes", Labels: []string{"id", "name", "tenant_id"}, Fn: ListImageProperties, Slow: true},
-	{Name: "image_created_at", Labels: []string{"id", "name", "tenant_id", "visibility", "hidden", "status"}, Slow: true},
+	{Name: "images", Fn: ListImages, Help: "Total number of images", Type: prometheus.GaugeValue},
+	{Name: "image_bytes", Labels: []string{"id", "name", "tenant_id"}, Fn: ListImageProperties, Slow: true, Help: "Image size in bytes", Type: prometheus.GaugeValue, Unit: "bytes"},
+	{Name: "image_created_at", Labels: []string{"id", "name", "tenant_id", "visibility", "hidden", "status"}, Slow: true, Help: "Image creation time in seconds since the epoch", Type: prometheus.GaugeValue, Unit: "seconds"},
 }
 
-func NewGlanceExporter(config *ExporterConfig, logger *slog.Logger) (*GlanceExporter, error) {
+func NewGlanceExporter(ctx context.Context, config *ExporterConfig, logger *slog.Logger) (*GlanceExporter, error) {
 	exporter := GlanceExporter{
 		BaseOpenStackExporter{
 			Name:           "glance",
@@ -29,11 +31,18 @@ func NewGlanceExporter(config *ExporterConfig, logger *slog.Logger) (*GlanceExpo
 	}
 
 	for _, metric := range defaultGlanceMetrics {
+		if metric.Name == "image_bytes" || metric.Name == "image_created_at" {
+			labels, err := exporter.mappedLabels("glance.image", metric.Labels)
+			if err != nil {
+				return nil, err
+			}
+			metric.Labels = labels
+		}
 		if exporter.isDeprecatedMetric(&metric) {
 			continue
 		}
 		if !exporter.isSlowMetric(&metric) {
-			exporter.AddMetric(metric.Name, metric.Fn, metric.Labels, metric.DeprecatedVersion, nil)
+			exporter.addMetric(metric, nil)
 		}
 	}
 
@@ -55,19 +64,19 @@ func getAllImages(exporter *BaseOpenStackExporter) ([]images.Image, error) {
 	return allImages, nil
 }
 
-func ListImages(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListImages(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	allImages, err := getAllImages(exporter)
 	if err != nil {
 		return err
 	}
 
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["images"].Metric,
-		prometheus.GaugeValue, float64(len(allImages)))
+		exporter.Metrics["images"].Type, float64(len(allImages)))
 
 	return nil
 }
 
-func ListImageProperties(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListImageProperties(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	// Image size and created at metrics
 	allImages, err := getAllImages(exporter)
 	if err != nil {
@@ -75,14 +84,28 @@ func ListImageProperties(exporter *BaseOpenStackExporter, ch chan<- prometheus.M
 	}
 
 	for _, image := range allImages {
+		propertyValues := exporter.LabelMappings.Get("glance.image").Extract(imageProperties(image))
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["image_bytes"].Metric,
-			prometheus.GaugeValue, float64(image.SizeBytes), image.ID, image.Name,
-			image.Owner)
+			exporter.Metrics["image_bytes"].Type, float64(image.SizeBytes), append([]string{image.ID, image.Name,
+				image.Owner}, propertyValues...)...)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["image_created_at"].Metric,
-			prometheus.GaugeValue, float64(image.CreatedAt.Unix()), image.ID, image.Name,
-			image.Owner, string(image.Visibility), strconv.FormatBool(image.Hidden), string(image.Status))
+			exporter.Metrics["image_created_at"].Type, float64(image.CreatedAt.Unix()), append([]string{image.ID, image.Name,
+				image.Owner, string(image.Visibility), strconv.FormatBool(image.Hidden), string(image.Status)}, propertyValues...)...)
 
 	}
 
 	return nil
 }
+
+// imageProperties returns the additional properties of the image as strings.
+func imageProperties(image images.Image) map[string]string {
+	properties := make(map[string]string, len(image.Properties))
+	for key, value := range image.Properties {
+		if str, ok := value.(string); ok {
+			properties[key] = str
+		} else {
+			properties[key] = fmt.Sprint(value)
+		}
+	}
+	return properties
+}
@@ -12,18 +12,22 @@ type GlanceTestSuite struct {
 }
 
 var glanceExpectedUp = `
-# HELP openstack_glance_image_bytes image_bytes
+# HELP openstack_collector_success Whether the last collection of the metric from OpenStack API succeeded
+# TYPE openstack_collector_success gauge
+openstack_collector_success{metric="image_bytes",service="glance"} 1
+openstack_collector_success{metric="images",service="glance"} 1
+# HELP openstack_glance_image_bytes Image size in bytes
 # TYPE openstack_glance_image_bytes gauge
 openstack_glance_image_bytes{id="781b3762-9469-4cec-b58d-3349e5de4e9c",name="F17-x86_64-cfntools",tenant_id="5ef70662f8b34079a6eddb8da9d75fe8"} 4.76704768e+08
 openstack_glance_image_bytes{id="1bea47ed-f6a9-463b-b423-14b9cca9ad27",name="cirros-0.3.2-x86_64-disk",tenant_id="5ef70662f8b34079a6eddb8da9d75fe8"} 1.3167616e+07
-# HELP openstack_glance_image_created_at image_created_at
+# HELP openstack_glance_image_created_at Image creation time in seconds since the epoch
 # TYPE openstack_glance_image_created_at gauge
 openstack_glance_image_created_at{hidden="false",id="781b3762-9469-4cec-b58d-3349e5de4e9c",name="F17-x86_64-cfntools",status="active",tenant_id="5ef70662f8b34079a6eddb8da9d75fe8",visibility="public"} 1.414657419e+09
 openstack_glance_image_created_at{hidden="false",id="1bea47ed-f6a9-463b-b423-14b9cca9ad27",name="cirros-0.3.2-x86_64-disk",status="active",tenant_id="5ef70662f8b34079a6eddb8da9d75fe8",visibility="public"} 1.415380026e+09
-# HELP openstack_glance_images images
+# HELP openstack_glance_images Total number of images
 # TYPE openstack_glance_images gauge
 openstack_glance_images 2
-# HELP openstack_glance_up up
+# HELP openstack_glance_up Whether the OpenStack API of the service is up (1=up, 0=down)
 # TYPE openstack_glance_up gauge
 openstack_glance_up 1
 `
@@ -1,6 +1,7 @@
 package exporters
 
 import (
+	"context"
 	"log/slog"
 
 	"github.com/gophercloud/utils/gnocchi/metric/v1/metrics"
@@ -13,13 +14,13 @@ type GnocchiExporter struct {
 }
 
 var defaultGnocchiMetrics = []Metric{
-	{Name: "status_metricd_processors", Fn: getMetricStatus},
-	{Name: "status_metric_having_measures_to_process", Fn: nil},
-	{Name: "status_measures_to_process", Fn: nil},
-	{Name: "total_metrics", Fn: ListAllMetrics},
+	{Name: "status_metricd_processors", Fn: getMetricStatus, Help: "Number of metricd processors", Type: prometheus.GaugeValue},
+	{Name: "status_metric_having_measures_to_process", Fn: nil, Help: "Number of metrics having measures to process", Type: prometheus.GaugeValue},
+	{Name: "status_measures_to_process", Fn: nil, Help: "Number of measures to process", Type: prometheus.GaugeValue},
+	{Name: "total_metrics", Fn: ListAllMetrics, Help: "Total number of metrics", Type: prometheus.GaugeValue},
 }
 
-func NewGnocchiExporter(config *ExporterConfig, logger *slog.Logger) (*GnocchiExporter, error) {
+func NewGnocchiExporter(ctx context.Context, config *ExporterConfig, logger *slog.Logger) (*GnocchiExporter, error) {
 	exporter := GnocchiExporter{
 		BaseOpenStackExporter{
 			Name:           "gnocchi",
@@ -32,13 +33,13 @@ func NewGnocchiExporter(config *ExporterConfig, logger *slog.Logger) (*GnocchiEx
 			continue
 		}
 		if !exporter.isSlowMetric(&metric) {
-			exporter.AddMetric(metric.Name, metric.Fn, metric.Labels, metric.DeprecatedVersion, nil)
+			exporter.addMetric(metric, nil)
 		}
 	}
 	return &exporter, nil
 }
 
-func ListAllMetrics(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListAllMetrics(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allMetrics []metrics.Metric
 	allPagesMetrics, err := metrics.List(exporter.Client, metrics.ListOpts{}).AllPages()
 	if err != nil {
@@ -49,12 +50,12 @@ func ListAllMetrics(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric
 		return err
 	}
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["total_metrics"].Metric,
-		prometheus.GaugeValue, float64(len(allMetrics)))
+		exporter.Metrics["total_metrics"].Type, float64(len(allMetrics)))
 
 	return nil
 }
 
-func getMetricStatus(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func getMetricStatus(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	details := true
 	metricStatus, err := status.Get(exporter.Client, status.GetOpts{Details: &details}).Extract()
 	if err != nil {
@@ -62,11 +63,11 @@ func getMetricStatus(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metri
 	}
 
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["status_metricd_processors"].Metric,
-		prometheus.GaugeValue, float64(len(metricStatus.Metricd.Processors)))
+		exporter.Metrics["status_metricd_processors"].Type, float64(len(metricStatus.Metricd.Processors)))
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["status_metric_having_measures_to_process"].Metric,
-		prometheus.GaugeValue, float64(metricStatus.Storage.Summary.Metrics))
+		exporter.Metrics["status_metric_having_measures_to_process"].Type, float64(metricStatus.Storage.Summary.Metrics))
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["status_measures_to_process"].Metric,
-		prometheus.GaugeValue, float64(metricStatus.Storage.Summary.Measures))
+		exporter.Metrics["status_measures_to_process"].Type, float64(metricStatus.Storage.Summary.Measures))
 
 	return nil
 }
@@ -15,16 +15,26 @@ type GnocchiTestSuite struct {
 }
 
 var gnocchiExpectedUp = `
-# HELP openstack_gnocchi_status_measures_to_process status_measures_to_process
+# HELP openstack_collector_errors_total Number of failed collections of the metric from OpenStack API by reason
+# TYPE openstack_collector_errors_total counter
+openstack_collector_errors_total{metric="total_metrics",reason="other",service="gnocchi"} 1
+# HELP openstack_collector_last_error_timestamp_seconds Time of the last failed collection of the metric from OpenStack API
+# TYPE openstack_collector_last_error_timestamp_seconds gauge
+openstack_collector_last_error_timestamp_seconds{metric="total_metrics",service="gnocchi"} 1.7e+09
+# HELP openstack_collector_success Whether the last collection of the metric from OpenStack API succeeded
+# TYPE openstack_collector_success gauge
+openstack_collector_success{metric="status_metricd_processors",service="gnocchi"} 1
+openstack_collector_success{metric="total_metrics",service="gnocchi"} 0
+# HELP openstack_gnocchi_status_measures_to_process Number of measures to process
 # TYPE openstack_gnocchi_status_measures_to_process gauge
 openstack_gnocchi_status_measures_to_process 0
-# HELP openstack_gnocchi_status_metric_having_measures_to_process status_metric_having_measures_to_process
+# HELP openstack_gnocchi_status_metric_having_measures_to_process Number of metrics having measures to process
 # TYPE openstack_gnocchi_status_metric_having_measures_to_process gauge
 openstack_gnocchi_status_metric_having_measures_to_process 0
-# HELP openstack_gnocchi_status_metricd_processors status_metricd_processors
+# HELP openstack_gnocchi_status_metricd_processors Number of metricd processors
 # TYPE openstack_gnocchi_status_metricd_processors gauge
 openstack_gnocchi_status_metricd_processors 0
-# HELP openstack_gnocchi_up up
+# HELP openstack_gnocchi_up Whether the OpenStack API of the service is up (1=up, 0=down)
 # TYPE openstack_gnocchi_up gauge
 openstack_gnocchi_up 1
 `
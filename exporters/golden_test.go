@@ -0,0 +1,63 @@
+package exporters
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files of testdata with the gathered metrics")
+
+const goldenPath = "testdata"
+
+func goldenFile(service string) string {
+	return filepath.Join(goldenPath, service+".prom")
+}
+
+// TestGolden compares the metrics gathered from the exporter with its fixtures to the golden file
+// of the service, testdata/<service>.prom. go test -update rewrites the golden files.
+func (suite *BaseOpenStackTestSuite) TestGolden() {
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(*suite.Exporter)
+	mfs, err := registry.Gather()
+	suite.Require().NoError(err)
+
+	var buf bytes.Buffer
+	encoder := expfmt.NewEncoder(&buf, expfmt.NewFormat(expfmt.TypeTextPlain))
+	for _, mf := range mfs {
+		suite.Require().NoError(encoder.Encode(mf))
+	}
+
+	path := goldenFile(suite.ServiceName)
+	if *update {
+		suite.Require().NoError(os.MkdirAll(goldenPath, 0o755))
+		suite.Require().NoError(os.WriteFile(path, buf.Bytes(), 0o644))
+		return
+	}
+	golden, err := os.ReadFile(path)
+	suite.Require().NoError(err, "run go test ./exporters -update to create the golden file")
+	suite.Equal(string(golden), buf.String(), "the metrics changed, run go test ./exporters -update to update %s", path)
+}
+
+// TestGoldenFiles checks that every service has a golden file, so that every exporter has a suite
+// in TestOpenStackSuites, and that the golden files are the ones of services.
+func TestGoldenFiles(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join(goldenPath, "*.prom"))
+	require.NoError(t, err)
+	services := []string{}
+	for _, file := range files {
+		services = append(services, strings.TrimSuffix(filepath.Base(file), ".prom"))
+	}
+	expected := Services()
+	slices.Sort(expected)
+	assert.Equal(t, expected, services)
+}
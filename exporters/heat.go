@@ -1,6 +1,7 @@
 package exporters
 
 import (
+	"context"
 	"log/slog"
 
 	"github.com/gophercloud/gophercloud/openstack/orchestration/v1/stacks"
@@ -72,11 +73,11 @@ type HeatExporter struct {
 }
 
 var defaultHeatMetrics = []Metric{
-	{Name: "stack_status", Labels: []string{"id", "name", "project_id", "status"}, Fn: ListAllStacks},
-	{Name: "stack_status_counter", Labels: []string{"status"}, Fn: nil},
+	{Name: "stack_status", Labels: []string{"id", "name", "project_id", "status"}, Fn: ListAllStacks, States: stack_status, Help: "Heat stack status, the index of the status in the known stack statuses", Type: prometheus.GaugeValue},
+	{Name: "stack_status_counter", Labels: []string{"status"}, Fn: nil, Help: "Number of Heat stacks by status", Type: prometheus.GaugeValue},
 }
 
-func NewHeatExporter(config *ExporterConfig, logger *slog.Logger) (*HeatExporter, error) {
+func NewHeatExporter(ctx context.Context, config *ExporterConfig, logger *slog.Logger) (*HeatExporter, error) {
 	exporter := HeatExporter{
 		BaseOpenStackExporter{
 			Name:           "heat",
@@ -87,14 +88,14 @@ func NewHeatExporter(config *ExporterConfig, logger *slog.Logger) (*HeatExporter
 
 	for _, metric := range defaultHeatMetrics {
 		if !exporter.isSlowMetric(&metric) {
-			exporter.AddMetric(metric.Name, metric.Fn, metric.Labels, metric.DeprecatedVersion, nil)
+			exporter.addMetric(metric, nil)
 		}
 	}
 
 	return &exporter, nil
 }
 
-func ListAllStacks(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListAllStacks(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allStacks []listedStack
 	allPagesStacks, err := stacks.List(exporter.Client, stacks.ListOpts{}).AllPages()
 	if err != nil {
@@ -113,14 +114,14 @@ func ListAllStacks(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric)
 	for _, stack := range allStacks {
 		stack_status_counter[stack.Status]++
 		// Stack status metrics
-		ch <- prometheus.MustNewConstMetric(exporter.Metrics["stack_status"].Metric,
-			prometheus.GaugeValue, float64(mapHeatStatus(stack.Status)), stack.ID, stack.Name, stack.Project, stack.Status)
+		exporter.SendStatus(ch, "stack_status", stack_status, mapHeatStatus(stack.Status), stack.Status,
+			stack.ID, stack.Name, stack.Project, stack.Status)
 	}
 
 	// Stack status counter metrics
 	for status, count := range stack_status_counter {
 		ch <- prometheus.MustNewConstMetric(
-			exporter.Metrics["stack_status_counter"].Metric, prometheus.GaugeValue, float64(count), status)
+			exporter.Metrics["stack_status_counter"].Metric, exporter.Metrics["stack_status_counter"].Type, float64(count), status)
 	}
 
 	return nil
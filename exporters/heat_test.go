@@ -12,7 +12,10 @@ type HeatTestSuite struct {
 }
 
 var heatExpectedUp = `
-# HELP openstack_heat_stack_status stack_status
+# HELP openstack_collector_success Whether the last collection of the metric from OpenStack API succeeded
+# TYPE openstack_collector_success gauge
+openstack_collector_success{metric="stack_status",service="heat"} 1
+# HELP openstack_heat_stack_status Heat stack status, the index of the status in the known stack statuses
 # TYPE openstack_heat_stack_status gauge
 openstack_heat_stack_status{id="0009e826-5ad0-4310-994c-d3d2151eb6fd",name="demo-stack1",project_id="0cbd49cbf76d405d9c86562e1d579bd3",status="UPDATE_COMPLETE"} 11
 openstack_heat_stack_status{id="00cb0780-c883-4964-89c3-b79d840b3cbf",name="demo-stack2",project_id="0cbd49cbf76d405d9c86562e1d579bd3",status="CREATE_COMPLETE"} 5
@@ -20,7 +23,7 @@ openstack_heat_stack_status{id="03438d56-3109-4881-b75e-c8eb83cb9985",name="demo
 openstack_heat_stack_status{id="1128f6cf-589b-468c-8ba1-9ae7e3f24507",name="demo-stack4",project_id="0cbd49cbf76d405d9c86562e1d579bd3",status="UPDATE_FAILED"} 10
 openstack_heat_stack_status{id="23f50926-d2ab-4e13-86ee-0c768f8ce426",name="demo-stack5",project_id="0cbd49cbf76d405d9c86562e1d579bd3",status="DELETE_IN_PROGRESS"} 6
 openstack_heat_stack_status{id="24cb54d6-f060-41b6-b7ae-e4c149b35382",name="demo-stack6",project_id="0cbd49cbf76d405d9c86562e1d579bd3",status="DELETE_FAILED"} 7
-# HELP openstack_heat_stack_status_counter stack_status_counter
+# HELP openstack_heat_stack_status_counter Number of Heat stacks by status
 # TYPE openstack_heat_stack_status_counter gauge
 openstack_heat_stack_status_counter{status="ADOPT_COMPLETE"} 0
 openstack_heat_stack_status_counter{status="ADOPT_FAILED"} 0
@@ -52,7 +55,7 @@ openstack_heat_stack_status_counter{status="SUSPEND_IN_PROGRESS"} 0
 openstack_heat_stack_status_counter{status="UPDATE_COMPLETE"} 1
 openstack_heat_stack_status_counter{status="UPDATE_FAILED"} 1
 openstack_heat_stack_status_counter{status="UPDATE_IN_PROGRESS"} 0
-# HELP openstack_heat_up up
+# HELP openstack_heat_up Whether the OpenStack API of the service is up (1=up, 0=down)
 # TYPE openstack_heat_up gauge
 openstack_heat_up 1
 `
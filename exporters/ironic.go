@@ -16,11 +16,11 @@ type IronicExporter struct {
 }
 
 var defaultIronicMetrics = []Metric{
-	{Name: "node", Labels: []string{"id", "name", "provision_state", "power_state", "maintenance", "console_enabled", "resource_class", "deploy_kernel", "deploy_ramdisk", "retired", "retired_reason"}, Fn: ListNodes},
+	{Name: "node", Labels: []string{"id", "name", "provision_state", "power_state", "maintenance", "console_enabled", "resource_class", "deploy_kernel", "deploy_ramdisk", "retired", "retired_reason"}, Fn: ListNodes, Help: "Bare metal node information", Type: prometheus.GaugeValue},
 }
 
 // NewIronicExporter : returns a pointer to IronicExporter
-func NewIronicExporter(config *ExporterConfig, logger *slog.Logger) (*IronicExporter, error) {
+func NewIronicExporter(ctx context.Context, config *ExporterConfig, logger *slog.Logger) (*IronicExporter, error) {
 	exporter := IronicExporter{
 		BaseOpenStackExporter{
 			Name:           "ironic",
@@ -34,7 +34,7 @@ func NewIronicExporter(config *ExporterConfig, logger *slog.Logger) (*IronicExpo
 			continue
 		}
 		if !exporter.isSlowMetric(&metric) {
-			exporter.AddMetric(metric.Name, metric.Fn, metric.Labels, metric.DeprecatedVersion, nil)
+			exporter.addMetric(metric, nil)
 		}
 	}
 
@@ -44,7 +44,7 @@ func NewIronicExporter(config *ExporterConfig, logger *slog.Logger) (*IronicExpo
 	config.ClientV2.ResourceBase = config.ClientV2.Endpoint
 
 	// Set Microversion workaround
-	microversion, err := apiversions.Get(context.TODO(), config.ClientV2, "v1").Extract()
+	microversion, err := apiversions.Get(ctx, config.ClientV2, "v1").Extract()
 	if err == nil {
 		config.ClientV2.Microversion = microversion.Version
 		config.Client.Microversion = microversion.Version
@@ -54,8 +54,8 @@ func NewIronicExporter(config *ExporterConfig, logger *slog.Logger) (*IronicExpo
 }
 
 // ListNodes : list nodes
-func ListNodes(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
-	allPagesNodes, err := nodes.ListDetail(exporter.ClientV2, nodes.ListOpts{}).AllPages(context.TODO())
+func ListNodes(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+	allPagesNodes, err := nodes.ListDetail(exporter.ClientV2, nodes.ListOpts{}).AllPages(ctx)
 	if err != nil {
 		return err
 	}
@@ -81,7 +81,7 @@ func ListNodes(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) err
 		}
 
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["node"].Metric,
-			prometheus.GaugeValue, 1.0, node.UUID, node.Name, node.ProvisionState, node.PowerState,
+			exporter.Metrics["node"].Type, 1.0, node.UUID, node.Name, node.ProvisionState, node.PowerState,
 			strconv.FormatBool(node.Maintenance), strconv.FormatBool(node.ConsoleEnabled), node.ResourceClass,
 			deployKernel, deployRamdisk, strconv.FormatBool(node.Retired), node.RetiredReason)
 	}
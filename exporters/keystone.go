@@ -1,15 +1,16 @@
 package exporters
 
 import (
+	"context"
 	"log/slog"
 	"strconv"
 	"strings"
 
 	"github.com/gophercloud/gophercloud/openstack/identity/v3/domains"
 	"github.com/gophercloud/gophercloud/openstack/identity/v3/groups"
-	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
 	"github.com/gophercloud/gophercloud/openstack/identity/v3/regions"
 	"github.com/gophercloud/gophercloud/openstack/identity/v3/users"
+	"github.com/openstack-exporter/openstack-exporter/utils"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -18,16 +19,16 @@ type KeystoneExporter struct {
 }
 
 var defaultKeystoneMetrics = []Metric{
-	{Name: "domains", Fn: ListDomains},
-	{Name: "domain_info", Labels: []string{"description", "enabled", "id", "name"}},
-	{Name: "users", Fn: ListUsers},
-	{Name: "groups", Fn: ListGroups},
-	{Name: "projects", Fn: ListProjects},
-	{Name: "project_info", Labels: []string{"is_domain", "description", "domain_id", "enabled", "id", "name", "parent_id", "tags"}},
-	{Name: "regions", Fn: ListRegions},
+	{Name: "domains", Fn: ListDomains, Help: "Total number of domains", Type: prometheus.GaugeValue},
+	{Name: "domain_info", Labels: []string{"description", "enabled", "id", "name"}, Help: "Domain information", Type: prometheus.GaugeValue},
+	{Name: "users", Fn: ListUsers, Help: "Total number of users", Type: prometheus.GaugeValue},
+	{Name: "groups", Fn: ListGroups, Help: "Total number of groups", Type: prometheus.GaugeValue},
+	{Name: "projects", Fn: ListProjects, Help: "Total number of projects", Type: prometheus.GaugeValue},
+	{Name: "project_info", Labels: []string{"is_domain", "description", "domain_id", "enabled", "id", "name", "parent_id", "tags"}, Help: "Project information", Type: prometheus.GaugeValue},
+	{Name: "regions", Fn: ListRegions, Help: "Total number of regions", Type: prometheus.GaugeValue},
 }
 
-func NewKeystoneExporter(config *ExporterConfig, logger *slog.Logger) (*KeystoneExporter, error) {
+func NewKeystoneExporter(ctx context.Context, config *ExporterConfig, logger *slog.Logger) (*KeystoneExporter, error) {
 	exporter := KeystoneExporter{
 		BaseOpenStackExporter{
 			Name:           "identity",
@@ -37,18 +38,25 @@ func NewKeystoneExporter(config *ExporterConfig, logger *slog.Logger) (*Keystone
 	}
 
 	for _, metric := range defaultKeystoneMetrics {
+		if metric.Name == "project_info" {
+			labels, err := exporter.mappedLabels("keystone.project", metric.Labels)
+			if err != nil {
+				return nil, err
+			}
+			metric.Labels = labels
+		}
 		if exporter.isDeprecatedMetric(&metric) {
 			continue
 		}
 		if !exporter.isSlowMetric(&metric) {
-			exporter.AddMetric(metric.Name, metric.Fn, metric.Labels, metric.DeprecatedVersion, nil)
+			exporter.addMetric(metric, nil)
 		}
 	}
 
 	return &exporter, nil
 }
 
-func ListDomains(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListDomains(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allDomains []domains.Domain
 
 	allPagesDomain, err := domains.List(exporter.Client, domains.ListOpts{}).AllPages()
@@ -61,44 +69,38 @@ func ListDomains(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) e
 		return err
 	}
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["domains"].Metric,
-		prometheus.GaugeValue, float64(len(allDomains)))
+		exporter.Metrics["domains"].Type, float64(len(allDomains)))
 	if !exporter.MetricIsDisabled("domain_info") {
 		for _, d := range allDomains {
 			ch <- prometheus.MustNewConstMetric(exporter.Metrics["domain_info"].Metric,
-				prometheus.GaugeValue, 1.0,
+				exporter.Metrics["domain_info"].Type, 1.0,
 				d.Description, strconv.FormatBool(d.Enabled), d.ID, d.Name)
 		}
 	}
 	return nil
 }
 
-func ListProjects(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
-	var allProjects []projects.Project
-
-	allPagesProject, err := projects.List(exporter.Client, projects.ListOpts{DomainID: exporter.DomainID}).AllPages()
-	if err != nil {
-		return err
-	}
-
-	allProjects, err = projects.ExtractProjects(allPagesProject)
+func ListProjects(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+	allProjects, err := listProjects(ctx, exporter.Client, exporter.DomainID)
 	if err != nil {
 		return err
 	}
 
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["projects"].Metric,
-		prometheus.GaugeValue, float64(len(allProjects)))
+		exporter.Metrics["projects"].Type, float64(len(allProjects)))
 	if !exporter.MetricIsDisabled("project_info") {
 		for _, p := range allProjects {
 			ch <- prometheus.MustNewConstMetric(exporter.Metrics["project_info"].Metric,
-				prometheus.GaugeValue, 1.0, strconv.FormatBool(p.IsDomain),
-				p.Description, p.DomainID, strconv.FormatBool(p.Enabled), p.ID, p.Name,
-				p.ParentID, strings.Join(p.Tags, ","))
+				exporter.Metrics["project_info"].Type, 1.0, append([]string{strconv.FormatBool(p.IsDomain),
+					p.Description, p.DomainID, strconv.FormatBool(p.Enabled), p.ID, p.Name,
+					p.ParentID, strings.Join(p.Tags, ",")},
+					exporter.LabelMappings.Get("keystone.project").Extract(utils.TagsMetadata(p.Tags))...)...)
 		}
 	}
 	return nil
 }
 
-func ListRegions(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListRegions(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allRegions []regions.Region
 
 	allPagesRegion, err := regions.List(exporter.Client, regions.ListOpts{}).AllPages()
@@ -111,12 +113,12 @@ func ListRegions(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) e
 		return err
 	}
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["regions"].Metric,
-		prometheus.GaugeValue, float64(len(allRegions)))
+		exporter.Metrics["regions"].Type, float64(len(allRegions)))
 
 	return nil
 }
 
-func ListUsers(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListUsers(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allUsers []users.User
 
 	allPagesUser, err := users.List(exporter.Client, users.ListOpts{DomainID: exporter.DomainID}).AllPages()
@@ -129,12 +131,12 @@ func ListUsers(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) err
 		return err
 	}
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["users"].Metric,
-		prometheus.GaugeValue, float64(len(allUsers)))
+		exporter.Metrics["users"].Type, float64(len(allUsers)))
 
 	return nil
 }
 
-func ListGroups(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListGroups(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allGroups []groups.Group
 
 	allPagesGroup, err := groups.List(exporter.Client, groups.ListOpts{DomainID: exporter.DomainID}).AllPages()
@@ -148,7 +150,7 @@ func ListGroups(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) er
 	}
 
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["groups"].Metric,
-		prometheus.GaugeValue, float64(len(allGroups)))
+		exporter.Metrics["groups"].Type, float64(len(allGroups)))
 
 	return nil
 }
@@ -12,16 +12,23 @@ type KeystoneTestSuite struct {
 }
 
 var keystoneExpectedUp = `                       
-# HELP openstack_identity_domains domains
+# HELP openstack_collector_success Whether the last collection of the metric from OpenStack API succeeded
+# TYPE openstack_collector_success gauge
+openstack_collector_success{metric="domains",service="identity"} 1
+openstack_collector_success{metric="groups",service="identity"} 1
+openstack_collector_success{metric="projects",service="identity"} 1
+openstack_collector_success{metric="regions",service="identity"} 1
+openstack_collector_success{metric="users",service="identity"} 1
+# HELP openstack_identity_domains Total number of domains
 # TYPE openstack_identity_domains gauge
 openstack_identity_domains 1
-# HELP openstack_identity_domain_info domain_info
+# HELP openstack_identity_domain_info Domain information
 # TYPE openstack_identity_domain_info gauge
 openstack_identity_domain_info{description="Owns users and tenants (i.e. projects) available on Identity API v2.",enabled="true",id="default",name="Default"} 1
-# HELP openstack_identity_groups groups
+# HELP openstack_identity_groups Total number of groups
 # TYPE openstack_identity_groups gauge
 openstack_identity_groups 2
-# HELP openstack_identity_project_info project_info
+# HELP openstack_identity_project_info Project information
 # TYPE openstack_identity_project_info gauge
 openstack_identity_project_info{description="",domain_id="1bc2169ca88e4cdaaba46d4c15390b65",enabled="true",id="4b1eb781a47440acb8af9850103e537f",is_domain="false",name="swifttenanttest4",parent_id="",tags=""} 1
 openstack_identity_project_info{description="",domain_id="default",enabled="true",id="0c4e939acacf4376bdcd1129f1a054ad",is_domain="false",name="admin",parent_id="",tags=""} 1
@@ -31,16 +38,16 @@ openstack_identity_project_info{description="",domain_id="default",enabled="true
 openstack_identity_project_info{description="",domain_id="default",enabled="true",id="5961c443439d4fcebe42643723755e9d",is_domain="false",name="invisible_to_admin",parent_id="",tags=""} 1
 openstack_identity_project_info{description="",domain_id="default",enabled="true",id="fdb8424c4e4f4c0ba32c52e2de3bd80e",is_domain="false",name="alt_demo",parent_id="",tags=""} 1
 openstack_identity_project_info{description="This is a demo project.",domain_id="default",enabled="true",id="0cbd49cbf76d405d9c86562e1d579bd3",is_domain="false",name="demo",parent_id="",tags=""} 1
-# HELP openstack_identity_projects projects
+# HELP openstack_identity_projects Total number of projects
 # TYPE openstack_identity_projects gauge
 openstack_identity_projects 8
-# HELP openstack_identity_regions regions
+# HELP openstack_identity_regions Total number of regions
 # TYPE openstack_identity_regions gauge
 openstack_identity_regions 1
-# HELP openstack_identity_up up
+# HELP openstack_identity_up Whether the OpenStack API of the service is up (1=up, 0=down)
 # TYPE openstack_identity_up gauge
 openstack_identity_up 1
-# HELP openstack_identity_users users
+# HELP openstack_identity_users Total number of users
 # TYPE openstack_identity_users gauge
 openstack_identity_users 2
 `
@@ -1,12 +1,14 @@
 package exporters
 
 import (
+	"context"
 	"log/slog"
 	"time"
 
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/amphorae"
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/pools"
+	"github.com/openstack-exporter/openstack-exporter/utils"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -76,20 +78,24 @@ type LoadbalancerExporter struct {
 }
 
 var defaultLoadbalancerMetrics = []Metric{
-	{Name: "total_loadbalancers", Fn: ListAllLoadbalancers},
-	{Name: "loadbalancer_status", Labels: []string{"id", "name", "project_id", "operating_status", "provisioning_status", "provider", "vip_address"}},
-	{Name: "stats_bytes_in", Labels: []string{"id", "name", "project_id", "operating_status", "provisioning_status", "provider", "vip_address"}},
-	{Name: "stats_bytes_out", Labels: []string{"id", "name", "project_id", "operating_status", "provisioning_status", "provider", "vip_address"}},
-	{Name: "stats_active_connections", Labels: []string{"id", "name", "project_id", "operating_status", "provisioning_status", "provider", "vip_address"}},
-	{Name: "stats_total_connections", Labels: []string{"id", "name", "project_id", "operating_status", "provisioning_status", "provider", "vip_address"}},
-	{Name: "stats_request_errors", Labels: []string{"id", "name", "project_id", "operating_status", "provisioning_status", "provider", "vip_address"}},
-	{Name: "total_amphorae", Fn: ListAllAmphorae},
-	{Name: "amphora_status", Labels: []string{"id", "loadbalancer_id", "compute_id", "status", "role", "lb_network_ip", "ha_ip", "cert_expiration"}},
-	{Name: "total_pools", Fn: ListAllPools},
-	{Name: "pool_status", Labels: []string{"id", "provisioning_status", "name", "loadbalancers", "protocol", "lb_algorithm", "operating_status", "project_id"}},
+	{Name: "total_loadbalancers", Fn: ListAllLoadbalancers, Help: "Total number of load balancers", Type: prometheus.GaugeValue},
+	{Name: "loadbalancer_status", Labels: []string{"id", "name", "project_id", "operating_status", "provisioning_status", "provider", "vip_address"}, States: loadbalancer_status, Help: "Load balancer status, the index of the operating status in the known statuses", Type: prometheus.GaugeValue},
+	{Name: "stats_bytes_in_total", Labels: []string{"id", "name", "project_id", "operating_status", "provisioning_status", "provider", "vip_address"}, Help: "Total number of bytes received by the load balancer", Type: prometheus.CounterValue, Unit: "bytes"},
+	{Name: "stats_bytes_out_total", Labels: []string{"id", "name", "project_id", "operating_status", "provisioning_status", "provider", "vip_address"}, Help: "Total number of bytes sent by the load balancer", Type: prometheus.CounterValue, Unit: "bytes"},
+	{Name: "stats_active_connections", Labels: []string{"id", "name", "project_id", "operating_status", "provisioning_status", "provider", "vip_address"}, Help: "Number of active connections of the load balancer", Type: prometheus.GaugeValue},
+	{Name: "stats_connections_total", Labels: []string{"id", "name", "project_id", "operating_status", "provisioning_status", "provider", "vip_address"}, Help: "Total number of connections handled by the load balancer", Type: prometheus.CounterValue},
+	{Name: "stats_request_errors_total", Labels: []string{"id", "name", "project_id", "operating_status", "provisioning_status", "provider", "vip_address"}, Help: "Total number of request errors of the load balancer", Type: prometheus.CounterValue},
+	{Name: "stats_bytes_in", Labels: []string{"id", "name", "project_id", "operating_status", "provisioning_status", "provider", "vip_address"}, DeprecatedVersion: "1.7", Help: "Total number of bytes received by the load balancer, deprecated in favor of stats_bytes_in_total", Type: prometheus.GaugeValue, Unit: "bytes"},
+	{Name: "stats_bytes_out", Labels: []string{"id", "name", "project_id", "operating_status", "provisioning_status", "provider", "vip_address"}, DeprecatedVersion: "1.7", Help: "Total number of bytes sent by the load balancer, deprecated in favor of stats_bytes_out_total", Type: prometheus.GaugeValue, Unit: "bytes"},
+	{Name: "stats_total_connections", Labels: []string{"id", "name", "project_id", "operating_status", "provisioning_status", "provider", "vip_address"}, DeprecatedVersion: "1.7", Help: "Total number of connections handled by the load balancer, deprecated in favor of stats_connections_total", Type: prometheus.GaugeValue},
+	{Name: "stats_request_errors", Labels: []string{"id", "name", "project_id", "operating_status", "provisioning_status", "provider", "vip_address"}, DeprecatedVersion: "1.7", Help: "Total number of request errors of the load balancer, deprecated in favor of stats_request_errors_total", Type: prometheus.GaugeValue},
+	{Name: "total_amphorae", Fn: ListAllAmphorae, Help: "Total number of amphorae", Type: prometheus.GaugeValue},
+	{Name: "amphora_status", Labels: []string{"id", "loadbalancer_id", "compute_id", "status", "role", "lb_network_ip", "ha_ip", "cert_expiration"}, States: amphora_status, Help: "Amphora status, the index of the status in the known amphora statuses", Type: prometheus.GaugeValue},
+	{Name: "total_pools", Fn: ListAllPools, Help: "Total number of pools", Type: prometheus.GaugeValue},
+	{Name: "pool_status", Labels: []string{"id", "provisioning_status", "name", "loadbalancers", "protocol", "lb_algorithm", "operating_status", "project_id"}, States: pool_status, Help: "Pool status, the index of the status in the known pool statuses", Type: prometheus.GaugeValue},
 }
 
-func NewLoadbalancerExporter(config *ExporterConfig, logger *slog.Logger) (*LoadbalancerExporter, error) {
+func NewLoadbalancerExporter(ctx context.Context, config *ExporterConfig, logger *slog.Logger) (*LoadbalancerExporter, error) {
 	exporter := LoadbalancerExporter{
 		BaseOpenStackExporter{
 			Name:           "loadbalancer",
@@ -98,12 +104,22 @@ func NewLoadbalancerExporter(config *ExporterConfig, logger *slog.Logger) (*Load
 		},
 	}
 	for _, metric := range defaultLoadbalancerMetrics {
-		exporter.AddMetric(metric.Name, metric.Fn, metric.Labels, metric.DeprecatedVersion, nil)
+		if metric.Name == "loadbalancer_status" {
+			labels, err := exporter.mappedLabels("octavia.loadbalancer", metric.Labels)
+			if err != nil {
+				return nil, err
+			}
+			metric.Labels = labels
+		}
+		if exporter.isDeprecatedMetric(&metric) {
+			continue
+		}
+		exporter.addMetric(metric, nil)
 	}
 	return &exporter, nil
 }
 
-func ListAllLoadbalancers(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListAllLoadbalancers(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allLoadbalancers []loadbalancers.LoadBalancer
 	allPagesLoadbalancers, err := loadbalancers.List(exporter.Client, loadbalancers.ListOpts{}).AllPages()
 	if err != nil {
@@ -115,12 +131,13 @@ func ListAllLoadbalancers(exporter *BaseOpenStackExporter, ch chan<- prometheus.
 	}
 
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["total_loadbalancers"].Metric,
-		prometheus.GaugeValue, float64(len(allLoadbalancers)))
+		exporter.Metrics["total_loadbalancers"].Type, float64(len(allLoadbalancers)))
 	// Loadbalancer status metrics
 	for _, loadbalancer := range allLoadbalancers {
-		ch <- prometheus.MustNewConstMetric(exporter.Metrics["loadbalancer_status"].Metric,
-			prometheus.GaugeValue, float64(mapLoadbalancerStatus(loadbalancer.OperatingStatus)), loadbalancer.ID, loadbalancer.Name, loadbalancer.ProjectID,
-			loadbalancer.OperatingStatus, loadbalancer.ProvisioningStatus, loadbalancer.Provider, loadbalancer.VipAddress)
+		exporter.SendStatus(ch, "loadbalancer_status", loadbalancer_status, mapLoadbalancerStatus(loadbalancer.OperatingStatus), loadbalancer.OperatingStatus,
+			append([]string{loadbalancer.ID, loadbalancer.Name, loadbalancer.ProjectID,
+				loadbalancer.OperatingStatus, loadbalancer.ProvisioningStatus, loadbalancer.Provider, loadbalancer.VipAddress},
+				exporter.LabelMappings.Get("octavia.loadbalancer").Extract(utils.TagsMetadata(loadbalancer.Tags))...)...)
 
 		// Loadbalancer stats metrics
 		stats, err := loadbalancers.GetStats(exporter.Client, loadbalancer.ID).Extract()
@@ -132,21 +149,22 @@ func ListAllLoadbalancers(exporter *BaseOpenStackExporter, ch chan<- prometheus.
 		labelValues := []string{loadbalancer.ID, loadbalancer.Name, loadbalancer.ProjectID,
 			loadbalancer.OperatingStatus, loadbalancer.ProvisioningStatus, loadbalancer.Provider, loadbalancer.VipAddress}
 
-		ch <- prometheus.MustNewConstMetric(exporter.Metrics["stats_bytes_in"].Metric,
-			prometheus.GaugeValue, float64(stats.BytesIn), labelValues...)
-		ch <- prometheus.MustNewConstMetric(exporter.Metrics["stats_bytes_out"].Metric,
-			prometheus.GaugeValue, float64(stats.BytesOut), labelValues...)
-		ch <- prometheus.MustNewConstMetric(exporter.Metrics["stats_active_connections"].Metric,
-			prometheus.GaugeValue, float64(stats.ActiveConnections), labelValues...)
-		ch <- prometheus.MustNewConstMetric(exporter.Metrics["stats_total_connections"].Metric,
-			prometheus.GaugeValue, float64(stats.TotalConnections), labelValues...)
-		ch <- prometheus.MustNewConstMetric(exporter.Metrics["stats_request_errors"].Metric,
-			prometheus.GaugeValue, float64(stats.RequestErrors), labelValues...)
+		exporter.sendIfEnabled(ch, "stats_bytes_in_total", float64(stats.BytesIn), labelValues...)
+		exporter.sendIfEnabled(ch, "stats_bytes_out_total", float64(stats.BytesOut), labelValues...)
+		exporter.sendIfEnabled(ch, "stats_active_connections", float64(stats.ActiveConnections), labelValues...)
+		exporter.sendIfEnabled(ch, "stats_connections_total", float64(stats.TotalConnections), labelValues...)
+		exporter.sendIfEnabled(ch, "stats_request_errors_total", float64(stats.RequestErrors), labelValues...)
+
+		// The gauges the counters above replace, until their removal. They are not added with --disable-deprecated-metrics.
+		exporter.sendIfEnabled(ch, "stats_bytes_in", float64(stats.BytesIn), labelValues...)
+		exporter.sendIfEnabled(ch, "stats_bytes_out", float64(stats.BytesOut), labelValues...)
+		exporter.sendIfEnabled(ch, "stats_total_connections", float64(stats.TotalConnections), labelValues...)
+		exporter.sendIfEnabled(ch, "stats_request_errors", float64(stats.RequestErrors), labelValues...)
 	}
 	return nil
 }
 
-func ListAllAmphorae(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListAllAmphorae(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allAmphorae []amphorae.Amphora
 	allPagesAmphorae, err := amphorae.List(exporter.Client, amphorae.ListOpts{}).AllPages()
 	if err != nil {
@@ -158,17 +176,17 @@ func ListAllAmphorae(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metri
 	}
 
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["total_amphorae"].Metric,
-		prometheus.GaugeValue, float64(len(allAmphorae)))
+		exporter.Metrics["total_amphorae"].Type, float64(len(allAmphorae)))
 	// Loadbalancer status metrics
 	for _, amphora := range allAmphorae {
-		ch <- prometheus.MustNewConstMetric(exporter.Metrics["amphora_status"].Metric,
-			prometheus.GaugeValue, float64(mapAmphoraStatus(amphora.Status)), amphora.ID, amphora.LoadbalancerID, amphora.ComputeID, amphora.Status,
+		exporter.SendStatus(ch, "amphora_status", amphora_status, mapAmphoraStatus(amphora.Status), amphora.Status,
+			amphora.ID, amphora.LoadbalancerID, amphora.ComputeID, amphora.Status,
 			amphora.Role, amphora.LBNetworkIP, amphora.HAIP, amphora.CertExpiration.Format(time.RFC3339))
 	}
 	return nil
 }
 
-func ListAllPools(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListAllPools(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allPools []pools.Pool
 	allPagesPools, err := pools.List(exporter.Client, pools.ListOpts{}).AllPages()
 	if err != nil {
@@ -179,10 +197,10 @@ func ListAllPools(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric)
 		return err
 	}
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["total_pools"].Metric,
-		prometheus.GaugeValue, float64(len(allPools)))
+		exporter.Metrics["total_pools"].Type, float64(len(allPools)))
 	for _, pool := range allPools {
-		ch <- prometheus.MustNewConstMetric(exporter.Metrics["pool_status"].Metric,
-			prometheus.GaugeValue, float64(mapPoolStatus(pool.ProvisioningStatus)), pool.ID, pool.ProvisioningStatus, pool.Name,
+		exporter.SendStatus(ch, "pool_status", pool_status, mapPoolStatus(pool.ProvisioningStatus), pool.ProvisioningStatus,
+			pool.ID, pool.ProvisioningStatus, pool.Name,
 			lbsLabels(pool.Loadbalancers), pool.Protocol, pool.LBMethod, pool.OperatingStatus, pool.ProjectID)
 	}
 	return nil
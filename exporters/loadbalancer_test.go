@@ -1,10 +1,14 @@
 package exporters
 
 import (
+	"context"
+	"io"
+	"log/slog"
 	"strings"
 
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type LoadbalancerTestSuite struct {
@@ -12,41 +16,58 @@ type LoadbalancerTestSuite struct {
 }
 
 var loadbalancerExpectedUp = `
-# HELP openstack_loadbalancer_pool_status pool_status
+# HELP openstack_collector_success Whether the last collection of the metric from OpenStack API succeeded
+# TYPE openstack_collector_success gauge
+openstack_collector_success{metric="total_amphorae",service="loadbalancer"} 1
+openstack_collector_success{metric="total_loadbalancers",service="loadbalancer"} 1
+openstack_collector_success{metric="total_pools",service="loadbalancer"} 1
+# HELP openstack_loadbalancer_pool_status Pool status, the index of the status in the known pool statuses
 # TYPE openstack_loadbalancer_pool_status gauge
 openstack_loadbalancer_pool_status{id="ca00ed86-94e3-440e-95c6-ffa35531081e",lb_algorithm="ROUND_ROBIN",loadbalancers="e7284bb2-f46a-42ca-8c9b-e08671255125",name="my_test_pool",operating_status="ERROR",project_id="8b1632d90bfe407787d9996b7f662fd7",protocol="TCP",provisioning_status="ACTIVE"} 0
-# HELP openstack_loadbalancer_amphora_status amphora_status
+# HELP openstack_loadbalancer_amphora_status Amphora status, the index of the status in the known amphora statuses
 # TYPE openstack_loadbalancer_amphora_status gauge
 openstack_loadbalancer_amphora_status{cert_expiration="2020-08-08T23:44:31Z",compute_id="667bb225-69aa-44b1-8908-694dc624c267",ha_ip="10.0.0.6",id="45f40289-0551-483a-b089-47214bc2a8a4",lb_network_ip="192.168.0.6",loadbalancer_id="882f2a9d-9d53-4bd0-b0e9-08e9d0de11f9",role="MASTER",status="READY"} 2
 openstack_loadbalancer_amphora_status{cert_expiration="2020-08-08T23:44:30Z",compute_id="9cd0f9a2-fe12-42fc-a7e3-5b6fbbe20395",ha_ip="10.0.0.6",id="7f890893-ced0-46ed-8697-33415d070e5a",lb_network_ip="192.168.0.17",loadbalancer_id="882f2a9d-9d53-4bd0-b0e9-08e9d0de11f9",role="BACKUP",status="READY"} 2
-# HELP openstack_loadbalancer_loadbalancer_status loadbalancer_status
+# HELP openstack_loadbalancer_loadbalancer_status Load balancer status, the index of the operating status in the known statuses
 # TYPE openstack_loadbalancer_loadbalancer_status gauge
 openstack_loadbalancer_loadbalancer_status{id="607226db-27ef-4d41-ae89-f2a800e9c2db",name="best_load_balancer",operating_status="ONLINE",project_id="e3cd678b11784734bc366148aa37580e",provider="octavia",provisioning_status="ACTIVE",vip_address="203.0.113.50"} 0
-# HELP openstack_loadbalancer_stats_active_connections stats_active_connections
+# HELP openstack_loadbalancer_stats_active_connections Number of active connections of the load balancer
 # TYPE openstack_loadbalancer_stats_active_connections gauge
 openstack_loadbalancer_stats_active_connections{id="607226db-27ef-4d41-ae89-f2a800e9c2db",name="best_load_balancer",operating_status="ONLINE",project_id="e3cd678b11784734bc366148aa37580e",provider="octavia",provisioning_status="ACTIVE",vip_address="203.0.113.50"} 8
-# HELP openstack_loadbalancer_stats_bytes_in stats_bytes_in
+# HELP openstack_loadbalancer_stats_bytes_in Total number of bytes received by the load balancer, deprecated in favor of stats_bytes_in_total
 # TYPE openstack_loadbalancer_stats_bytes_in gauge
 openstack_loadbalancer_stats_bytes_in{id="607226db-27ef-4d41-ae89-f2a800e9c2db",name="best_load_balancer",operating_status="ONLINE",project_id="e3cd678b11784734bc366148aa37580e",provider="octavia",provisioning_status="ACTIVE",vip_address="203.0.113.50"} 2.233408e+06
-# HELP openstack_loadbalancer_stats_bytes_out stats_bytes_out
+# HELP openstack_loadbalancer_stats_bytes_in_total Total number of bytes received by the load balancer
+# TYPE openstack_loadbalancer_stats_bytes_in_total counter
+openstack_loadbalancer_stats_bytes_in_total{id="607226db-27ef-4d41-ae89-f2a800e9c2db",name="best_load_balancer",operating_status="ONLINE",project_id="e3cd678b11784734bc366148aa37580e",provider="octavia",provisioning_status="ACTIVE",vip_address="203.0.113.50"} 2.233408e+06
+# HELP openstack_loadbalancer_stats_bytes_out Total number of bytes sent by the load balancer, deprecated in favor of stats_bytes_out_total
 # TYPE openstack_loadbalancer_stats_bytes_out gauge
 openstack_loadbalancer_stats_bytes_out{id="607226db-27ef-4d41-ae89-f2a800e9c2db",name="best_load_balancer",operating_status="ONLINE",project_id="e3cd678b11784734bc366148aa37580e",provider="octavia",provisioning_status="ACTIVE",vip_address="203.0.113.50"} 1.357932e+06
-# HELP openstack_loadbalancer_stats_request_errors stats_request_errors
+# HELP openstack_loadbalancer_stats_bytes_out_total Total number of bytes sent by the load balancer
+# TYPE openstack_loadbalancer_stats_bytes_out_total counter
+openstack_loadbalancer_stats_bytes_out_total{id="607226db-27ef-4d41-ae89-f2a800e9c2db",name="best_load_balancer",operating_status="ONLINE",project_id="e3cd678b11784734bc366148aa37580e",provider="octavia",provisioning_status="ACTIVE",vip_address="203.0.113.50"} 1.357932e+06
+# HELP openstack_loadbalancer_stats_connections_total Total number of connections handled by the load balancer
+# TYPE openstack_loadbalancer_stats_connections_total counter
+openstack_loadbalancer_stats_connections_total{id="607226db-27ef-4d41-ae89-f2a800e9c2db",name="best_load_balancer",operating_status="ONLINE",project_id="e3cd678b11784734bc366148aa37580e",provider="octavia",provisioning_status="ACTIVE",vip_address="203.0.113.50"} 524
+# HELP openstack_loadbalancer_stats_request_errors Total number of request errors of the load balancer, deprecated in favor of stats_request_errors_total
 # TYPE openstack_loadbalancer_stats_request_errors gauge
 openstack_loadbalancer_stats_request_errors{id="607226db-27ef-4d41-ae89-f2a800e9c2db",name="best_load_balancer",operating_status="ONLINE",project_id="e3cd678b11784734bc366148aa37580e",provider="octavia",provisioning_status="ACTIVE",vip_address="203.0.113.50"} 5
-# HELP openstack_loadbalancer_stats_total_connections stats_total_connections
+# HELP openstack_loadbalancer_stats_request_errors_total Total number of request errors of the load balancer
+# TYPE openstack_loadbalancer_stats_request_errors_total counter
+openstack_loadbalancer_stats_request_errors_total{id="607226db-27ef-4d41-ae89-f2a800e9c2db",name="best_load_balancer",operating_status="ONLINE",project_id="e3cd678b11784734bc366148aa37580e",provider="octavia",provisioning_status="ACTIVE",vip_address="203.0.113.50"} 5
+# HELP openstack_loadbalancer_stats_total_connections Total number of connections handled by the load balancer, deprecated in favor of stats_connections_total
 # TYPE openstack_loadbalancer_stats_total_connections gauge
 openstack_loadbalancer_stats_total_connections{id="607226db-27ef-4d41-ae89-f2a800e9c2db",name="best_load_balancer",operating_status="ONLINE",project_id="e3cd678b11784734bc366148aa37580e",provider="octavia",provisioning_status="ACTIVE",vip_address="203.0.113.50"} 524
-# HELP openstack_loadbalancer_total_amphorae total_amphorae
+# HELP openstack_loadbalancer_total_amphorae Total number of amphorae
 # TYPE openstack_loadbalancer_total_amphorae gauge
 openstack_loadbalancer_total_amphorae 2
-# HELP openstack_loadbalancer_total_loadbalancers total_loadbalancers
+# HELP openstack_loadbalancer_total_loadbalancers Total number of load balancers
 # TYPE openstack_loadbalancer_total_loadbalancers gauge
 openstack_loadbalancer_total_loadbalancers 1
-# HELP openstack_loadbalancer_total_pools total_pools
+# HELP openstack_loadbalancer_total_pools Total number of pools
 # TYPE openstack_loadbalancer_total_pools gauge
 openstack_loadbalancer_total_pools 1
-# HELP openstack_loadbalancer_up up
+# HELP openstack_loadbalancer_up Whether the OpenStack API of the service is up (1=up, 0=down)
 # TYPE openstack_loadbalancer_up gauge
 openstack_loadbalancer_up 1
 `
@@ -55,3 +76,23 @@ func (suite *LoadbalancerTestSuite) TestLoadbalancerExporter() {
 	err := testutil.CollectAndCompare(*suite.Exporter, strings.NewReader(loadbalancerExpectedUp))
 	assert.NoError(suite.T(), err)
 }
+
+func (suite *LoadbalancerTestSuite) TestLoadbalancerExporterDisabledMetric() {
+	config := (*suite.Exporter).(*LoadbalancerExporter).ExporterConfig
+	config.DisabledMetrics = []string{"loadbalancer-stats_bytes_in"}
+	exporter, err := NewLoadbalancerExporter(context.Background(), &config, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(suite.T(), err)
+
+	// The mocked responses are only served twice, the exporter is collected once.
+	expected := `
+# HELP openstack_loadbalancer_stats_bytes_in_total Total number of bytes received by the load balancer
+# TYPE openstack_loadbalancer_stats_bytes_in_total counter
+openstack_loadbalancer_stats_bytes_in_total{id="607226db-27ef-4d41-ae89-f2a800e9c2db",name="best_load_balancer",operating_status="ONLINE",project_id="e3cd678b11784734bc366148aa37580e",provider="octavia",provisioning_status="ACTIVE",vip_address="203.0.113.50"} 2.233408e+06
+# HELP openstack_loadbalancer_stats_bytes_out Total number of bytes sent by the load balancer, deprecated in favor of stats_bytes_out_total
+# TYPE openstack_loadbalancer_stats_bytes_out gauge
+openstack_loadbalancer_stats_bytes_out{id="607226db-27ef-4d41-ae89-f2a800e9c2db",name="best_load_balancer",operating_status="ONLINE",project_id="e3cd678b11784734bc366148aa37580e",provider="octavia",provisioning_status="ACTIVE",vip_address="203.0.113.50"} 1.357932e+06
+`
+	err = testutil.CollectAndCompare(exporter, strings.NewReader(expected),
+		"openstack_loadbalancer_stats_bytes_in", "openstack_loadbalancer_stats_bytes_in_total", "openstack_loadbalancer_stats_bytes_out")
+	assert.NoError(suite.T(), err, "the disabled deprecated gauge should not be collected")
+}
@@ -1,11 +1,11 @@
 package exporters
 
 import (
+	"context"
 	"strconv"
 
 	"log/slog"
 
-	"github.com/gophercloud/gophercloud/openstack/sharedfilesystems/v2/shares"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -14,13 +14,13 @@ type ManilaExporter struct {
 }
 
 var defaultManilaMetrics = []Metric{
-	{Name: "shares_counter", Fn: CountShares},
-	{Name: "share_gb", Labels: []string{"id", "name", "status", "availability_zone", "share_type", "share_proto", "share_type_name", "project_id"}, Fn: nil},
-	{Name: "share_status", Labels: []string{"id", "name", "status", "size", "share_type", "share_proto", "share_type_name", "project_id"}, Fn: ListShareStatus},
-	{Name: "share_status_counter", Labels: []string{"status"}, Fn: nil},
+	{Name: "shares_counter", Fn: CountShares, Help: "Total number of shares", Type: prometheus.GaugeValue},
+	{Name: "share_gb", Labels: []string{"id", "name", "status", "availability_zone", "share_type", "share_proto", "share_type_name", "project_id"}, Fn: nil, Help: "Share size in GB", Type: prometheus.GaugeValue, Unit: "gigabytes"},
+	{Name: "share_status_counter", Labels: []string{"status"}, Fn: nil, Help: "Number of shares by status", Type: prometheus.GaugeValue},
+	{Name: "share_status", Labels: []string{"id", "name", "status", "size", "share_type", "share_proto", "share_type_name", "project_id"}, Fn: ListShareStatus, States: volume_status, Help: "Share status, the index of the status in the known share statuses", Type: prometheus.GaugeValue},
 }
 
-func NewManilaExporter(config *ExporterConfig, logger *slog.Logger) (*ManilaExporter, error) {
+func NewManilaExporter(ctx context.Context, config *ExporterConfig, logger *slog.Logger) (*ManilaExporter, error) {
 	exporter := ManilaExporter{
 		BaseOpenStackExporter{
 
@@ -31,39 +31,40 @@ func NewManilaExporter(config *ExporterConfig, logger *slog.Logger) (*ManilaExpo
 	}
 
 	for _, metric := range defaultManilaMetrics {
+		if metric.Name == "share_gb" || metric.Name == "share_status" {
+			labels, err := exporter.mappedLabels("manila.share", metric.Labels)
+			if err != nil {
+				return nil, err
+			}
+			metric.Labels = labels
+		}
 		if exporter.isDeprecatedMetric(&metric) {
 			continue
 		}
 		if !exporter.isSlowMetric(&metric) {
-			exporter.AddMetric(metric.Name, metric.Fn, metric.Labels, metric.DeprecatedVersion, nil)
+			exporter.addMetric(metric, nil)
 		}
 	}
 
 	return &exporter, nil
 }
 
-func CountShares(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
-
-	var allShares []shares.Share
-
-	allPagesShares, err := shares.ListDetail(exporter.Client, shares.ListOpts{AllTenants: true}).AllPages()
-	if err != nil {
-		return err
-	}
+func CountShares(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 
-	allShares, err = shares.ExtractShares(allPagesShares)
+	allShares, err := listShares(ctx, exporter.Client)
 	if err != nil {
 		return err
 	}
 
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["shares_counter"].Metric,
-		prometheus.GaugeValue, float64(len(allShares)))
+		exporter.Metrics["shares_counter"].Type, float64(len(allShares)))
 
 	// share_gb metrics
 	for _, share := range allShares {
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["share_gb"].Metric,
-			prometheus.GaugeValue, float64(share.Size), share.ID, share.Name,
-			share.Status, share.AvailabilityZone, share.ShareType, share.ShareProto, share.ShareTypeName, share.ProjectID)
+			exporter.Metrics["share_gb"].Type, float64(share.Size), append([]string{share.ID, share.Name,
+				share.Status, share.AvailabilityZone, share.ShareType, share.ShareProto, share.ShareTypeName, share.ProjectID},
+				exporter.LabelMappings.Get("manila.share").Extract(share.Metadata)...)...)
 	}
 
 	share_status_counter := map[string]int{
@@ -96,7 +97,7 @@ func CountShares(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) e
 	for status, count := range share_status_counter {
 		ch <- prometheus.MustNewConstMetric(
 			exporter.Metrics["share_status_counter"].Metric,
-			prometheus.GaugeValue,
+			exporter.Metrics["share_status_counter"].Type,
 			float64(count),
 			status)
 	}
@@ -104,25 +105,19 @@ func CountShares(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) e
 	return nil
 }
 
-func ListShareStatus(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
-
-	var allShares []shares.Share
-
-	allPagesShares, err := shares.ListDetail(exporter.Client, shares.ListOpts{AllTenants: true}).AllPages()
-	if err != nil {
-		return err
-	}
+func ListShareStatus(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 
-	allShares, err = shares.ExtractShares(allPagesShares)
+	allShares, err := listShares(ctx, exporter.Client)
 	if err != nil {
 		return err
 	}
 
 	// Share status metrics
 	for _, share := range allShares {
-		ch <- prometheus.MustNewConstMetric(exporter.Metrics["share_status"].Metric,
-			prometheus.GaugeValue, float64(mapVolumeStatus(share.Status)), share.ID, share.Name,
-			share.Status, strconv.Itoa(share.Size), share.ShareType, share.ShareProto, share.ShareTypeName, share.ProjectID)
+		exporter.SendStatus(ch, "share_status", volume_status, mapVolumeStatus(share.Status), share.Status,
+			append([]string{share.ID, share.Name,
+				share.Status, strconv.Itoa(share.Size), share.ShareType, share.ShareProto, share.ShareTypeName, share.ProjectID},
+				exporter.LabelMappings.Get("manila.share").Extract(share.Metadata)...)...)
 	}
 	return nil
 }
@@ -12,13 +12,17 @@ type ManilaTestSuite struct {
 }
 
 var manilaExpectedUp = `
-# HELP openstack_sharev2_share_gb share_gb
+# HELP openstack_collector_success Whether the last collection of the metric from OpenStack API succeeded
+# TYPE openstack_collector_success gauge
+openstack_collector_success{metric="share_status",service="sharev2"} 1
+openstack_collector_success{metric="shares_counter",service="sharev2"} 1
+# HELP openstack_sharev2_share_gb Share size in GB
 # TYPE openstack_sharev2_share_gb gauge
 openstack_sharev2_share_gb{availability_zone="az1",id="4be93e2e-ffff-ffff-ffff-603e3ec2a5d6",name="share-test",project_id="ffff8fa0ca1a468db8ad00970c1effff",share_proto="NFS",share_type="az1",share_type_name="",status="available"} 1
-# HELP openstack_sharev2_share_status share_status
+# HELP openstack_sharev2_share_status Share status, the index of the status in the known share statuses
 # TYPE openstack_sharev2_share_status gauge
 openstack_sharev2_share_status{id="4be93e2e-ffff-ffff-ffff-603e3ec2a5d6",name="share-test",project_id="ffff8fa0ca1a468db8ad00970c1effff",share_proto="NFS",share_type="az1",share_type_name="",size="1",status="available"} 1
-# HELP openstack_sharev2_share_status_counter share_status_counter
+# HELP openstack_sharev2_share_status_counter Number of shares by status
 # TYPE openstack_sharev2_share_status_counter gauge
 openstack_sharev2_share_status_counter{status="available"} 1
 openstack_sharev2_share_status_counter{status="creating"} 0
@@ -39,10 +43,10 @@ openstack_sharev2_share_status_counter{status="shrinking_error"} 0
 openstack_sharev2_share_status_counter{status="soft_deleting"} 0
 openstack_sharev2_share_status_counter{status="unmanaging"} 0
 openstack_sharev2_share_status_counter{status="updating"} 0
-# HELP openstack_sharev2_shares_counter shares_counter
+# HELP openstack_sharev2_shares_counter Total number of shares
 # TYPE openstack_sharev2_shares_counter gauge
 openstack_sharev2_shares_counter 1
-# HELP openstack_sharev2_up up
+# HELP openstack_sharev2_up Whether the OpenStack API of the service is up (1=up, 0=down)
 # TYPE openstack_sharev2_up gauge
 openstack_sharev2_up 1
 `
@@ -0,0 +1,167 @@
+package exporters
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// groupExported returns true if at least one of the metrics collected by the Fn of the metric is exported.
+func (exporter *BaseOpenStackExporter) groupExported(name string) bool {
+	for _, metric := range exporter.Metrics {
+		if metric.group == name && !metric.filtered {
+			return true
+		}
+	}
+	return false
+}
+
+// collectFiltered runs fn, dropping the filtered metrics and labels it sends before they reach ch.
+// The series of a metric whose labels are dropped are held until fn returns, the series having the
+// same label values once the labels are dropped being merged into their sum, like sum without() of
+// PromQL. The series of the metrics having a series limit are held until fn returns as well, and
+// dropped altogether if they exceed the limit.
+func (exporter *BaseOpenStackExporter) collectFiltered(ctx context.Context, fn ListFunc, ch chan<- prometheus.Metric) error {
+	if exporter.MetricFilter.IsEmpty() {
+		return fn(ctx, exporter, ch)
+	}
+
+	limited := make(map[*PrometheusMetric]*limitedSeries)
+	export := func(described *PrometheusMetric, metric prometheus.Metric) {
+		if described == nil || described.seriesLimit == 0 {
+			ch <- metric
+			return
+		}
+		series, ok := limited[described]
+		if !ok {
+			series = &limitedSeries{}
+			limited[described] = series
+		}
+		series.count++
+		if series.count > described.seriesLimit {
+			series.metrics = nil
+			return
+		}
+		series.metrics = append(series.metrics, metric)
+	}
+
+	filtered := make(chan prometheus.Metric)
+	var merged []*mergedSeries
+	mergedByKey := make(map[*PrometheusMetric]map[string]*mergedSeries)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for metric := range filtered {
+			described := exporter.descs[metric.Desc()]
+			if described != nil && described.filtered {
+				continue
+			}
+			if described == nil || described.exportedDesc == nil {
+				export(described, metric)
+				continue
+			}
+
+			series, err := relabelSeries(described, metric)
+			if err != nil {
+				exporter.logger.Error("Failed to filter the labels of metric", "exporter", exporter.GetName(), "metric", metric.Desc().String(), "error", err)
+				continue
+			}
+			key := strings.Join(series.labelValues, "\xff")
+			byKey, ok := mergedByKey[described]
+			if !ok {
+				byKey = make(map[string]*mergedSeries)
+				mergedByKey[described] = byKey
+			}
+			if previous, ok := byKey[key]; ok {
+				previous.value += series.value
+				continue
+			}
+			byKey[key] = series
+			merged = append(merged, series)
+		}
+	}()
+
+	err := fn(ctx, exporter, filtered)
+	close(filtered)
+	<-done
+
+	for _, series := range merged {
+		metric, err := prometheus.NewConstMetric(series.described.exportedDesc, series.valueType, series.value, series.labelValues...)
+		if err != nil {
+			exporter.logger.Error("Failed to filter the labels of metric", "exporter", exporter.GetName(), "metric", series.described.fqName, "error", err)
+			continue
+		}
+		export(series.described, metric)
+	}
+
+	for described, series := range limited {
+		if series.count <= described.seriesLimit {
+			for _, metric := range series.metrics {
+				ch <- metric
+			}
+			continue
+		}
+		exporter.logger.Warn("metric exceeded its series limit, not exporting it", "exporter", exporter.GetName(), "metric", described.fqName, "series", series.count, "limit", described.seriesLimit)
+		ch <- prometheus.MustNewConstMetric(exporter.Metrics["series_limit_exceeded"].Metric,
+			prometheus.GaugeValue, float64(series.count), described.fqName)
+	}
+	return err
+}
+
+// resourceLabels are the labels identifying a resource, the metrics having one of them export a
+// series per resource.
+var resourceLabels = []string{"id", "uuid"}
+
+// perResource returns true if the metric has a series per resource, the default series limit only
+// applies to them.
+func perResource(labels []string) bool {
+	return slices.ContainsFunc(labels, func(label string) bool { return slices.Contains(resourceLabels, label) })
+}
+
+// limitedSeries holds the series of a metric having a series limit during a collection.
+type limitedSeries struct {
+	count   int
+	metrics []prometheus.Metric
+}
+
+// mergedSeries is a series of a metric whose labels are dropped, the sum of the series having its
+// label values once the labels are dropped.
+type mergedSeries struct {
+	described   *PrometheusMetric
+	labelValues []string
+	valueType   prometheus.ValueType
+	value       float64
+}
+
+// relabelSeries returns the series of a metric whose labels are dropped, with the values of the
+// labels kept.
+func relabelSeries(described *PrometheusMetric, metric prometheus.Metric) (*mergedSeries, error) {
+	var pb dto.Metric
+	if err := metric.Write(&pb); err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(pb.Label))
+	for _, pair := range pb.Label {
+		values[pair.GetName()] = pair.GetValue()
+	}
+	series := &mergedSeries{described: described, labelValues: make([]string, 0, len(described.kept))}
+	for _, i := range described.kept {
+		series.labelValues = append(series.labelValues, values[described.labels[i]])
+	}
+
+	switch {
+	case pb.Gauge != nil:
+		series.valueType, series.value = prometheus.GaugeValue, pb.Gauge.GetValue()
+	case pb.Counter != nil:
+		series.valueType, series.value = prometheus.CounterValue, pb.Counter.GetValue()
+	case pb.Untyped != nil:
+		series.valueType, series.value = prometheus.UntypedValue, pb.Untyped.GetValue()
+	default:
+		return nil, fmt.Errorf("unsupported metric type")
+	}
+	return series, nil
+}
@@ -1,6 +1,7 @@
 package exporters
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -30,6 +31,7 @@ import (
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+	"github.com/openstack-exporter/openstack-exporter/utils"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -55,42 +57,49 @@ type NeutronExporter struct {
 }
 
 var defaultNeutronMetrics = []Metric{
-	{Name: "floating_ips", Fn: ListFloatingIps},
-	{Name: "floating_ips_associated_not_active"},
-	{Name: "floating_ip", Labels: []string{"id", "floating_network_id", "router_id", "status", "project_id", "floating_ip_address"}},
-	{Name: "networks", Fn: ListNetworks},
+	{Name: "floating_ips", Fn: ListFloatingIps, Help: "Total number of floating IPs", Type: prometheus.GaugeValue},
+	{Name: "floating_ips_associated_not_active", Help: "Number of floating IPs associated with a port but not active", Type: prometheus.GaugeValue},
+	{Name: "floating_ip", Labels: []string{"id", "floating_network_id", "router_id", "status", "project_id", "floating_ip_address"}, Help: "Floating IP information", Type: prometheus.GaugeValue},
+	{Name: "networks", Fn: ListNetworks, Help: "Total number of networks", Type: prometheus.GaugeValue},
 	{Name: "network", Labels: []string{"id", "tenant_id", "status", "name", "is_shared", "is_external", "provider_network_type",
-		"provider_physical_network", "provider_segmentation_id", "subnets", "tags"}},
-	{Name: "security_groups", Fn: ListSecGroups},
-	{Name: "subnets", Fn: ListSubnets},
-	{Name: "subnet", Labels: []string{"id", "tenant_id", "name", "network_id", "cidr", "gateway_ip", "enable_dhcp", "dns_nameservers", "tags"}},
-	{Name: "port", Labels: []string{"uuid", "network_id", "mac_address", "device_owner", "status", "binding_vif_type", "admin_state_up", "fixed_ips"}, Fn: ListPorts},
-	{Name: "ports"},
-	{Name: "ports_no_ips"},
-	{Name: "ports_lb_not_active"},
-	{Name: "router", Labels: []string{"id", "name", "project_id", "admin_state_up", "status", "external_network_id"}},
-	{Name: "routers", Fn: ListRouters},
-	{Name: "routers_not_active"},
-	{Name: "l3_agent_of_router", Labels: []string{"router_id", "l3_agent_id", "ha_state", "agent_alive", "agent_admin_up", "agent_host"}},
-	{Name: "agent_state", Labels: []string{"id", "hostname", "service", "adminState", "availability_zone"}, Fn: ListAgentStates},
-	{Name: "network_ip_availabilities_total", Labels: []string{"network_id", "network_name", "ip_version", "cidr", "subnet_name", "project_id"}, Fn: ListNetworkIPAvailabilities},
-	{Name: "network_ip_availabilities_used", Labels: []string{"network_id", "network_name", "ip_version", "cidr", "subnet_name", "project_id"}},
-	{Name: "subnets_total", Labels: []string{"ip_version", "prefix", "prefix_length", "project_id", "subnet_pool_id", "subnet_pool_name"}, Fn: ListSubnetsPerPool},
-	{Name: "subnets_used", Labels: []string{"ip_version", "prefix", "prefix_length", "project_id", "subnet_pool_id", "subnet_pool_name"}},
-	{Name: "subnets_free", Labels: []string{"ip_version", "prefix", "prefix_length", "project_id", "subnet_pool_id", "subnet_pool_name"}},
-	{Name: "quota_network", Labels: []string{"type", "tenant"}, Fn: ListNetworkQuotas, Slow: true},
-	{Name: "quota_subnet", Labels: []string{"type", "tenant"}, Fn: nil, Slow: true},
-	{Name: "quota_subnetpool", Labels: []string{"type", "tenant"}, Fn: nil, Slow: true},
-	{Name: "quota_port", Labels: []string{"type", "tenant"}, Fn: nil, Slow: true},
-	{Name: "quota_router", Labels: []string{"type", "tenant"}, Fn: nil, Slow: true},
-	{Name: "quota_floatingip", Labels: []string{"type", "tenant"}, Fn: nil, Slow: true},
-	{Name: "quota_security_group", Labels: []string{"type", "tenant"}, Fn: nil, Slow: true},
-	{Name: "quota_security_group_rule", Labels: []string{"type", "tenant"}, Fn: nil, Slow: true},
-	{Name: "quota_rbac_policy", Labels: []string{"type", "tenant"}, Fn: nil, Slow: true},
+		"provider_physical_network", "provider_segmentation_id", "subnets", "tags"}, States: network_status, Help: "Network status, the index of the status in the known network statuses", Type: prometheus.GaugeValue},
+	{Name: "security_groups", Fn: ListSecGroups, Help: "Total number of security groups", Type: prometheus.GaugeValue},
+	{Name: "subnets", Fn: ListSubnets, Help: "Total number of subnets", Type: prometheus.GaugeValue},
+	{Name: "subnet", Labels: []string{"id", "tenant_id", "name", "network_id", "cidr", "gateway_ip", "enable_dhcp", "dns_nameservers", "tags"}, Help: "Subnet information", Type: prometheus.GaugeValue},
+	{Name: "port", Labels: []string{"uuid", "network_id", "mac_address", "device_owner", "status", "binding_vif_type", "admin_state_up", "fixed_ips"}, Fn: ListPorts, Help: "Port information", Type: prometheus.GaugeValue},
+	{Name: "ports", Help: "Total number of ports", Type: prometheus.GaugeValue},
+	{Name: "ports_no_ips", Help: "Number of active ports without IP addresses", Type: prometheus.GaugeValue},
+	{Name: "ports_lb_not_active", Help: "Number of load balancer ports not active", Type: prometheus.GaugeValue},
+	{Name: "routers", Fn: ListRouters, Help: "Total number of routers", Type: prometheus.GaugeValue},
+	{Name: "router", Labels: []string{"id", "name", "project_id", "admin_state_up", "status", "external_network_id"}, Help: "Router information", Type: prometheus.GaugeValue},
+	{Name: "routers_not_active", Help: "Number of routers not active", Type: prometheus.GaugeValue},
+	{Name: "l3_agent_of_router", Labels: []string{"router_id", "l3_agent_id", "ha_state", "agent_alive", "agent_admin_up", "agent_host"}, Help: "L3 agent router assignment", Type: prometheus.GaugeValue},
+	{Name: "agent_state", Labels: []string{"id", "hostname", "service", "adminState", "availability_zone"}, Fn: ListAgentStates, Help: "Agent state (1=up, 0=down)", Type: prometheus.GaugeValue},
+	{Name: "network_ip_availabilities_total", Labels: []string{"network_id", "network_name", "ip_version", "cidr", "subnet_name", "project_id"}, Fn: ListNetworkIPAvailabilities, Help: "Total IPs in the subnet of the network", Type: prometheus.GaugeValue},
+	{Name: "network_ip_availabilities_used", Labels: []string{"network_id", "network_name", "ip_version", "cidr", "subnet_name", "project_id"}, Help: "Used IPs in the subnet of the network", Type: prometheus.GaugeValue},
+	{Name: "subnets_total", Labels: []string{"ip_version", "prefix", "prefix_length", "project_id", "subnet_pool_id", "subnet_pool_name"}, Fn: ListSubnetsPerPool, Help: "Total subnets in the subnet pool", Type: prometheus.GaugeValue},
+	{Name: "subnets_used", Labels: []string{"ip_version", "prefix", "prefix_length", "project_id", "subnet_pool_id", "subnet_pool_name"}, Help: "Used subnets in the subnet pool", Type: prometheus.GaugeValue},
+	{Name: "subnets_free", Labels: []string{"ip_version", "prefix", "prefix_length", "project_id", "subnet_pool_id", "subnet_pool_name"}, Help: "Free subnets in the subnet pool", Type: prometheus.GaugeValue},
+	{Name: "quota_network", Labels: []string{"type", "tenant"}, Fn: ListNetworkQuotas, Slow: true, Help: "Quota of networks of the tenant, by type (used, reserved or limit)", Type: prometheus.GaugeValue},
+	{Name: "quota_subnet", Labels: []string{"type", "tenant"}, Fn: nil, Slow: true, Help: "Quota of subnets of the tenant, by type (used, reserved or limit)", Type: prometheus.GaugeValue},
+	{Name: "quota_subnetpool", Labels: []string{"type", "tenant"}, Fn: nil, Slow: true, Help: "Quota of subnet pools of the tenant, by type (used, reserved or limit)", Type: prometheus.GaugeValue},
+	{Name: "quota_port", Labels: []string{"type", "tenant"}, Fn: nil, Slow: true, Help: "Quota of ports of the tenant, by type (used, reserved or limit)", Type: prometheus.GaugeValue},
+	{Name: "quota_router", Labels: []string{"type", "tenant"}, Fn: nil, Slow: true, Help: "Quota of routers of the tenant, by type (used, reserved or limit)", Type: prometheus.GaugeValue},
+	{Name: "quota_floatingip", Labels: []string{"type", "tenant"}, Fn: nil, Slow: true, Help: "Quota of floating IPs of the tenant, by type (used, reserved or limit)", Type: prometheus.GaugeValue},
+	{Name: "quota_security_group", Labels: []string{"type", "tenant"}, Fn: nil, Slow: true, Help: "Quota of security groups of the tenant, by type (used, reserved or limit)", Type: prometheus.GaugeValue},
+	{Name: "quota_security_group_rule", Labels: []string{"type", "tenant"}, Fn: nil, Slow: true, Help: "Quota of security group rules of the tenant, by type (used, reserved or limit)", Type: prometheus.GaugeValue},
+	{Name: "quota_rbac_policy", Labels: []string{"type", "tenant"}, Fn: nil, Slow: true, Help: "Quota of RBAC policies of the tenant, by type (used, reserved or limit)", Type: prometheus.GaugeValue},
+}
+
+// neutronMappedResources are the resources whose tags can be mapped to labels of their metric.
+var neutronMappedResources = map[string]string{
+	"network": "neutron.network",
+	"port":    "neutron.port",
+	"router":  "neutron.router",
 }
 
 // NewNeutronExporter : returns a pointer to NeutronExporter
-func NewNeutronExporter(config *ExporterConfig, logger *slog.Logger) (*NeutronExporter, error) {
+func NewNeutronExporter(ctx context.Context, config *ExporterConfig, logger *slog.Logger) (*NeutronExporter, error) {
 	exporter := NeutronExporter{
 		BaseOpenStackExporter{
 			Name:           "neutron",
@@ -100,11 +109,18 @@ func NewNeutronExporter(config *ExporterConfig, logger *slog.Logger) (*NeutronEx
 	}
 
 	for _, metric := range defaultNeutronMetrics {
+		if resource, ok := neutronMappedResources[metric.Name]; ok {
+			labels, err := exporter.mappedLabels(resource, metric.Labels)
+			if err != nil {
+				return nil, err
+			}
+			metric.Labels = labels
+		}
 		if exporter.isDeprecatedMetric(&metric) {
 			continue
 		}
 		if !exporter.isSlowMetric(&metric) {
-			exporter.AddMetric(metric.Name, metric.Fn, metric.Labels, metric.DeprecatedVersion, nil)
+			exporter.addMetric(metric, nil)
 		}
 	}
 
@@ -112,7 +128,7 @@ func NewNeutronExporter(config *ExporterConfig, logger *slog.Logger) (*NeutronEx
 }
 
 // ListFloatingIps : count total number of instantiated FloatingIPs and those that are associated to private IP but not in ACTIVE state
-func ListFloatingIps(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListFloatingIps(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allFloatingIPs []floatingips.FloatingIP
 
 	allPagesFloatingIPs, err := floatingips.List(exporter.Client, floatingips.ListOpts{}).AllPages()
@@ -128,7 +144,7 @@ func ListFloatingIps(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metri
 	failedFIPs := 0
 	for _, fip := range allFloatingIPs {
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["floating_ip"].Metric,
-			prometheus.GaugeValue, 1, fip.ID, fip.FloatingNetworkID, fip.RouterID, fip.Status, fip.ProjectID, fip.FloatingIP)
+			exporter.Metrics["floating_ip"].Type, 1, fip.ID, fip.FloatingNetworkID, fip.RouterID, fip.Status, fip.ProjectID, fip.FloatingIP)
 		if fip.FixedIP != "" {
 			if fip.Status != "ACTIVE" {
 				failedFIPs = failedFIPs + 1
@@ -137,15 +153,15 @@ func ListFloatingIps(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metri
 	}
 
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["floating_ips"].Metric,
-		prometheus.GaugeValue, float64(len(allFloatingIPs)))
+		exporter.Metrics["floating_ips"].Type, float64(len(allFloatingIPs)))
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["floating_ips_associated_not_active"].Metric,
-		prometheus.GaugeValue, float64(failedFIPs))
+		exporter.Metrics["floating_ips_associated_not_active"].Type, float64(failedFIPs))
 
 	return nil
 }
 
 // ListAgentStates : list agent state per node
-func ListAgentStates(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListAgentStates(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allAgents []agents.Agent
 
 	allPagesAgents, err := agents.List(exporter.Client, agents.ListOpts{}).AllPages()
@@ -182,14 +198,14 @@ func ListAgentStates(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metri
 		zone = agent.AvailabilityZone
 
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["agent_state"].Metric,
-			prometheus.CounterValue, float64(state), id, agent.Host, agent.Binary, adminState, zone)
+			exporter.Metrics["agent_state"].Type, float64(state), id, agent.Host, agent.Binary, adminState, zone)
 	}
 
 	return nil
 }
 
 // ListNetworks : Count total number of instantiated Networks and list each Network info
-func ListNetworks(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListNetworks(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	type NetworkWithExt struct {
 		networks.Network
 		external.NetworkExternalExt
@@ -207,20 +223,21 @@ func ListNetworks(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric)
 		return err
 	}
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["networks"].Metric,
-		prometheus.GaugeValue, float64(len(allNetworks)))
+		exporter.Metrics["networks"].Type, float64(len(allNetworks)))
 	if !exporter.MetricIsDisabled("network") {
 		for _, net := range allNetworks {
-			ch <- prometheus.MustNewConstMetric(exporter.Metrics["network"].Metric,
-				prometheus.GaugeValue, float64(mapNetworkStatus(net.Status)), net.ID, net.TenantID, net.Status, net.Name,
-				strconv.FormatBool(net.Shared), strconv.FormatBool(net.External), net.NetworkType,
-				net.PhysicalNetwork, net.SegmentationID, strings.Join(net.Subnets, ","), strings.Join(net.Tags, ","))
+			exporter.SendStatus(ch, "network", network_status, mapNetworkStatus(net.Status), net.Status,
+				append([]string{net.ID, net.TenantID, net.Status, net.Name,
+					strconv.FormatBool(net.Shared), strconv.FormatBool(net.External), net.NetworkType,
+					net.PhysicalNetwork, net.SegmentationID, strings.Join(net.Subnets, ","), strings.Join(net.Tags, ",")},
+					exporter.LabelMappings.Get("neutron.network").Extract(utils.TagsMetadata(net.Tags))...)...)
 		}
 	}
 	return nil
 }
 
 // ListSecGroups : count total number of instantiated Security Groups
-func ListSecGroups(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListSecGroups(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allSecurityGroups []groups.SecGroup
 
 	allPagesSecurityGroups, err := groups.List(exporter.Client, groups.ListOpts{}).AllPages()
@@ -233,13 +250,13 @@ func ListSecGroups(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric)
 		return err
 	}
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["security_groups"].Metric,
-		prometheus.GaugeValue, float64(len(allSecurityGroups)))
+		exporter.Metrics["security_groups"].Type, float64(len(allSecurityGroups)))
 
 	return nil
 }
 
 // ListSubnets : count total number of instantiated Subnets and list each Subnet info
-func ListSubnets(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListSubnets(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allSubnets []subnets.Subnet
 
 	allPagesSubnets, err := subnets.List(exporter.Client, subnets.ListOpts{}).AllPages()
@@ -252,11 +269,11 @@ func ListSubnets(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) e
 		return err
 	}
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["subnets"].Metric,
-		prometheus.GaugeValue, float64(len(allSubnets)))
+		exporter.Metrics["subnets"].Type, float64(len(allSubnets)))
 	if !exporter.MetricIsDisabled("subnet") {
 		for _, subnet := range allSubnets {
 			ch <- prometheus.MustNewConstMetric(exporter.Metrics["subnet"].Metric,
-				prometheus.GaugeValue, 1.0, subnet.ID, subnet.TenantID, subnet.Name, subnet.NetworkID, subnet.CIDR,
+				exporter.Metrics["subnet"].Type, 1.0, subnet.ID, subnet.TenantID, subnet.Name, subnet.NetworkID, subnet.CIDR,
 				subnet.GatewayIP, strconv.FormatBool(subnet.EnableDHCP), strings.Join(subnet.DNSNameservers, ","), strings.Join(subnet.Tags, ","))
 		}
 	}
@@ -270,7 +287,7 @@ type PortBinding struct {
 }
 
 // ListPorts generates metrics about ports inside the OpenStack cloud
-func ListPorts(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListPorts(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allPorts []PortBinding
 
 	allPagesPorts, err := ports.List(exporter.Client, ports.ListOpts{}).AllPages()
@@ -307,29 +324,30 @@ func ListPorts(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) err
 				}
 			}
 			ch <- prometheus.MustNewConstMetric(exporter.Metrics["port"].Metric,
-				prometheus.GaugeValue, 1, port.ID, port.NetworkID, port.MACAddress, port.DeviceOwner,
-				port.Status, port.VIFType, strconv.FormatBool(port.AdminStateUp), fixedIPs)
+				exporter.Metrics["port"].Type, 1, append([]string{port.ID, port.NetworkID, port.MACAddress, port.DeviceOwner,
+					port.Status, port.VIFType, strconv.FormatBool(port.AdminStateUp), fixedIPs},
+					exporter.LabelMappings.Get("neutron.port").Extract(utils.TagsMetadata(port.Tags))...)...)
 		}
 	}
 
 	// NOTE(mnaser): We should deprecate this and users can replace it by
 	//               count(openstack_neutron_port)
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["ports"].Metric,
-		prometheus.GaugeValue, float64(len(allPorts)))
+		exporter.Metrics["ports"].Type, float64(len(allPorts)))
 
 	// NOTE(mnaser): We should deprecate this and users can replace it by:
 	//               count(openstack_neutron_port{device_owner="neutron:LOADBALANCERV2",status!="ACTIVE"})
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["ports_lb_not_active"].Metric,
-		prometheus.GaugeValue, lbaasPortsInactive)
+		exporter.Metrics["ports_lb_not_active"].Type, lbaasPortsInactive)
 
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["ports_no_ips"].Metric,
-		prometheus.GaugeValue, portsWithNoIP)
+		exporter.Metrics["ports_no_ips"].Type, portsWithNoIP)
 
 	return nil
 }
 
 // ListNetworkIPAvailabilities : count total number of used IPs per Network
-func ListNetworkIPAvailabilities(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListNetworkIPAvailabilities(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 
 	type CustomSubnetIPAvailability struct {
 		SubnetName string      `json:"subnet_name"`
@@ -397,12 +415,12 @@ func ListNetworkIPAvailabilities(exporter *BaseOpenStackExporter, ch chan<- prom
 			usedFloat64, _ := usedBig.Float64()
 
 			ch <- prometheus.MustNewConstMetric(exporter.Metrics["network_ip_availabilities_total"].Metric,
-				prometheus.GaugeValue, totalFloat64, network.NetworkID,
+				exporter.Metrics["network_ip_availabilities_total"].Type, totalFloat64, network.NetworkID,
 				network.NetworkName, strconv.Itoa(subnet.IPVersion), subnet.CIDR,
 				subnet.SubnetName, projectID)
 
 			ch <- prometheus.MustNewConstMetric(exporter.Metrics["network_ip_availabilities_used"].Metric,
-				prometheus.GaugeValue, usedFloat64, network.NetworkID,
+				exporter.Metrics["network_ip_availabilities_used"].Type, usedFloat64, network.NetworkID,
 				network.NetworkName, strconv.Itoa(subnet.IPVersion), subnet.CIDR,
 				subnet.SubnetName, projectID)
 		}
@@ -412,7 +430,7 @@ func ListNetworkIPAvailabilities(exporter *BaseOpenStackExporter, ch chan<- prom
 }
 
 // ListRouters : count total number of instantiated Routers and those that are not in ACTIVE state
-func ListRouters(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListRouters(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allRouters []routers.Router
 	// We need to know if neutron has ovn backend
 	var ovnBackendEnabled = false
@@ -448,8 +466,9 @@ func ListRouters(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) e
 		}
 		if !exporter.MetricIsDisabled("router") {
 			ch <- prometheus.MustNewConstMetric(exporter.Metrics["router"].Metric,
-				prometheus.GaugeValue, 1, router.ID, router.Name, router.ProjectID,
-				strconv.FormatBool(router.AdminStateUp), router.Status, router.GatewayInfo.NetworkID)
+				exporter.Metrics["router"].Type, 1, append([]string{router.ID, router.Name, router.ProjectID,
+					strconv.FormatBool(router.AdminStateUp), router.Status, router.GatewayInfo.NetworkID},
+					exporter.LabelMappings.Get("neutron.router").Extract(utils.TagsMetadata(router.Tags))...)...)
 		}
 		if ovnBackendEnabled {
 			continue
@@ -472,16 +491,16 @@ func ListRouters(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) e
 				}
 
 				ch <- prometheus.MustNewConstMetric(exporter.Metrics["l3_agent_of_router"].Metric,
-					prometheus.GaugeValue, float64(state), router.ID, agent.ID,
+					exporter.Metrics["l3_agent_of_router"].Type, float64(state), router.ID, agent.ID,
 					agent.HAState, strconv.FormatBool(agent.Alive), strconv.FormatBool(agent.AdminStateUp), agent.Host)
 			}
 		}
 	}
 
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["routers"].Metric,
-		prometheus.GaugeValue, float64(len(allRouters)))
+		exporter.Metrics["routers"].Type, float64(len(allRouters)))
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["routers_not_active"].Metric,
-		prometheus.GaugeValue, float64(failedRouters))
+		exporter.Metrics["routers_not_active"].Type, float64(failedRouters))
 
 	return nil
 }
@@ -564,7 +583,7 @@ func calculateUsedSubnets(subnets []netip.Prefix, ipPrefix netip.Prefix, prefixL
 }
 
 // ListSubnetsPerPool : Count used/free/total number of subnets per subnet pool
-func ListSubnetsPerPool(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListSubnetsPerPool(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	allPagesSubnets, err := subnets.List(exporter.Client, subnets.ListOpts{}).AllPages()
 	if err != nil {
 		return err
@@ -603,12 +622,12 @@ func ListSubnetsPerPool(exporter *BaseOpenStackExporter, ch chan<- prometheus.Me
 
 				totalSubnets := math.Pow(2, float64(prefixLength-int(ipPrefix.Bits())))
 				ch <- prometheus.MustNewConstMetric(exporter.Metrics["subnets_total"].Metric,
-					prometheus.GaugeValue, totalSubnets, strconv.Itoa(subnetPool.IPversion), ipPrefix.String(), strconv.Itoa(prefixLength),
+					exporter.Metrics["subnets_total"].Type, totalSubnets, strconv.Itoa(subnetPool.IPversion), ipPrefix.String(), strconv.Itoa(prefixLength),
 					subnetPool.ProjectID, subnetPool.ID, subnetPool.Name)
 
 				usedSubnets := calculateUsedSubnets(subnetPool.subnets, ipPrefix, prefixLength)
 				ch <- prometheus.MustNewConstMetric(exporter.Metrics["subnets_used"].Metric,
-					prometheus.GaugeValue, usedSubnets, strconv.Itoa(subnetPool.IPversion), ipPrefix.String(), strconv.Itoa(prefixLength),
+					exporter.Metrics["subnets_used"].Type, usedSubnets, strconv.Itoa(subnetPool.IPversion), ipPrefix.String(), strconv.Itoa(prefixLength),
 					subnetPool.ProjectID, subnetPool.ID, subnetPool.Name)
 
 				freeSubnets, err := calculateFreeSubnets(&ipPrefix, subnetPool.subnets, prefixLength)
@@ -616,7 +635,7 @@ func ListSubnetsPerPool(exporter *BaseOpenStackExporter, ch chan<- prometheus.Me
 					return err
 				}
 				ch <- prometheus.MustNewConstMetric(exporter.Metrics["subnets_free"].Metric,
-					prometheus.GaugeValue, freeSubnets, strconv.Itoa(subnetPool.IPversion), ipPrefix.String(), strconv.Itoa(prefixLength),
+					exporter.Metrics["subnets_free"].Type, freeSubnets, strconv.Itoa(subnetPool.IPversion), ipPrefix.String(), strconv.Itoa(prefixLength),
 					subnetPool.ProjectID, subnetPool.ID, subnetPool.Name)
 			}
 		}
@@ -625,7 +644,7 @@ func ListSubnetsPerPool(exporter *BaseOpenStackExporter, ch chan<- prometheus.Me
 	return nil
 }
 
-func ListNetworkQuotas(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListNetworkQuotas(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allProjects []projects.Project
 	var eo gophercloud.EndpointOpts
 
@@ -645,12 +664,7 @@ func ListNetworkQuotas(exporter *BaseOpenStackExporter, ch chan<- prometheus.Met
 		return err
 	}
 
-	allPagesProject, err := projects.List(c, projects.ListOpts{DomainID: exporter.DomainID}).AllPages()
-	if err != nil {
-		return err
-	}
-
-	allProjects, err = projects.ExtractProjects(allPagesProject)
+	allProjects, err = listProjects(ctx, c, exporter.DomainID)
 	if err != nil {
 		return err
 	}
@@ -663,59 +677,59 @@ func ListNetworkQuotas(exporter *BaseOpenStackExporter, ch chan<- prometheus.Met
 		}
 
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_network"].Metric,
-			prometheus.GaugeValue, float64(quota.Network.Used), "used", p.Name)
+			exporter.Metrics["quota_network"].Type, float64(quota.Network.Used), "used", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_network"].Metric,
-			prometheus.GaugeValue, float64(quota.Network.Reserved), "reserved", p.Name)
+			exporter.Metrics["quota_network"].Type, float64(quota.Network.Reserved), "reserved", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_network"].Metric,
-			prometheus.GaugeValue, float64(quota.Network.Limit), "limit", p.Name)
+			exporter.Metrics["quota_network"].Type, float64(quota.Network.Limit), "limit", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_subnet"].Metric,
-			prometheus.GaugeValue, float64(quota.Subnet.Used), "used", p.Name)
+			exporter.Metrics["quota_subnet"].Type, float64(quota.Subnet.Used), "used", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_subnet"].Metric,
-			prometheus.GaugeValue, float64(quota.Subnet.Reserved), "reserved", p.Name)
+			exporter.Metrics["quota_subnet"].Type, float64(quota.Subnet.Reserved), "reserved", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_subnet"].Metric,
-			prometheus.GaugeValue, float64(quota.Subnet.Limit), "limit", p.Name)
+			exporter.Metrics["quota_subnet"].Type, float64(quota.Subnet.Limit), "limit", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_subnetpool"].Metric,
-			prometheus.GaugeValue, float64(quota.SubnetPool.Used), "used", p.Name)
+			exporter.Metrics["quota_subnetpool"].Type, float64(quota.SubnetPool.Used), "used", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_subnetpool"].Metric,
-			prometheus.GaugeValue, float64(quota.SubnetPool.Reserved), "reserved", p.Name)
+			exporter.Metrics["quota_subnetpool"].Type, float64(quota.SubnetPool.Reserved), "reserved", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_subnetpool"].Metric,
-			prometheus.GaugeValue, float64(quota.SubnetPool.Limit), "limit", p.Name)
+			exporter.Metrics["quota_subnetpool"].Type, float64(quota.SubnetPool.Limit), "limit", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_port"].Metric,
-			prometheus.GaugeValue, float64(quota.Port.Used), "used", p.Name)
+			exporter.Metrics["quota_port"].Type, float64(quota.Port.Used), "used", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_port"].Metric,
-			prometheus.GaugeValue, float64(quota.Port.Reserved), "reserved", p.Name)
+			exporter.Metrics["quota_port"].Type, float64(quota.Port.Reserved), "reserved", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_port"].Metric,
-			prometheus.GaugeValue, float64(quota.Port.Limit), "limit", p.Name)
+			exporter.Metrics["quota_port"].Type, float64(quota.Port.Limit), "limit", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_router"].Metric,
-			prometheus.GaugeValue, float64(quota.Router.Used), "used", p.Name)
+			exporter.Metrics["quota_router"].Type, float64(quota.Router.Used), "used", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_router"].Metric,
-			prometheus.GaugeValue, float64(quota.Router.Reserved), "reserved", p.Name)
+			exporter.Metrics["quota_router"].Type, float64(quota.Router.Reserved), "reserved", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_router"].Metric,
-			prometheus.GaugeValue, float64(quota.Router.Limit), "limit", p.Name)
+			exporter.Metrics["quota_router"].Type, float64(quota.Router.Limit), "limit", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_floatingip"].Metric,
-			prometheus.GaugeValue, float64(quota.FloatingIP.Used), "used", p.Name)
+			exporter.Metrics["quota_floatingip"].Type, float64(quota.FloatingIP.Used), "used", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_floatingip"].Metric,
-			prometheus.GaugeValue, float64(quota.FloatingIP.Reserved), "reserved", p.Name)
+			exporter.Metrics["quota_floatingip"].Type, float64(quota.FloatingIP.Reserved), "reserved", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_floatingip"].Metric,
-			prometheus.GaugeValue, float64(quota.FloatingIP.Limit), "limit", p.Name)
+			exporter.Metrics["quota_floatingip"].Type, float64(quota.FloatingIP.Limit), "limit", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_security_group"].Metric,
-			prometheus.GaugeValue, float64(quota.SecurityGroup.Used), "used", p.Name)
+			exporter.Metrics["quota_security_group"].Type, float64(quota.SecurityGroup.Used), "used", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_security_group"].Metric,
-			prometheus.GaugeValue, float64(quota.SecurityGroup.Reserved), "reserved", p.Name)
+			exporter.Metrics["quota_security_group"].Type, float64(quota.SecurityGroup.Reserved), "reserved", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_security_group"].Metric,
-			prometheus.GaugeValue, float64(quota.SecurityGroup.Limit), "limit", p.Name)
+			exporter.Metrics["quota_security_group"].Type, float64(quota.SecurityGroup.Limit), "limit", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_security_group_rule"].Metric,
-			prometheus.GaugeValue, float64(quota.SecurityGroupRule.Used), "used", p.Name)
+			exporter.Metrics["quota_security_group_rule"].Type, float64(quota.SecurityGroupRule.Used), "used", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_security_group_rule"].Metric,
-			prometheus.GaugeValue, float64(quota.SecurityGroupRule.Reserved), "reserved", p.Name)
+			exporter.Metrics["quota_security_group_rule"].Type, float64(quota.SecurityGroupRule.Reserved), "reserved", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_security_group_rule"].Metric,
-			prometheus.GaugeValue, float64(quota.SecurityGroupRule.Limit), "limit", p.Name)
+			exporter.Metrics["quota_security_group_rule"].Type, float64(quota.SecurityGroupRule.Limit), "limit", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_rbac_policy"].Metric,
-			prometheus.GaugeValue, float64(quota.RBACPolicy.Used), "used", p.Name)
+			exporter.Metrics["quota_rbac_policy"].Type, float64(quota.RBACPolicy.Used), "used", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_rbac_policy"].Metric,
-			prometheus.GaugeValue, float64(quota.RBACPolicy.Reserved), "reserved", p.Name)
+			exporter.Metrics["quota_rbac_policy"].Type, float64(quota.RBACPolicy.Reserved), "reserved", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_rbac_policy"].Metric,
-			prometheus.GaugeValue, float64(quota.RBACPolicy.Limit), "limit", p.Name)
+			exporter.Metrics["quota_rbac_policy"].Type, float64(quota.RBACPolicy.Limit), "limit", p.Name)
 	}
 	return nil
 }
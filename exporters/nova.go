@@ -1,6 +1,7 @@
 package exporters
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -79,46 +80,46 @@ var defaultNovaServerStatusLabels = []string{"id", "status", "name", "tenant_id"
 	"address_ipv6", "host_id", "hypervisor_hostname", "uuid", "availability_zone", "flavor_id", "instance_libvirt"}
 
 var defaultNovaMetrics = []Metric{
-	{Name: "flavors", Fn: ListFlavors},
-	{Name: "flavor", Labels: []string{"id", "name", "vcpus", "ram", "disk", "is_public"}},
-	{Name: "availability_zones", Fn: ListAZs},
-	{Name: "security_groups", Fn: ListComputeSecGroups},
-	{Name: "total_vms", Fn: ListAllServers},
-	{Name: "agent_state", Labels: []string{"id", "hostname", "service", "adminState", "zone", "disabledReason"}, Fn: ListNovaAgentState},
-	{Name: "running_vms", Labels: []string{"hostname", "availability_zone", "aggregates"}, Fn: ListHypervisors},
-	{Name: "current_workload", Labels: []string{"hostname", "availability_zone", "aggregates"}},
-	{Name: "vcpus_available", Labels: []string{"hostname", "availability_zone", "aggregates"}},
-	{Name: "vcpus_used", Labels: []string{"hostname", "availability_zone", "aggregates"}},
-	{Name: "memory_available_bytes", Labels: []string{"hostname", "availability_zone", "aggregates"}},
-	{Name: "memory_used_bytes", Labels: []string{"hostname", "availability_zone", "aggregates"}},
-	{Name: "local_storage_available_bytes", Labels: []string{"hostname", "availability_zone", "aggregates"}},
-	{Name: "local_storage_used_bytes", Labels: []string{"hostname", "availability_zone", "aggregates"}},
-	{Name: "free_disk_bytes", Labels: []string{"hostname", "availability_zone", "aggregates"}},
-	{Name: "server_status", Labels: defaultNovaServerStatusLabels},
-	{Name: "limits_vcpus_max", Labels: []string{"tenant", "tenant_id"}, Fn: ListComputeLimits, Slow: true},
-	{Name: "limits_vcpus_used", Labels: []string{"tenant", "tenant_id"}, Slow: true},
-	{Name: "limits_memory_max", Labels: []string{"tenant", "tenant_id"}, Slow: true},
-	{Name: "limits_memory_used", Labels: []string{"tenant", "tenant_id"}, Slow: true},
-	{Name: "limits_instances_used", Labels: []string{"tenant", "tenant_id"}, Slow: true},
-	{Name: "limits_instances_max", Labels: []string{"tenant", "tenant_id"}, Slow: true},
-	{Name: "server_local_gb", Labels: []string{"name", "id", "tenant_id"}, Fn: ListUsage, Slow: true},
-	{Name: "quota_cores", Labels: []string{"type", "tenant"}, Fn: ListQuotas},
-	{Name: "quota_instances", Labels: []string{"type", "tenant"}},
-	{Name: "quota_key_pairs", Labels: []string{"type", "tenant"}},
-	{Name: "quota_metadata_items", Labels: []string{"type", "tenant"}},
-	{Name: "quota_ram", Labels: []string{"type", "tenant"}},
-	{Name: "quota_server_groups", Labels: []string{"type", "tenant"}},
-	{Name: "quota_server_group_members", Labels: []string{"type", "tenant"}},
-	{Name: "quota_fixed_ips", Labels: []string{"type", "tenant"}},
-	{Name: "quota_floating_ips", Labels: []string{"type", "tenant"}},
-	{Name: "quota_security_group_rules", Labels: []string{"type", "tenant"}},
-	{Name: "quota_security_groups", Labels: []string{"type", "tenant"}},
-	{Name: "quota_injected_file_content_bytes", Labels: []string{"type", "tenant"}},
-	{Name: "quota_injected_file_path_bytes", Labels: []string{"type", "tenant"}},
-	{Name: "quota_injected_files", Labels: []string{"type", "tenant"}},
+	{Name: "flavors", Fn: ListFlavors, Help: "Total number of flavors", Type: prometheus.GaugeValue},
+	{Name: "flavor", Labels: []string{"id", "name", "vcpus", "ram", "disk", "is_public"}, Help: "Flavor information", Type: prometheus.GaugeValue},
+	{Name: "availability_zones", Fn: ListAZs, Help: "Total number of availability zones", Type: prometheus.GaugeValue},
+	{Name: "security_groups", Fn: ListComputeSecGroups, Help: "Total number of security groups", Type: prometheus.GaugeValue},
+	{Name: "total_vms", Fn: ListAllServers, Help: "Total number of VMs", Type: prometheus.GaugeValue},
+	{Name: "server_status", Labels: defaultNovaServerStatusLabels, States: server_status, Help: "Server status, the index of the status in the known server statuses", Type: prometheus.GaugeValue},
+	{Name: "agent_state", Labels: []string{"id", "hostname", "service", "adminState", "zone", "disabledReason"}, Fn: ListNovaAgentState, Help: "Agent state (1=up, 0=down)", Type: prometheus.GaugeValue},
+	{Name: "running_vms", Labels: []string{"hostname", "availability_zone", "aggregates"}, Fn: ListHypervisors, Help: "Number of running VMs of the hypervisor", Type: prometheus.GaugeValue},
+	{Name: "current_workload", Labels: []string{"hostname", "availability_zone", "aggregates"}, Help: "Current workload of the hypervisor", Type: prometheus.GaugeValue},
+	{Name: "vcpus_available", Labels: []string{"hostname", "availability_zone", "aggregates"}, Help: "Available vCPUs of the hypervisor", Type: prometheus.GaugeValue},
+	{Name: "vcpus_used", Labels: []string{"hostname", "availability_zone", "aggregates"}, Help: "Used vCPUs of the hypervisor", Type: prometheus.GaugeValue},
+	{Name: "memory_available_bytes", Labels: []string{"hostname", "availability_zone", "aggregates"}, Help: "Available memory of the hypervisor in bytes", Type: prometheus.GaugeValue, Unit: "bytes"},
+	{Name: "memory_used_bytes", Labels: []string{"hostname", "availability_zone", "aggregates"}, Help: "Used memory of the hypervisor in bytes", Type: prometheus.GaugeValue, Unit: "bytes"},
+	{Name: "local_storage_available_bytes", Labels: []string{"hostname", "availability_zone", "aggregates"}, Help: "Available local storage of the hypervisor in bytes", Type: prometheus.GaugeValue, Unit: "bytes"},
+	{Name: "local_storage_used_bytes", Labels: []string{"hostname", "availability_zone", "aggregates"}, Help: "Used local storage of the hypervisor in bytes", Type: prometheus.GaugeValue, Unit: "bytes"},
+	{Name: "free_disk_bytes", Labels: []string{"hostname", "availability_zone", "aggregates"}, Help: "Free disk space of the hypervisor in bytes", Type: prometheus.GaugeValue, Unit: "bytes"},
+	{Name: "limits_vcpus_max", Labels: []string{"tenant", "tenant_id"}, Fn: ListComputeLimits, Slow: true, Help: "Maximum vCPUs limit of the tenant", Type: prometheus.GaugeValue},
+	{Name: "limits_vcpus_used", Labels: []string{"tenant", "tenant_id"}, Slow: true, Help: "Used vCPUs of the tenant", Type: prometheus.GaugeValue},
+	{Name: "limits_memory_max", Labels: []string{"tenant", "tenant_id"}, Slow: true, Help: "Maximum memory limit of the tenant in MB", Type: prometheus.GaugeValue, Unit: "megabytes"},
+	{Name: "limits_memory_used", Labels: []string{"tenant", "tenant_id"}, Slow: true, Help: "Used memory of the tenant in MB", Type: prometheus.GaugeValue, Unit: "megabytes"},
+	{Name: "limits_instances_used", Labels: []string{"tenant", "tenant_id"}, Slow: true, Help: "Used instances of the tenant", Type: prometheus.GaugeValue},
+	{Name: "limits_instances_max", Labels: []string{"tenant", "tenant_id"}, Slow: true, Help: "Maximum instances limit of the tenant", Type: prometheus.GaugeValue},
+	{Name: "server_local_gb", Labels: []string{"name", "id", "tenant_id"}, Fn: ListUsage, Slow: true, Help: "Server local disk size in GB", Type: prometheus.GaugeValue, Unit: "gigabytes"},
+	{Name: "quota_cores", Labels: []string{"type", "tenant"}, Fn: ListQuotas, Help: "Quota of cores of the tenant, by type (in_use, reserved or limit)", Type: prometheus.GaugeValue},
+	{Name: "quota_instances", Labels: []string{"type", "tenant"}, Help: "Quota of instances of the tenant, by type (in_use, reserved or limit)", Type: prometheus.GaugeValue},
+	{Name: "quota_key_pairs", Labels: []string{"type", "tenant"}, Help: "Quota of key pairs of the tenant, by type (in_use, reserved or limit)", Type: prometheus.GaugeValue},
+	{Name: "quota_metadata_items", Labels: []string{"type", "tenant"}, Help: "Quota of metadata items of the tenant, by type (in_use, reserved or limit)", Type: prometheus.GaugeValue},
+	{Name: "quota_ram", Labels: []string{"type", "tenant"}, Help: "Quota of RAM of the tenant in MB, by type (in_use, reserved or limit)", Type: prometheus.GaugeValue, Unit: "megabytes"},
+	{Name: "quota_server_groups", Labels: []string{"type", "tenant"}, Help: "Quota of server groups of the tenant, by type (in_use, reserved or limit)", Type: prometheus.GaugeValue},
+	{Name: "quota_server_group_members", Labels: []string{"type", "tenant"}, Help: "Quota of server group members of the tenant, by type (in_use, reserved or limit)", Type: prometheus.GaugeValue},
+	{Name: "quota_fixed_ips", Labels: []string{"type", "tenant"}, Help: "Quota of fixed IPs of the tenant, by type (in_use, reserved or limit)", Type: prometheus.GaugeValue},
+	{Name: "quota_floating_ips", Labels: []string{"type", "tenant"}, Help: "Quota of floating IPs of the tenant, by type (in_use, reserved or limit)", Type: prometheus.GaugeValue},
+	{Name: "quota_security_group_rules", Labels: []string{"type", "tenant"}, Help: "Quota of security group rules of the tenant, by type (in_use, reserved or limit)", Type: prometheus.GaugeValue},
+	{Name: "quota_security_groups", Labels: []string{"type", "tenant"}, Help: "Quota of security groups of the tenant, by type (in_use, reserved or limit)", Type: prometheus.GaugeValue},
+	{Name: "quota_injected_file_content_bytes", Labels: []string{"type", "tenant"}, Help: "Quota of injected file content bytes of the tenant, by type (in_use, reserved or limit)", Type: prometheus.GaugeValue, Unit: "bytes"},
+	{Name: "quota_injected_file_path_bytes", Labels: []string{"type", "tenant"}, Help: "Quota of injected file path bytes of the tenant, by type (in_use, reserved or limit)", Type: prometheus.GaugeValue, Unit: "bytes"},
+	{Name: "quota_injected_files", Labels: []string{"type", "tenant"}, Help: "Quota of injected files of the tenant, by type (in_use, reserved or limit)", Type: prometheus.GaugeValue},
 }
 
-func NewNovaExporter(config *ExporterConfig, logger *slog.Logger) (*NovaExporter, error) {
+func NewNovaExporter(ctx context.Context, config *ExporterConfig, logger *slog.Logger) (*NovaExporter, error) {
 	exporter := NovaExporter{
 		BaseOpenStackExporter{
 			Name:           "nova",
@@ -128,13 +129,17 @@ func NewNovaExporter(config *ExporterConfig, logger *slog.Logger) (*NovaExporter
 	}
 	for _, metric := range defaultNovaMetrics {
 		if metric.Name == "server_status" {
-			metric.Labels = append(defaultNovaServerStatusLabels, config.NovaMetadataMapping.Labels...)
+			labels, err := exporter.mappedLabels("nova.server", metric.Labels)
+			if err != nil {
+				return nil, err
+			}
+			metric.Labels = labels
 		}
 		if exporter.isDeprecatedMetric(&metric) {
 			continue
 		}
 		if !exporter.isSlowMetric(&metric) {
-			exporter.AddMetric(metric.Name, metric.Fn, metric.Labels, metric.DeprecatedVersion, nil)
+			exporter.addMetric(metric, nil)
 		}
 	}
 
@@ -143,7 +148,7 @@ func NewNovaExporter(config *ExporterConfig, logger *slog.Logger) (*NovaExporter
 		exporter.Client.Microversion = envMicroversion
 	} else {
 
-		microversion, err := apiversions.Get(config.Client, "v2.1").Extract()
+		microversion, err := apiversions.Get(serviceClientWithContext(ctx, config.Client), "v2.1").Extract()
 		if err == nil {
 			exporter.Client.Microversion = microversion.Version
 		}
@@ -152,7 +157,7 @@ func NewNovaExporter(config *ExporterConfig, logger *slog.Logger) (*NovaExporter
 	return &exporter, nil
 }
 
-func ListNovaAgentState(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListNovaAgentState(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allServices []services.Service
 
 	allPagesServices, err := services.List(exporter.Client, services.ListOpts{}).AllPages()
@@ -170,13 +175,13 @@ func ListNovaAgentState(exporter *BaseOpenStackExporter, ch chan<- prometheus.Me
 			state = 1
 		}
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["agent_state"].Metric,
-			prometheus.CounterValue, float64(state), service.ID, service.Host, service.Binary, service.Status, service.Zone, service.DisabledReason)
+			exporter.Metrics["agent_state"].Type, float64(state), service.ID, service.Host, service.Binary, service.Status, service.Zone, service.DisabledReason)
 	}
 
 	return nil
 }
 
-func ListHypervisors(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListHypervisors(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allHypervisors []hypervisors.Hypervisor
 	var allAggregates []aggregates.Aggregate
 
@@ -220,10 +225,10 @@ func ListHypervisors(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metri
 			availabilityZone = val
 		}
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["running_vms"].Metric,
-			prometheus.GaugeValue, float64(hypervisor.RunningVMs), hypervisor.HypervisorHostname, availabilityZone, aggregatesLabel(hypervisor.Service.Host, hostToAggrMap))
+			exporter.Metrics["running_vms"].Type, float64(hypervisor.RunningVMs), hypervisor.HypervisorHostname, availabilityZone, aggregatesLabel(hypervisor.Service.Host, hostToAggrMap))
 
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["current_workload"].Metric,
-			prometheus.GaugeValue, float64(hypervisor.CurrentWorkload), hypervisor.HypervisorHostname, availabilityZone, aggregatesLabel(hypervisor.Service.Host, hostToAggrMap))
+			exporter.Metrics["current_workload"].Type, float64(hypervisor.CurrentWorkload), hypervisor.HypervisorHostname, availabilityZone, aggregatesLabel(hypervisor.Service.Host, hostToAggrMap))
 
 		var vcpus int
 		if !reflect.ValueOf(hypervisor.CPUInfo).IsZero() {
@@ -232,55 +237,48 @@ func ListHypervisors(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metri
 			vcpus = hypervisor.VCPUs
 		}
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["vcpus_available"].Metric,
-			prometheus.GaugeValue, float64(vcpus), hypervisor.HypervisorHostname, availabilityZone, aggregatesLabel(hypervisor.Service.Host, hostToAggrMap))
+			exporter.Metrics["vcpus_available"].Type, float64(vcpus), hypervisor.HypervisorHostname, availabilityZone, aggregatesLabel(hypervisor.Service.Host, hostToAggrMap))
 
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["vcpus_used"].Metric,
-			prometheus.GaugeValue, float64(hypervisor.VCPUsUsed), hypervisor.HypervisorHostname, availabilityZone, aggregatesLabel(hypervisor.Service.Host, hostToAggrMap))
+			exporter.Metrics["vcpus_used"].Type, float64(hypervisor.VCPUsUsed), hypervisor.HypervisorHostname, availabilityZone, aggregatesLabel(hypervisor.Service.Host, hostToAggrMap))
 
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["memory_available_bytes"].Metric,
-			prometheus.GaugeValue, float64(hypervisor.MemoryMB*MEGABYTE), hypervisor.HypervisorHostname, availabilityZone, aggregatesLabel(hypervisor.Service.Host, hostToAggrMap))
+			exporter.Metrics["memory_available_bytes"].Type, float64(hypervisor.MemoryMB*MEGABYTE), hypervisor.HypervisorHostname, availabilityZone, aggregatesLabel(hypervisor.Service.Host, hostToAggrMap))
 
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["memory_used_bytes"].Metric,
-			prometheus.GaugeValue, float64(hypervisor.MemoryMBUsed*MEGABYTE), hypervisor.HypervisorHostname, availabilityZone, aggregatesLabel(hypervisor.Service.Host, hostToAggrMap))
+			exporter.Metrics["memory_used_bytes"].Type, float64(hypervisor.MemoryMBUsed*MEGABYTE), hypervisor.HypervisorHostname, availabilityZone, aggregatesLabel(hypervisor.Service.Host, hostToAggrMap))
 
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["local_storage_available_bytes"].Metric,
-			prometheus.GaugeValue, float64(hypervisor.LocalGB*GIGABYTE), hypervisor.HypervisorHostname, availabilityZone, aggregatesLabel(hypervisor.Service.Host, hostToAggrMap))
+			exporter.Metrics["local_storage_available_bytes"].Type, float64(hypervisor.LocalGB*GIGABYTE), hypervisor.HypervisorHostname, availabilityZone, aggregatesLabel(hypervisor.Service.Host, hostToAggrMap))
 
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["local_storage_used_bytes"].Metric,
-			prometheus.GaugeValue, float64(hypervisor.LocalGBUsed*GIGABYTE), hypervisor.HypervisorHostname, availabilityZone, aggregatesLabel(hypervisor.Service.Host, hostToAggrMap))
+			exporter.Metrics["local_storage_used_bytes"].Type, float64(hypervisor.LocalGBUsed*GIGABYTE), hypervisor.HypervisorHostname, availabilityZone, aggregatesLabel(hypervisor.Service.Host, hostToAggrMap))
 
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["free_disk_bytes"].Metric,
-			prometheus.GaugeValue, float64(hypervisor.FreeDiskGB*GIGABYTE), hypervisor.HypervisorHostname, availabilityZone, aggregatesLabel(hypervisor.Service.Host, hostToAggrMap))
+			exporter.Metrics["free_disk_bytes"].Type, float64(hypervisor.FreeDiskGB*GIGABYTE), hypervisor.HypervisorHostname, availabilityZone, aggregatesLabel(hypervisor.Service.Host, hostToAggrMap))
 
 	}
 
 	return nil
 }
 
-func ListFlavors(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
-	var allFlavors []flavors.Flavor
-
-	allPagesFlavors, err := flavors.ListDetail(exporter.Client, flavors.ListOpts{AccessType: "None"}).AllPages()
-	if err != nil {
-		return err
-	}
-
-	allFlavors, err = flavors.ExtractFlavors(allPagesFlavors)
+func ListFlavors(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+	allFlavors, err := listFlavors(ctx, exporter.Client)
 	if err != nil {
 		return err
 	}
 
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["flavors"].Metric,
-		prometheus.GaugeValue, float64(len(allFlavors)))
+		exporter.Metrics["flavors"].Type, float64(len(allFlavors)))
 	for _, f := range allFlavors {
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["flavor"].Metric,
-			prometheus.GaugeValue, 1, f.ID, f.Name, fmt.Sprintf("%v", f.VCPUs), fmt.Sprintf("%v", f.RAM), fmt.Sprintf("%v", f.Disk), fmt.Sprintf("%v", f.IsPublic))
+			exporter.Metrics["flavor"].Type, 1, f.ID, f.Name, fmt.Sprintf("%v", f.VCPUs), fmt.Sprintf("%v", f.RAM), fmt.Sprintf("%v", f.Disk), fmt.Sprintf("%v", f.IsPublic))
 	}
 
 	return nil
 }
 
-func ListQuotas(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListQuotas(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allProjects []projects.Project
 	var eo gophercloud.EndpointOpts
 
@@ -300,12 +298,7 @@ func ListQuotas(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) er
 		return err
 	}
 
-	allPagesProject, err := projects.List(c, projects.ListOpts{DomainID: exporter.DomainID}).AllPages()
-	if err != nil {
-		return err
-	}
-
-	allProjects, err = projects.ExtractProjects(allPagesProject)
+	allProjects, err = listProjects(ctx, c, exporter.DomainID)
 	if err != nil {
 		return err
 	}
@@ -317,94 +310,94 @@ func ListQuotas(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) er
 		}
 
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_cores"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.Cores.InUse), "in_use", p.Name)
+			exporter.Metrics["quota_cores"].Type, float64(quotaSet.Cores.InUse), "in_use", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_cores"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.Cores.Reserved), "reserved", p.Name)
+			exporter.Metrics["quota_cores"].Type, float64(quotaSet.Cores.Reserved), "reserved", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_cores"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.Cores.Limit), "limit", p.Name)
+			exporter.Metrics["quota_cores"].Type, float64(quotaSet.Cores.Limit), "limit", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_instances"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.Instances.InUse), "in_use", p.Name)
+			exporter.Metrics["quota_instances"].Type, float64(quotaSet.Instances.InUse), "in_use", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_instances"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.Instances.Reserved), "reserved", p.Name)
+			exporter.Metrics["quota_instances"].Type, float64(quotaSet.Instances.Reserved), "reserved", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_instances"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.Instances.Limit), "limit", p.Name)
+			exporter.Metrics["quota_instances"].Type, float64(quotaSet.Instances.Limit), "limit", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_key_pairs"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.KeyPairs.InUse), "in_use", p.Name)
+			exporter.Metrics["quota_key_pairs"].Type, float64(quotaSet.KeyPairs.InUse), "in_use", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_key_pairs"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.KeyPairs.Reserved), "reserved", p.Name)
+			exporter.Metrics["quota_key_pairs"].Type, float64(quotaSet.KeyPairs.Reserved), "reserved", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_key_pairs"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.KeyPairs.Limit), "limit", p.Name)
+			exporter.Metrics["quota_key_pairs"].Type, float64(quotaSet.KeyPairs.Limit), "limit", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_metadata_items"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.MetadataItems.InUse), "in_use", p.Name)
+			exporter.Metrics["quota_metadata_items"].Type, float64(quotaSet.MetadataItems.InUse), "in_use", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_metadata_items"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.MetadataItems.Reserved), "reserved", p.Name)
+			exporter.Metrics["quota_metadata_items"].Type, float64(quotaSet.MetadataItems.Reserved), "reserved", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_metadata_items"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.MetadataItems.Limit), "limit", p.Name)
+			exporter.Metrics["quota_metadata_items"].Type, float64(quotaSet.MetadataItems.Limit), "limit", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_ram"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.RAM.InUse), "in_use", p.Name)
+			exporter.Metrics["quota_ram"].Type, float64(quotaSet.RAM.InUse), "in_use", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_ram"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.RAM.Reserved), "reserved", p.Name)
+			exporter.Metrics["quota_ram"].Type, float64(quotaSet.RAM.Reserved), "reserved", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_ram"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.RAM.Limit), "limit", p.Name)
+			exporter.Metrics["quota_ram"].Type, float64(quotaSet.RAM.Limit), "limit", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_server_groups"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.ServerGroups.InUse), "in_use", p.Name)
+			exporter.Metrics["quota_server_groups"].Type, float64(quotaSet.ServerGroups.InUse), "in_use", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_server_groups"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.ServerGroups.Reserved), "reserved", p.Name)
+			exporter.Metrics["quota_server_groups"].Type, float64(quotaSet.ServerGroups.Reserved), "reserved", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_server_groups"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.ServerGroups.Limit), "limit", p.Name)
+			exporter.Metrics["quota_server_groups"].Type, float64(quotaSet.ServerGroups.Limit), "limit", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_server_group_members"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.ServerGroupMembers.InUse), "in_use", p.Name)
+			exporter.Metrics["quota_server_group_members"].Type, float64(quotaSet.ServerGroupMembers.InUse), "in_use", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_server_group_members"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.ServerGroupMembers.Reserved), "reserved", p.Name)
+			exporter.Metrics["quota_server_group_members"].Type, float64(quotaSet.ServerGroupMembers.Reserved), "reserved", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_server_group_members"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.ServerGroupMembers.Limit), "limit", p.Name)
+			exporter.Metrics["quota_server_group_members"].Type, float64(quotaSet.ServerGroupMembers.Limit), "limit", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_fixed_ips"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.FixedIPs.InUse), "in_use", p.Name)
+			exporter.Metrics["quota_fixed_ips"].Type, float64(quotaSet.FixedIPs.InUse), "in_use", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_fixed_ips"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.FixedIPs.Reserved), "reserved", p.Name)
+			exporter.Metrics["quota_fixed_ips"].Type, float64(quotaSet.FixedIPs.Reserved), "reserved", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_fixed_ips"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.FixedIPs.Limit), "limit", p.Name)
+			exporter.Metrics["quota_fixed_ips"].Type, float64(quotaSet.FixedIPs.Limit), "limit", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_floating_ips"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.FloatingIPs.InUse), "in_use", p.Name)
+			exporter.Metrics["quota_floating_ips"].Type, float64(quotaSet.FloatingIPs.InUse), "in_use", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_floating_ips"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.FloatingIPs.Reserved), "reserved", p.Name)
+			exporter.Metrics["quota_floating_ips"].Type, float64(quotaSet.FloatingIPs.Reserved), "reserved", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_floating_ips"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.FloatingIPs.Limit), "limit", p.Name)
+			exporter.Metrics["quota_floating_ips"].Type, float64(quotaSet.FloatingIPs.Limit), "limit", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_security_group_rules"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.SecurityGroupRules.InUse), "in_use", p.Name)
+			exporter.Metrics["quota_security_group_rules"].Type, float64(quotaSet.SecurityGroupRules.InUse), "in_use", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_security_group_rules"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.SecurityGroupRules.Reserved), "reserved", p.Name)
+			exporter.Metrics["quota_security_group_rules"].Type, float64(quotaSet.SecurityGroupRules.Reserved), "reserved", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_security_group_rules"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.SecurityGroupRules.Limit), "limit", p.Name)
+			exporter.Metrics["quota_security_group_rules"].Type, float64(quotaSet.SecurityGroupRules.Limit), "limit", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_security_groups"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.ServerGroups.InUse), "in_use", p.Name)
+			exporter.Metrics["quota_security_groups"].Type, float64(quotaSet.ServerGroups.InUse), "in_use", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_security_groups"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.ServerGroups.Reserved), "reserved", p.Name)
+			exporter.Metrics["quota_security_groups"].Type, float64(quotaSet.ServerGroups.Reserved), "reserved", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_security_groups"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.ServerGroups.Limit), "limit", p.Name)
+			exporter.Metrics["quota_security_groups"].Type, float64(quotaSet.ServerGroups.Limit), "limit", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_injected_file_content_bytes"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.InjectedFileContentBytes.InUse), "in_use", p.Name)
+			exporter.Metrics["quota_injected_file_content_bytes"].Type, float64(quotaSet.InjectedFileContentBytes.InUse), "in_use", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_injected_file_content_bytes"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.InjectedFileContentBytes.Reserved), "reserved", p.Name)
+			exporter.Metrics["quota_injected_file_content_bytes"].Type, float64(quotaSet.InjectedFileContentBytes.Reserved), "reserved", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_injected_file_content_bytes"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.InjectedFileContentBytes.Limit), "limit", p.Name)
+			exporter.Metrics["quota_injected_file_content_bytes"].Type, float64(quotaSet.InjectedFileContentBytes.Limit), "limit", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_injected_file_path_bytes"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.InjectedFilePathBytes.InUse), "in_use", p.Name)
+			exporter.Metrics["quota_injected_file_path_bytes"].Type, float64(quotaSet.InjectedFilePathBytes.InUse), "in_use", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_injected_file_path_bytes"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.InjectedFilePathBytes.Reserved), "reserved", p.Name)
+			exporter.Metrics["quota_injected_file_path_bytes"].Type, float64(quotaSet.InjectedFilePathBytes.Reserved), "reserved", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_injected_file_path_bytes"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.InjectedFilePathBytes.Limit), "limit", p.Name)
+			exporter.Metrics["quota_injected_file_path_bytes"].Type, float64(quotaSet.InjectedFilePathBytes.Limit), "limit", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_injected_files"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.InjectedFiles.InUse), "in_use", p.Name)
+			exporter.Metrics["quota_injected_files"].Type, float64(quotaSet.InjectedFiles.InUse), "in_use", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_injected_files"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.InjectedFiles.Reserved), "reserved", p.Name)
+			exporter.Metrics["quota_injected_files"].Type, float64(quotaSet.InjectedFiles.Reserved), "reserved", p.Name)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["quota_injected_files"].Metric,
-			prometheus.GaugeValue, float64(quotaSet.InjectedFiles.Limit), "limit", p.Name)
+			exporter.Metrics["quota_injected_files"].Type, float64(quotaSet.InjectedFiles.Limit), "limit", p.Name)
 	}
 	return nil
 }
 
-func ListAZs(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListAZs(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allAZs []availabilityzones.AvailabilityZone
 
 	allPagesAZs, err := availabilityzones.List(exporter.Client).AllPages()
@@ -417,12 +410,12 @@ func ListAZs(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error
 	}
 
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["availability_zones"].Metric,
-		prometheus.GaugeValue, float64(len(allAZs)))
+		exporter.Metrics["availability_zones"].Type, float64(len(allAZs)))
 
 	return nil
 }
 
-func ListComputeSecGroups(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListComputeSecGroups(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allSecurityGroups []secgroups.SecurityGroup
 
 	allPagesSecurityGroups, err := secgroups.List(exporter.Client).AllPages()
@@ -435,12 +428,12 @@ func ListComputeSecGroups(exporter *BaseOpenStackExporter, ch chan<- prometheus.
 	}
 
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["security_groups"].Metric,
-		prometheus.GaugeValue, float64(len(allSecurityGroups)))
+		exporter.Metrics["security_groups"].Type, float64(len(allSecurityGroups)))
 
 	return nil
 }
 
-func ListAllServers(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListAllServers(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	type ServerWithExt struct {
 		servers.Server
 		availabilityzones.ServerAvailabilityZoneExt
@@ -457,7 +450,7 @@ func ListAllServers(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric
 		serverListOption = servers.ListOpts{TenantID: exporter.TenantID}
 
 	}
-	allPagesServers, err := servers.List(exporter.Client, serverListOption).AllPages()
+	allPagesServers, err := listServers(ctx, exporter.Client, serverListOption)
 
 	if err != nil {
 		return err
@@ -469,7 +462,7 @@ func ListAllServers(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric
 	}
 
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["total_vms"].Metric,
-		prometheus.GaugeValue, float64(len(allServers)))
+		exporter.Metrics["total_vms"].Type, float64(len(allServers)))
 	apiMv, _ := strconv.ParseFloat(exporter.Client.Microversion, 64)
 	if apiMv >= 2.46 {
 		// https://docs.openstack.org/api-ref/compute/#list-servers-detailed
@@ -477,11 +470,7 @@ func ListAllServers(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric
 		// If micro-version is greater than 2.46,
 		// we need to retrieve all flavors once again and search for flavor_id by name,
 		// as flavor_id are only available in server's detail data up to that version.
-		allPagesFlavors, err := flavors.ListDetail(exporter.Client, flavors.ListOpts{AccessType: "None"}).AllPages()
-		if err != nil {
-			return err
-		}
-		allFlavors, err = flavors.ExtractFlavors(allPagesFlavors)
+		allFlavors, err = listFlavors(ctx, exporter.Client)
 		if err != nil {
 			return err
 		}
@@ -503,16 +492,16 @@ func ListAllServers(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric
 					server.AvailabilityZone, searchFlavorIDbyName(server.Flavor["original_name"], allFlavors), server.InstanceName,
 				}
 			}()
-			metadataValues := exporter.NovaMetadataMapping.Extract(server.Metadata)
+			metadataValues := exporter.LabelMappings.Get("nova.server").Extract(server.Metadata)
 
-			ch <- prometheus.MustNewConstMetric(exporter.Metrics["server_status"].Metric,
-				prometheus.GaugeValue, float64(mapServerStatus(server.Status)), append(labelValues, metadataValues...)...)
+			exporter.SendStatus(ch, "server_status", server_status, mapServerStatus(server.Status), server.Status,
+				append(labelValues, metadataValues...)...)
 		}
 	}
 	return nil
 }
 
-func ListComputeLimits(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListComputeLimits(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allProjects []projects.Project
 	var eo gophercloud.EndpointOpts
 
@@ -532,12 +521,7 @@ func ListComputeLimits(exporter *BaseOpenStackExporter, ch chan<- prometheus.Met
 		return err
 	}
 
-	allPagesProject, err := projects.List(c, projects.ListOpts{DomainID: exporter.DomainID}).AllPages()
-	if err != nil {
-		return err
-	}
-
-	allProjects, err = projects.ExtractProjects(allPagesProject)
+	allProjects, err = listProjects(ctx, c, exporter.DomainID)
 	if err != nil {
 		return err
 	}
@@ -550,29 +534,29 @@ func ListComputeLimits(exporter *BaseOpenStackExporter, ch chan<- prometheus.Met
 		}
 
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["limits_vcpus_max"].Metric,
-			prometheus.GaugeValue, float64(limits.Absolute.MaxTotalCores), p.Name, p.ID)
+			exporter.Metrics["limits_vcpus_max"].Type, float64(limits.Absolute.MaxTotalCores), p.Name, p.ID)
 
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["limits_vcpus_used"].Metric,
-			prometheus.GaugeValue, float64(limits.Absolute.TotalCoresUsed), p.Name, p.ID)
+			exporter.Metrics["limits_vcpus_used"].Type, float64(limits.Absolute.TotalCoresUsed), p.Name, p.ID)
 
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["limits_memory_max"].Metric,
-			prometheus.GaugeValue, float64(limits.Absolute.MaxTotalRAMSize), p.Name, p.ID)
+			exporter.Metrics["limits_memory_max"].Type, float64(limits.Absolute.MaxTotalRAMSize), p.Name, p.ID)
 
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["limits_memory_used"].Metric,
-			prometheus.GaugeValue, float64(limits.Absolute.TotalRAMUsed), p.Name, p.ID)
+			exporter.Metrics["limits_memory_used"].Type, float64(limits.Absolute.TotalRAMUsed), p.Name, p.ID)
 
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["limits_instances_used"].Metric,
-			prometheus.GaugeValue, float64(limits.Absolute.TotalInstancesUsed), p.Name, p.ID)
+			exporter.Metrics["limits_instances_used"].Type, float64(limits.Absolute.TotalInstancesUsed), p.Name, p.ID)
 
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["limits_instances_max"].Metric,
-			prometheus.GaugeValue, float64(limits.Absolute.MaxTotalInstances), p.Name, p.ID)
+			exporter.Metrics["limits_instances_max"].Type, float64(limits.Absolute.MaxTotalInstances), p.Name, p.ID)
 	}
 
 	return nil
 }
 
 // ListUsage add metrics about usage
-func ListUsage(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListUsage(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	allPagesUsage, err := usage.AllTenants(exporter.Client, usage.AllTenantsOpts{Detailed: true}).AllPages()
 	if err != nil {
 		return err
@@ -587,7 +571,7 @@ func ListUsage(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) err
 	for _, tenant := range allTenantsUsage {
 		for _, server := range tenant.ServerUsages {
 			ch <- prometheus.MustNewConstMetric(exporter.Metrics["server_local_gb"].Metric,
-				prometheus.GaugeValue, float64(server.LocalGB), server.Name, server.InstanceID, tenant.TenantID)
+				exporter.Metrics["server_local_gb"].Type, float64(server.LocalGB), server.Name, server.InstanceID, tenant.TenantID)
 		}
 
 	}
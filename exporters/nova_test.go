@@ -12,19 +12,30 @@ type NovaTestSuite struct {
 }
 
 var novaExpectedUp = `
-# HELP openstack_nova_agent_state agent_state
-# TYPE openstack_nova_agent_state counter
+# HELP openstack_collector_success Whether the last collection of the metric from OpenStack API succeeded
+# TYPE openstack_collector_success gauge
+openstack_collector_success{metric="agent_state",service="nova"} 1
+openstack_collector_success{metric="availability_zones",service="nova"} 1
+openstack_collector_success{metric="flavors",service="nova"} 1
+openstack_collector_success{metric="limits_vcpus_max",service="nova"} 1
+openstack_collector_success{metric="quota_cores",service="nova"} 1
+openstack_collector_success{metric="running_vms",service="nova"} 1
+openstack_collector_success{metric="security_groups",service="nova"} 1
+openstack_collector_success{metric="server_local_gb",service="nova"} 1
+openstack_collector_success{metric="total_vms",service="nova"} 1
+# HELP openstack_nova_agent_state Agent state (1=up, 0=down)
+# TYPE openstack_nova_agent_state gauge
 openstack_nova_agent_state{adminState="disabled",disabledReason="test1",hostname="host1",id="1",service="nova-scheduler",zone="internal"} 1
 openstack_nova_agent_state{adminState="disabled",disabledReason="test2",hostname="host1",id="2",service="nova-compute",zone="nova"} 1
 openstack_nova_agent_state{adminState="disabled",disabledReason="test4",hostname="host2",id="4",service="nova-compute",zone="nova"} 0
 openstack_nova_agent_state{adminState="enabled",disabledReason="",hostname="host2",id="3",service="nova-scheduler",zone="internal"} 0
-# HELP openstack_nova_availability_zones availability_zones
+# HELP openstack_nova_availability_zones Total number of availability zones
 # TYPE openstack_nova_availability_zones gauge
 openstack_nova_availability_zones 1
-# HELP openstack_nova_current_workload current_workload
+# HELP openstack_nova_current_workload Current workload of the hypervisor
 # TYPE openstack_nova_current_workload gauge
 openstack_nova_current_workload{aggregates="",availability_zone="",hostname="host1"} 0
-# HELP openstack_nova_flavor flavor
+# HELP openstack_nova_flavor Flavor information
 # TYPE openstack_nova_flavor gauge
 openstack_nova_flavor{disk="0",id="1",is_public="true",name="m1.tiny",ram="512",vcpus="1"} 1
 openstack_nova_flavor{disk="0",id="2",is_public="true",name="m1.small",ram="2048",vcpus="1"} 1
@@ -34,13 +45,13 @@ openstack_nova_flavor{disk="0",id="5",is_public="true",name="m1.xlarge",ram="163
 openstack_nova_flavor{disk="0",id="6",is_public="true",name="m1.tiny.specs",ram="512",vcpus="1"} 1
 openstack_nova_flavor{disk="0",id="7",is_public="true",name="m1.small.description",ram="2048",vcpus="1"} 1
 openstack_nova_flavor{disk="0",id="8",is_public="false",name="m1.tiny.private",ram="512",vcpus="1"} 1
-# HELP openstack_nova_flavors flavors
+# HELP openstack_nova_flavors Total number of flavors
 # TYPE openstack_nova_flavors gauge
 openstack_nova_flavors 8
-# HELP openstack_nova_free_disk_bytes free_disk_bytes
+# HELP openstack_nova_free_disk_bytes Free disk space of the hypervisor in bytes
 # TYPE openstack_nova_free_disk_bytes gauge
 openstack_nova_free_disk_bytes{aggregates="",availability_zone="",hostname="host1"} 1.103806595072e+12
-# HELP openstack_nova_limits_instances_max limits_instances_max
+# HELP openstack_nova_limits_instances_max Maximum instances limit of the tenant
 # TYPE openstack_nova_limits_instances_max gauge
 openstack_nova_limits_instances_max{tenant="admin",tenant_id="0c4e939acacf4376bdcd1129f1a054ad"} 10
 openstack_nova_limits_instances_max{tenant="alt_demo",tenant_id="fdb8424c4e4f4c0ba32c52e2de3bd80e"} 10
@@ -50,7 +61,7 @@ openstack_nova_limits_instances_max{tenant="service",tenant_id="3d594eb0f0474106
 openstack_nova_limits_instances_max{tenant="swifttenanttest1",tenant_id="43ebde53fc314b1c9ea2b8c5dc744927"} 10
 openstack_nova_limits_instances_max{tenant="swifttenanttest2",tenant_id="2db68fed84324f29bb73130c6c2094fb"} 10
 openstack_nova_limits_instances_max{tenant="swifttenanttest4",tenant_id="4b1eb781a47440acb8af9850103e537f"} 10
-# HELP openstack_nova_limits_instances_used limits_instances_used
+# HELP openstack_nova_limits_instances_used Used instances of the tenant
 # TYPE openstack_nova_limits_instances_used gauge
 openstack_nova_limits_instances_used{tenant="admin",tenant_id="0c4e939acacf4376bdcd1129f1a054ad"} 0
 openstack_nova_limits_instances_used{tenant="alt_demo",tenant_id="fdb8424c4e4f4c0ba32c52e2de3bd80e"} 0
@@ -60,7 +71,7 @@ openstack_nova_limits_instances_used{tenant="service",tenant_id="3d594eb0f047410
 openstack_nova_limits_instances_used{tenant="swifttenanttest1",tenant_id="43ebde53fc314b1c9ea2b8c5dc744927"} 0
 openstack_nova_limits_instances_used{tenant="swifttenanttest2",tenant_id="2db68fed84324f29bb73130c6c2094fb"} 0
 openstack_nova_limits_instances_used{tenant="swifttenanttest4",tenant_id="4b1eb781a47440acb8af9850103e537f"} 0
-# HELP openstack_nova_limits_memory_max limits_memory_max
+# HELP openstack_nova_limits_memory_max Maximum memory limit of the tenant in MB
 # TYPE openstack_nova_limits_memory_max gauge
 openstack_nova_limits_memory_max{tenant="admin",tenant_id="0c4e939acacf4376bdcd1129f1a054ad"} 51200
 openstack_nova_limits_memory_max{tenant="alt_demo",tenant_id="fdb8424c4e4f4c0ba32c52e2de3bd80e"} 51200
@@ -70,7 +81,7 @@ openstack_nova_limits_memory_max{tenant="service",tenant_id="3d594eb0f04741069db
 openstack_nova_limits_memory_max{tenant="swifttenanttest1",tenant_id="43ebde53fc314b1c9ea2b8c5dc744927"} 51200
 openstack_nova_limits_memory_max{tenant="swifttenanttest2",tenant_id="2db68fed84324f29bb73130c6c2094fb"} 51200
 openstack_nova_limits_memory_max{tenant="swifttenanttest4",tenant_id="4b1eb781a47440acb8af9850103e537f"} 51200
-# HELP openstack_nova_limits_memory_used limits_memory_used
+# HELP openstack_nova_limits_memory_used Used memory of the tenant in MB
 # TYPE openstack_nova_limits_memory_used gauge
 openstack_nova_limits_memory_used{tenant="admin",tenant_id="0c4e939acacf4376bdcd1129f1a054ad"} 0
 openstack_nova_limits_memory_used{tenant="alt_demo",tenant_id="fdb8424c4e4f4c0ba32c52e2de3bd80e"} 0
@@ -80,7 +91,7 @@ openstack_nova_limits_memory_used{tenant="service",tenant_id="3d594eb0f04741069d
 openstack_nova_limits_memory_used{tenant="swifttenanttest1",tenant_id="43ebde53fc314b1c9ea2b8c5dc744927"} 0
 openstack_nova_limits_memory_used{tenant="swifttenanttest2",tenant_id="2db68fed84324f29bb73130c6c2094fb"} 0
 openstack_nova_limits_memory_used{tenant="swifttenanttest4",tenant_id="4b1eb781a47440acb8af9850103e537f"} 0
-# HELP openstack_nova_limits_vcpus_max limits_vcpus_max
+# HELP openstack_nova_limits_vcpus_max Maximum vCPUs limit of the tenant
 # TYPE openstack_nova_limits_vcpus_max gauge
 openstack_nova_limits_vcpus_max{tenant="admin",tenant_id="0c4e939acacf4376bdcd1129f1a054ad"} 20
 openstack_nova_limits_vcpus_max{tenant="alt_demo",tenant_id="fdb8424c4e4f4c0ba32c52e2de3bd80e"} 20
@@ -90,7 +101,7 @@ openstack_nova_limits_vcpus_max{tenant="service",tenant_id="3d594eb0f04741069dbb
 openstack_nova_limits_vcpus_max{tenant="swifttenanttest1",tenant_id="43ebde53fc314b1c9ea2b8c5dc744927"} 20
 openstack_nova_limits_vcpus_max{tenant="swifttenanttest2",tenant_id="2db68fed84324f29bb73130c6c2094fb"} 20
 openstack_nova_limits_vcpus_max{tenant="swifttenanttest4",tenant_id="4b1eb781a47440acb8af9850103e537f"} 20
-# HELP openstack_nova_limits_vcpus_used limits_vcpus_used
+# HELP openstack_nova_limits_vcpus_used Used vCPUs of the tenant
 # TYPE openstack_nova_limits_vcpus_used gauge
 openstack_nova_limits_vcpus_used{tenant="admin",tenant_id="0c4e939acacf4376bdcd1129f1a054ad"} 0
 openstack_nova_limits_vcpus_used{tenant="alt_demo",tenant_id="fdb8424c4e4f4c0ba32c52e2de3bd80e"} 0
@@ -100,19 +111,19 @@ openstack_nova_limits_vcpus_used{tenant="service",tenant_id="3d594eb0f04741069db
 openstack_nova_limits_vcpus_used{tenant="swifttenanttest1",tenant_id="43ebde53fc314b1c9ea2b8c5dc744927"} 0
 openstack_nova_limits_vcpus_used{tenant="swifttenanttest2",tenant_id="2db68fed84324f29bb73130c6c2094fb"} 0
 openstack_nova_limits_vcpus_used{tenant="swifttenanttest4",tenant_id="4b1eb781a47440acb8af9850103e537f"} 0
-# HELP openstack_nova_local_storage_available_bytes local_storage_available_bytes
+# HELP openstack_nova_local_storage_available_bytes Available local storage of the hypervisor in bytes
 # TYPE openstack_nova_local_storage_available_bytes gauge
 openstack_nova_local_storage_available_bytes{aggregates="",availability_zone="",hostname="host1"} 1.103806595072e+12
-# HELP openstack_nova_local_storage_used_bytes local_storage_used_bytes
+# HELP openstack_nova_local_storage_used_bytes Used local storage of the hypervisor in bytes
 # TYPE openstack_nova_local_storage_used_bytes gauge
 openstack_nova_local_storage_used_bytes{aggregates="",availability_zone="",hostname="host1"} 0
-# HELP openstack_nova_memory_available_bytes memory_available_bytes
+# HELP openstack_nova_memory_available_bytes Available memory of the hypervisor in bytes
 # TYPE openstack_nova_memory_available_bytes gauge
 openstack_nova_memory_available_bytes{aggregates="",availability_zone="",hostname="host1"} 8.589934592e+09
-# HELP openstack_nova_memory_used_bytes memory_used_bytes
+# HELP openstack_nova_memory_used_bytes Used memory of the hypervisor in bytes
 # TYPE openstack_nova_memory_used_bytes gauge
 openstack_nova_memory_used_bytes{aggregates="",availability_zone="",hostname="host1"} 5.36870912e+08
-# HELP openstack_nova_quota_cores quota_cores
+# HELP openstack_nova_quota_cores Quota of cores of the tenant, by type (in_use, reserved or limit)
 # TYPE openstack_nova_quota_cores gauge
 openstack_nova_quota_cores{tenant="admin",type="in_use"} 0
 openstack_nova_quota_cores{tenant="admin",type="limit"} 20
@@ -138,7 +149,7 @@ openstack_nova_quota_cores{tenant="swifttenanttest2",type="reserved"} 0
 openstack_nova_quota_cores{tenant="swifttenanttest4",type="in_use"} 0
 openstack_nova_quota_cores{tenant="swifttenanttest4",type="limit"} 20
 openstack_nova_quota_cores{tenant="swifttenanttest4",type="reserved"} 0
-# HELP openstack_nova_quota_fixed_ips quota_fixed_ips
+# HELP openstack_nova_quota_fixed_ips Quota of fixed IPs of the tenant, by type (in_use, reserved or limit)
 # TYPE openstack_nova_quota_fixed_ips gauge
 openstack_nova_quota_fixed_ips{tenant="admin",type="in_use"} 0
 openstack_nova_quota_fixed_ips{tenant="admin",type="limit"} -1
@@ -164,7 +175,7 @@ openstack_nova_quota_fixed_ips{tenant="swifttenanttest2",type="reserved"} 0
 openstack_nova_quota_fixed_ips{tenant="swifttenanttest4",type="in_use"} 0
 openstack_nova_quota_fixed_ips{tenant="swifttenanttest4",type="limit"} -1
 openstack_nova_quota_fixed_ips{tenant="swifttenanttest4",type="reserved"} 0
-# HELP openstack_nova_quota_floating_ips quota_floating_ips
+# HELP openstack_nova_quota_floating_ips Quota of floating IPs of the tenant, by type (in_use, reserved or limit)
 # TYPE openstack_nova_quota_floating_ips gauge
 openstack_nova_quota_floating_ips{tenant="admin",type="in_use"} 0
 openstack_nova_quota_floating_ips{tenant="admin",type="limit"} -1
@@ -190,7 +201,7 @@ openstack_nova_quota_floating_ips{tenant="swifttenanttest2",type="reserved"} 0
 openstack_nova_quota_floating_ips{tenant="swifttenanttest4",type="in_use"} 0
 openstack_nova_quota_floating_ips{tenant="swifttenanttest4",type="limit"} -1
 openstack_nova_quota_floating_ips{tenant="swifttenanttest4",type="reserved"} 0
-# HELP openstack_nova_quota_injected_file_content_bytes quota_injected_file_content_bytes
+# HELP openstack_nova_quota_injected_file_content_bytes Quota of injected file content bytes of the tenant, by type (in_use, reserved or limit)
 # TYPE openstack_nova_quota_injected_file_content_bytes gauge
 openstack_nova_quota_injected_file_content_bytes{tenant="admin",type="in_use"} 0
 openstack_nova_quota_injected_file_content_bytes{tenant="admin",type="limit"} 10240
@@ -216,7 +227,7 @@ openstack_nova_quota_injected_file_content_bytes{tenant="swifttenanttest2",type=
 openstack_nova_quota_injected_file_content_bytes{tenant="swifttenanttest4",type="in_use"} 0
 openstack_nova_quota_injected_file_content_bytes{tenant="swifttenanttest4",type="limit"} 10240
 openstack_nova_quota_injected_file_content_bytes{tenant="swifttenanttest4",type="reserved"} 0
-# HELP openstack_nova_quota_injected_file_path_bytes quota_injected_file_path_bytes
+# HELP openstack_nova_quota_injected_file_path_bytes Quota of injected file path bytes of the tenant, by type (in_use, reserved or limit)
 # TYPE openstack_nova_quota_injected_file_path_bytes gauge
 openstack_nova_quota_injected_file_path_bytes{tenant="admin",type="in_use"} 0
 openstack_nova_quota_injected_file_path_bytes{tenant="admin",type="limit"} 255
@@ -242,7 +253,7 @@ openstack_nova_quota_injected_file_path_bytes{tenant="swifttenanttest2",type="re
 openstack_nova_quota_injected_file_path_bytes{tenant="swifttenanttest4",type="in_use"} 0
 openstack_nova_quota_injected_file_path_bytes{tenant="swifttenanttest4",type="limit"} 255
 openstack_nova_quota_injected_file_path_bytes{tenant="swifttenanttest4",type="reserved"} 0
-# HELP openstack_nova_quota_injected_files quota_injected_files
+# HELP openstack_nova_quota_injected_files Quota of injected files of the tenant, by type (in_use, reserved or limit)
 # TYPE openstack_nova_quota_injected_files gauge
 openstack_nova_quota_injected_files{tenant="admin",type="in_use"} 0
 openstack_nova_quota_injected_files{tenant="admin",type="limit"} 5
@@ -268,7 +279,7 @@ openstack_nova_quota_injected_files{tenant="swifttenanttest2",type="reserved"} 0
 openstack_nova_quota_injected_files{tenant="swifttenanttest4",type="in_use"} 0
 openstack_nova_quota_injected_files{tenant="swifttenanttest4",type="limit"} 5
 openstack_nova_quota_injected_files{tenant="swifttenanttest4",type="reserved"} 0
-# HELP openstack_nova_quota_instances quota_instances
+# HELP openstack_nova_quota_instances Quota of instances of the tenant, by type (in_use, reserved or limit)
 # TYPE openstack_nova_quota_instances gauge
 openstack_nova_quota_instances{tenant="admin",type="in_use"} 0
 openstack_nova_quota_instances{tenant="admin",type="limit"} 10
@@ -294,7 +305,7 @@ openstack_nova_quota_instances{tenant="swifttenanttest2",type="reserved"} 0
 openstack_nova_quota_instances{tenant="swifttenanttest4",type="in_use"} 0
 openstack_nova_quota_instances{tenant="swifttenanttest4",type="limit"} 10
 openstack_nova_quota_instances{tenant="swifttenanttest4",type="reserved"} 0
-# HELP openstack_nova_quota_key_pairs quota_key_pairs
+# HELP openstack_nova_quota_key_pairs Quota of key pairs of the tenant, by type (in_use, reserved or limit)
 # TYPE openstack_nova_quota_key_pairs gauge
 openstack_nova_quota_key_pairs{tenant="admin",type="in_use"} 0
 openstack_nova_quota_key_pairs{tenant="admin",type="limit"} 100
@@ -320,7 +331,7 @@ openstack_nova_quota_key_pairs{tenant="swifttenanttest2",type="reserved"} 0
 openstack_nova_quota_key_pairs{tenant="swifttenanttest4",type="in_use"} 0
 openstack_nova_quota_key_pairs{tenant="swifttenanttest4",type="limit"} 100
 openstack_nova_quota_key_pairs{tenant="swifttenanttest4",type="reserved"} 0
-# HELP openstack_nova_quota_metadata_items quota_metadata_items
+# HELP openstack_nova_quota_metadata_items Quota of metadata items of the tenant, by type (in_use, reserved or limit)
 # TYPE openstack_nova_quota_metadata_items gauge
 openstack_nova_quota_metadata_items{tenant="admin",type="in_use"} 0
 openstack_nova_quota_metadata_items{tenant="admin",type="limit"} 128
@@ -346,7 +357,7 @@ openstack_nova_quota_metadata_items{tenant="swifttenanttest2",type="reserved"} 0
 openstack_nova_quota_metadata_items{tenant="swifttenanttest4",type="in_use"} 0
 openstack_nova_quota_metadata_items{tenant="swifttenanttest4",type="limit"} 128
 openstack_nova_quota_metadata_items{tenant="swifttenanttest4",type="reserved"} 0
-# HELP openstack_nova_quota_ram quota_ram
+# HELP openstack_nova_quota_ram Quota of RAM of the tenant in MB, by type (in_use, reserved or limit)
 # TYPE openstack_nova_quota_ram gauge
 openstack_nova_quota_ram{tenant="admin",type="in_use"} 0
 openstack_nova_quota_ram{tenant="admin",type="limit"} 51200
@@ -372,7 +383,7 @@ openstack_nova_quota_ram{tenant="swifttenanttest2",type="reserved"} 0
 openstack_nova_quota_ram{tenant="swifttenanttest4",type="in_use"} 0
 openstack_nova_quota_ram{tenant="swifttenanttest4",type="limit"} 51200
 openstack_nova_quota_ram{tenant="swifttenanttest4",type="reserved"} 0
-# HELP openstack_nova_quota_security_group_rules quota_security_group_rules
+# HELP openstack_nova_quota_security_group_rules Quota of security group rules of the tenant, by type (in_use, reserved or limit)
 # TYPE openstack_nova_quota_security_group_rules gauge
 openstack_nova_quota_security_group_rules{tenant="admin",type="in_use"} 0
 openstack_nova_quota_security_group_rules{tenant="admin",type="limit"} -1
@@ -398,7 +409,7 @@ openstack_nova_quota_security_group_rules{tenant="swifttenanttest2",type="reserv
 openstack_nova_quota_security_group_rules{tenant="swifttenanttest4",type="in_use"} 0
 openstack_nova_quota_security_group_rules{tenant="swifttenanttest4",type="limit"} -1
 openstack_nova_quota_security_group_rules{tenant="swifttenanttest4",type="reserved"} 0
-# HELP openstack_nova_quota_security_groups quota_security_groups
+# HELP openstack_nova_quota_security_groups Quota of security groups of the tenant, by type (in_use, reserved or limit)
 # TYPE openstack_nova_quota_security_groups gauge
 openstack_nova_quota_security_groups{tenant="admin",type="in_use"} 0
 openstack_nova_quota_security_groups{tenant="admin",type="limit"} 10
@@ -424,7 +435,7 @@ openstack_nova_quota_security_groups{tenant="swifttenanttest2",type="reserved"}
 openstack_nova_quota_security_groups{tenant="swifttenanttest4",type="in_use"} 0
 openstack_nova_quota_security_groups{tenant="swifttenanttest4",type="limit"} 10
 openstack_nova_quota_security_groups{tenant="swifttenanttest4",type="reserved"} 0
-# HELP openstack_nova_quota_server_group_members quota_server_group_members
+# HELP openstack_nova_quota_server_group_members Quota of server group members of the tenant, by type (in_use, reserved or limit)
 # TYPE openstack_nova_quota_server_group_members gauge
 openstack_nova_quota_server_group_members{tenant="admin",type="in_use"} 0
 openstack_nova_quota_server_group_members{tenant="admin",type="limit"} 10
@@ -450,7 +461,7 @@ openstack_nova_quota_server_group_members{tenant="swifttenanttest2",type="reserv
 openstack_nova_quota_server_group_members{tenant="swifttenanttest4",type="in_use"} 0
 openstack_nova_quota_server_group_members{tenant="swifttenanttest4",type="limit"} 10
 openstack_nova_quota_server_group_members{tenant="swifttenanttest4",type="reserved"} 0
-# HELP openstack_nova_quota_server_groups quota_server_groups
+# HELP openstack_nova_quota_server_groups Quota of server groups of the tenant, by type (in_use, reserved or limit)
 # TYPE openstack_nova_quota_server_groups gauge
 openstack_nova_quota_server_groups{tenant="admin",type="in_use"} 0
 openstack_nova_quota_server_groups{tenant="admin",type="limit"} 10
@@ -476,31 +487,31 @@ openstack_nova_quota_server_groups{tenant="swifttenanttest2",type="reserved"} 0
 openstack_nova_quota_server_groups{tenant="swifttenanttest4",type="in_use"} 0
 openstack_nova_quota_server_groups{tenant="swifttenanttest4",type="limit"} 10
 openstack_nova_quota_server_groups{tenant="swifttenanttest4",type="reserved"} 0
-# HELP openstack_nova_running_vms running_vms
+# HELP openstack_nova_running_vms Number of running VMs of the hypervisor
 # TYPE openstack_nova_running_vms gauge
 openstack_nova_running_vms{aggregates="",availability_zone="",hostname="host1"} 0
-# HELP openstack_nova_security_groups security_groups
+# HELP openstack_nova_security_groups Total number of security groups
 # TYPE openstack_nova_security_groups gauge
 openstack_nova_security_groups 1
-# HELP openstack_nova_server_local_gb server_local_gb
+# HELP openstack_nova_server_local_gb Server local disk size in GB
 # TYPE openstack_nova_server_local_gb gauge
 openstack_nova_server_local_gb{id="27bb2854-b06a-48f5-ab4e-139817b8b8ff",name="openstack-monitoring-0",tenant_id="110f6313d2d346b4aa90eabe4970b62a"} 10
 openstack_nova_server_local_gb{id="2dbdf831-4ffa-485b-8020-216655fb5c7d",name="openstack-monitoring-3",tenant_id="110f6313d2d346b4aa90eabe4970b62a"} 10
 openstack_nova_server_local_gb{id="6c773231-6532-447d-b651-9e0d1518b31d",name="openstack-monitoring-1",tenant_id="110f6313d2d346b4aa90eabe4970b62a"} 10
 openstack_nova_server_local_gb{id="f99bb4a3-90ff-46fa-b8ec-2ef6ac1f3b7d",name="openstack-monitoring-2-prod-zone",tenant_id="110f6313d2d346b4aa90eabe4970b62a"} 10
-# HELP openstack_nova_server_status server_status
+# HELP openstack_nova_server_status Server status, the index of the status in the known server statuses
 # TYPE openstack_nova_server_status gauge
 openstack_nova_server_status{address_ipv4="1.2.3.4",address_ipv6="80fe::",availability_zone="nova",flavor_id="1",host_id="2091634baaccdc4c5a1d57069c833e402921df696b7f970791b12ec6",hypervisor_hostname="fake-mini",id="2ce4c5b3-2866-4972-93ce-77a2ea46a7f9",instance_libvirt="instance-00000001",name="new-server-test",status="ACTIVE",tenant_id="6f70656e737461636b20342065766572",user_id="fake",uuid="2ce4c5b3-2866-4972-93ce-77a2ea46a7f9"} 0
-# HELP openstack_nova_total_vms total_vms
+# HELP openstack_nova_total_vms Total number of VMs
 # TYPE openstack_nova_total_vms gauge
 openstack_nova_total_vms 1
-# HELP openstack_nova_up up
+# HELP openstack_nova_up Whether the OpenStack API of the service is up (1=up, 0=down)
 # TYPE openstack_nova_up gauge
 openstack_nova_up 1
-# HELP openstack_nova_vcpus_available vcpus_available
+# HELP openstack_nova_vcpus_available Available vCPUs of the hypervisor
 # TYPE openstack_nova_vcpus_available gauge
 openstack_nova_vcpus_available{aggregates="",availability_zone="",hostname="host1"} 4
-# HELP openstack_nova_vcpus_used vcpus_used
+# HELP openstack_nova_vcpus_used Used vCPUs of the hypervisor
 # TYPE openstack_nova_vcpus_used gauge
 openstack_nova_vcpus_used{aggregates="",availability_zone="",hostname="host1"} 0
 `
@@ -1,6 +1,7 @@
 package exporters
 
 import (
+	"context"
 	"log/slog"
 
 	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/containers"
@@ -13,11 +14,11 @@ type ObjectStoreExporter struct {
 }
 
 var defaultObjectStoreMetrics = []Metric{
-	{Name: "objects", Labels: []string{"container_name"}, Fn: ListContainers},
-	{Name: "bytes", Labels: []string{"container_name"}, Fn: nil},
+	{Name: "objects", Labels: []string{"container_name"}, Fn: ListContainers, Help: "Number of objects in the container", Type: prometheus.GaugeValue},
+	{Name: "bytes", Labels: []string{"container_name"}, Fn: nil, Help: "Size of the objects in the container in bytes", Type: prometheus.GaugeValue, Unit: "bytes"},
 }
 
-func NewObjectStoreExporter(config *ExporterConfig, logger *slog.Logger) (*ObjectStoreExporter, error) {
+func NewObjectStoreExporter(ctx context.Context, config *ExporterConfig, logger *slog.Logger) (*ObjectStoreExporter, error) {
 	exporter := ObjectStoreExporter{
 		BaseOpenStackExporter{
 			Name:           "object_store",
@@ -31,14 +32,14 @@ func NewObjectStoreExporter(config *ExporterConfig, logger *slog.Logger) (*Objec
 			continue
 		}
 		if !exporter.isSlowMetric(&metric) {
-			exporter.AddMetric(metric.Name, metric.Fn, metric.Labels, metric.DeprecatedVersion, nil)
+			exporter.addMetric(metric, nil)
 		}
 	}
 
 	return &exporter, nil
 }
 
-func ListContainers(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListContainers(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	err := containers.List(exporter.Client, containers.ListOpts{Full: true}).EachPage(func(page pagination.Page) (bool, error) {
 		containerList, err := containers.ExtractInfo(page)
 		if err != nil {
@@ -47,9 +48,9 @@ func ListContainers(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric
 
 		for _, c := range containerList {
 			ch <- prometheus.MustNewConstMetric(exporter.Metrics["objects"].Metric,
-				prometheus.GaugeValue, float64(c.Count), c.Name)
+				exporter.Metrics["objects"].Type, float64(c.Count), c.Name)
 			ch <- prometheus.MustNewConstMetric(exporter.Metrics["bytes"].Metric,
-				prometheus.GaugeValue, float64(c.Bytes), c.Name)
+				exporter.Metrics["bytes"].Type, float64(c.Bytes), c.Name)
 		}
 		return true, nil
 	})
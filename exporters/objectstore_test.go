@@ -0,0 +1,32 @@
+package exporters
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type ObjectStoreTestSuite struct {
+	BaseOpenStackTestSuite
+}
+
+var objectStoreExpectedUp = `
+# HELP openstack_collector_success Whether the last collection of the metric from OpenStack API succeeded
+# TYPE openstack_collector_success gauge
+openstack_collector_success{metric="objects",service="object_store"} 1
+# HELP openstack_object_store_bytes Size of the objects in the container in bytes
+# TYPE openstack_object_store_bytes gauge
+openstack_object_store_bytes{container_name="container"} 0
+# HELP openstack_object_store_objects Number of objects in the container
+# TYPE openstack_object_store_objects gauge
+openstack_object_store_objects{container_name="container"} 0
+# HELP openstack_object_store_up Whether the OpenStack API of the service is up (1=up, 0=down)
+# TYPE openstack_object_store_up gauge
+openstack_object_store_up 1
+`
+
+func (suite *ObjectStoreTestSuite) TestObjectStoreExporter() {
+	err := testutil.CollectAndCompare(*suite.Exporter, strings.NewReader(objectStoreExpectedUp))
+	assert.NoError(suite.T(), err)
+}
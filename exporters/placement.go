@@ -1,6 +1,7 @@
 package exporters
 
 import (
+	"context"
 	"log/slog"
 
 	"github.com/gophercloud/gophercloud/openstack/placement/v1/resourceproviders"
@@ -12,13 +13,13 @@ type PlacementExporter struct {
 }
 
 var defaultPlacementMetrics = []Metric{
-	{Name: "resource_total", Fn: ListPlacementResourceProviders, Labels: []string{"hostname", "resourcetype"}},
-	{Name: "resource_allocation_ratio", Labels: []string{"hostname", "resourcetype"}},
-	{Name: "resource_reserved", Labels: []string{"hostname", "resourcetype"}},
-	{Name: "resource_usage", Labels: []string{"hostname", "resourcetype"}},
+	{Name: "resource_total", Fn: ListPlacementResourceProviders, Labels: []string{"hostname", "resourcetype"}, Help: "Total amount of the resource class of the resource provider", Type: prometheus.GaugeValue},
+	{Name: "resource_allocation_ratio", Labels: []string{"hostname", "resourcetype"}, Help: "Allocation ratio of the resource class of the resource provider", Type: prometheus.GaugeValue},
+	{Name: "resource_reserved", Labels: []string{"hostname", "resourcetype"}, Help: "Reserved amount of the resource class of the resource provider", Type: prometheus.GaugeValue},
+	{Name: "resource_usage", Labels: []string{"hostname", "resourcetype"}, Help: "Used amount of the resource class of the resource provider", Type: prometheus.GaugeValue},
 }
 
-func NewPlacementExporter(config *ExporterConfig, logger *slog.Logger) (*PlacementExporter, error) {
+func NewPlacementExporter(ctx context.Context, config *ExporterConfig, logger *slog.Logger) (*PlacementExporter, error) {
 	exporter := PlacementExporter{
 		BaseOpenStackExporter{
 			Name:           "placement",
@@ -31,13 +32,13 @@ func NewPlacementExporter(config *ExporterConfig, logger *slog.Logger) (*Placeme
 			continue
 		}
 		if !exporter.isSlowMetric(&metric) {
-			exporter.AddMetric(metric.Name, metric.Fn, metric.Labels, metric.DeprecatedVersion, nil)
+			exporter.addMetric(metric, nil)
 		}
 	}
 	return &exporter, nil
 }
 
-func ListPlacementResourceProviders(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListPlacementResourceProviders(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allResourceProviders []resourceproviders.ResourceProvider
 
 	allPagesResourceProviders, err := resourceproviders.List(exporter.Client, resourceproviders.ListOpts{}).AllPages()
@@ -62,13 +63,13 @@ func ListPlacementResourceProviders(exporter *BaseOpenStackExporter, ch chan<- p
 		for k, v := range inventoryResult.Inventories {
 
 			ch <- prometheus.MustNewConstMetric(exporter.Metrics["resource_total"].Metric,
-				prometheus.GaugeValue, float64(v.Total), resourceprovider.Name, k)
+				exporter.Metrics["resource_total"].Type, float64(v.Total), resourceprovider.Name, k)
 
 			ch <- prometheus.MustNewConstMetric(exporter.Metrics["resource_allocation_ratio"].Metric,
-				prometheus.GaugeValue, float64(v.AllocationRatio), resourceprovider.Name, k)
+				exporter.Metrics["resource_allocation_ratio"].Type, float64(v.AllocationRatio), resourceprovider.Name, k)
 
 			ch <- prometheus.MustNewConstMetric(exporter.Metrics["resource_reserved"].Metric,
-				prometheus.GaugeValue, float64(v.Reserved), resourceprovider.Name, k)
+				exporter.Metrics["resource_reserved"].Type, float64(v.Reserved), resourceprovider.Name, k)
 		}
 
 		usagesResult, err := resourceproviders.GetUsages(exporter.Client, resourceprovider.UUID).Extract()
@@ -78,7 +79,7 @@ func ListPlacementResourceProviders(exporter *BaseOpenStackExporter, ch chan<- p
 
 		for k, v := range usagesResult.Usages {
 			ch <- prometheus.MustNewConstMetric(exporter.Metrics["resource_usage"].Metric,
-				prometheus.GaugeValue, float64(v), resourceprovider.Name, k)
+				exporter.Metrics["resource_usage"].Type, float64(v), resourceprovider.Name, k)
 		}
 
 	}
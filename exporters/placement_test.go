@@ -12,7 +12,10 @@ type PlacementTestSuite struct {
 }
 
 var placementExpected = `
-# HELP openstack_placement_resource_allocation_ratio resource_allocation_ratio
+# HELP openstack_collector_success Whether the last collection of the metric from OpenStack API succeeded
+# TYPE openstack_collector_success gauge
+openstack_collector_success{metric="resource_total",service="placement"} 1
+# HELP openstack_placement_resource_allocation_ratio Allocation ratio of the resource class of the resource provider
 # TYPE openstack_placement_resource_allocation_ratio gauge
 openstack_placement_resource_allocation_ratio{hostname="cmp-1-svr8204.localdomain",resourcetype="DISK_GB"} 1.2000000476837158
 openstack_placement_resource_allocation_ratio{hostname="cmp-1-svr8204.localdomain",resourcetype="MEMORY_MB"} 1.2999999523162842
@@ -20,7 +23,7 @@ openstack_placement_resource_allocation_ratio{hostname="cmp-1-svr8204.localdomai
 openstack_placement_resource_allocation_ratio{hostname="cmp-5-svr8208.localdomain",resourcetype="DISK_GB"} 1.2000000476837158
 openstack_placement_resource_allocation_ratio{hostname="cmp-5-svr8208.localdomain",resourcetype="MEMORY_MB"} 1
 openstack_placement_resource_allocation_ratio{hostname="cmp-5-svr8208.localdomain",resourcetype="PCPU"} 1
-# HELP openstack_placement_resource_reserved resource_reserved
+# HELP openstack_placement_resource_reserved Reserved amount of the resource class of the resource provider
 # TYPE openstack_placement_resource_reserved gauge
 openstack_placement_resource_reserved{hostname="cmp-1-svr8204.localdomain",resourcetype="DISK_GB"} 0
 openstack_placement_resource_reserved{hostname="cmp-1-svr8204.localdomain",resourcetype="MEMORY_MB"} 8192
@@ -28,7 +31,7 @@ openstack_placement_resource_reserved{hostname="cmp-1-svr8204.localdomain",resou
 openstack_placement_resource_reserved{hostname="cmp-5-svr8208.localdomain",resourcetype="DISK_GB"} 0
 openstack_placement_resource_reserved{hostname="cmp-5-svr8208.localdomain",resourcetype="MEMORY_MB"} 8192
 openstack_placement_resource_reserved{hostname="cmp-5-svr8208.localdomain",resourcetype="PCPU"} 0
-# HELP openstack_placement_resource_total resource_total
+# HELP openstack_placement_resource_total Total amount of the resource class of the resource provider
 # TYPE openstack_placement_resource_total gauge
 openstack_placement_resource_total{hostname="cmp-1-svr8204.localdomain",resourcetype="DISK_GB"} 2047
 openstack_placement_resource_total{hostname="cmp-1-svr8204.localdomain",resourcetype="MEMORY_MB"} 772447
@@ -36,7 +39,7 @@ openstack_placement_resource_total{hostname="cmp-1-svr8204.localdomain",resource
 openstack_placement_resource_total{hostname="cmp-5-svr8208.localdomain",resourcetype="DISK_GB"} 2047
 openstack_placement_resource_total{hostname="cmp-5-svr8208.localdomain",resourcetype="MEMORY_MB"} 772447
 openstack_placement_resource_total{hostname="cmp-5-svr8208.localdomain",resourcetype="PCPU"} 96
-# HELP openstack_placement_resource_usage resource_usage
+# HELP openstack_placement_resource_usage Used amount of the resource class of the resource provider
 # TYPE openstack_placement_resource_usage gauge
 openstack_placement_resource_usage{hostname="cmp-1-svr8204.localdomain",resourcetype="DISK_GB"} 6969
 openstack_placement_resource_usage{hostname="cmp-1-svr8204.localdomain",resourcetype="MEMORY_MB"} 1945
@@ -44,7 +47,7 @@ openstack_placement_resource_usage{hostname="cmp-1-svr8204.localdomain",resource
 openstack_placement_resource_usage{hostname="cmp-5-svr8208.localdomain",resourcetype="DISK_GB"} 0
 openstack_placement_resource_usage{hostname="cmp-5-svr8208.localdomain",resourcetype="MEMORY_MB"} 0
 openstack_placement_resource_usage{hostname="cmp-5-svr8208.localdomain",resourcetype="PCPU"} 0
-# HELP openstack_placement_up up
+# HELP openstack_placement_up Whether the OpenStack API of the service is up (1=up, 0=down)
 # TYPE openstack_placement_up gauge
 openstack_placement_up 1
 `
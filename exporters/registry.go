@@ -0,0 +1,134 @@
+package exporters
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// Factory creates the exporter of a service type, with the service clients set in config. The
+// exporters of other packages build their base with NewBaseOpenStackExporter.
+type Factory func(ctx context.Context, config *ExporterConfig, logger *slog.Logger) (OpenStackExporter, error)
+
+var (
+	factories    = make(map[string]Factory)
+	serviceTypes []string
+	// catalogs are the metric tables of the exporters, by service type.
+	catalogs    = make(map[string]serviceMetrics)
+	factoriesMu sync.RWMutex
+)
+
+// serviceMetrics are the metrics of the exporter of a service, named name.
+type serviceMetrics struct {
+	name    string
+	metrics []Metric
+}
+
+func init() {
+	for _, builtin := range []struct {
+		serviceType string
+		name        string
+		factory     Factory
+		metrics     []Metric
+	}{
+		{"network", "neutron", exporterFactory(NewNeutronExporter), defaultNeutronMetrics},
+		{"compute", "nova", exporterFactory(NewNovaExporter), defaultNovaMetrics},
+		{"image", "glance", exporterFactory(NewGlanceExporter), defaultGlanceMetrics},
+		{"volume", "cinder", exporterFactory(NewCinderExporter), defaultCinderMetrics},
+		{"identity", "identity", exporterFactory(NewKeystoneExporter), defaultKeystoneMetrics},
+		{"object-store", "object_store", exporterFactory(NewObjectStoreExporter), defaultObjectStoreMetrics},
+		{"load-balancer", "loadbalancer", exporterFactory(NewLoadbalancerExporter), defaultLoadbalancerMetrics},
+		{"container-infra", "container_infra", exporterFactory(NewContainerInfraExporter), defaultContainerInfraMetrics},
+		{"dns", "designate", exporterFactory(NewDesignateExporter), defaultDesignateMetrics},
+		{"baremetal", "ironic", exporterFactory(NewIronicExporter), defaultIronicMetrics},
+		{"gnocchi", "gnocchi", exporterFactory(NewGnocchiExporter), defaultGnocchiMetrics},
+		{"database", "trove", exporterFactory(NewTroveExporter), defaultTroveMetrics},
+		{"orchestration", "heat", exporterFactory(NewHeatExporter), defaultHeatMetrics},
+		{"placement", "placement", exporterFactory(NewPlacementExporter), defaultPlacementMetrics},
+		{"sharev2", "sharev2", exporterFactory(NewManilaExporter), defaultManilaMetrics},
+	} {
+		Register(builtin.serviceType, builtin.factory)
+		RegisterMetrics(builtin.serviceType, builtin.name, builtin.metrics)
+	}
+}
+
+// Register makes the exporter of a service type available to NewExporter, and to the service
+// flags and options of the exporter. The service type is the type of the service in the Keystone
+// catalog, the exporters of other packages are meant to be registered from their init function.
+// It panics if the service type is empty or already registered.
+func Register(serviceType string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if serviceType == "" || factory == nil {
+		panic("exporters: Register called with an empty service type or a nil factory")
+	}
+	if _, ok := factories[serviceType]; ok {
+		panic(fmt.Sprintf("exporters: Register called twice for service %s", serviceType))
+	}
+	factories[serviceType] = factory
+	serviceTypes = append(serviceTypes, serviceType)
+}
+
+// RegisterMetrics describes the metrics of the exporter of a service type for the catalog of the
+// metrics, name being the name of the exporter in their fully qualified names (i.e: neutron).
+func RegisterMetrics(serviceType, name string, metrics []Metric) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	catalogs[serviceType] = serviceMetrics{name: name, metrics: metrics}
+}
+
+// Services returns the registered service types, in their registration order.
+func Services() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	return slices.Clone(serviceTypes)
+}
+
+// ServiceType returns the registered service type of a service given by its type or by the name
+// of its exporter (i.e: nova for compute).
+func ServiceType(service string) (string, bool) {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	if _, ok := factories[service]; ok {
+		return service, true
+	}
+	for _, serviceType := range serviceTypes {
+		if catalogs[serviceType].name == service {
+			return serviceType, true
+		}
+	}
+	return "", false
+}
+
+// ServiceName returns the name of the exporter of a service type in the fully qualified names of
+// its metrics (i.e: nova for compute), the service type itself if its metrics are not registered.
+func ServiceName(serviceType string) string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	if catalog, ok := catalogs[serviceType]; ok {
+		return catalog.name
+	}
+	return serviceType
+}
+
+// lookupFactory returns the factory of the exporter of a service type.
+func lookupFactory(serviceType string) (Factory, bool) {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	factory, ok := factories[serviceType]
+	return factory, ok
+}
+
+// exporterFactory adapts the constructor of an exporter to a Factory.
+func exporterFactory[E OpenStackExporter](newExporter func(context.Context, *ExporterConfig, *slog.Logger) (E, error)) Factory {
+	return func(ctx context.Context, config *ExporterConfig, logger *slog.Logger) (OpenStackExporter, error) {
+		exporter, err := newExporter(ctx, config, logger)
+		if err != nil {
+			return nil, err
+		}
+		return exporter, nil
+	}
+}
@@ -0,0 +1,68 @@
+package exporters
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"slices"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/openstack-exporter/openstack-exporter/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type registryTestExporter struct {
+	BaseOpenStackExporter
+}
+
+func unregister(serviceType string) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	delete(factories, serviceType)
+	delete(catalogs, serviceType)
+	serviceTypes = slices.DeleteFunc(serviceTypes, func(s string) bool { return s == serviceType })
+}
+
+func TestRegister(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	data, err := os.ReadFile(path.Join(baseFixturePath, "tokens.json"))
+	require.NoError(t, err)
+	httpmock.RegisterResponder("POST", "http://test.cloud:35357/v3/auth/tokens",
+		httpmock.NewBytesResponder(201, data).HeaderSet(map[string][]string{"X-Subject-Token": {"token"}}))
+	t.Setenv("OS_CLIENT_CONFIG_FILE", path.Join(baseFixturePath, "test_config.yaml"))
+
+	Register("metric", func(ctx context.Context, config *ExporterConfig, logger *slog.Logger) (OpenStackExporter, error) {
+		return &registryTestExporter{BaseOpenStackExporter{Name: "metric", ExporterConfig: *config, logger: logger}}, nil
+	})
+	defer unregister("metric")
+
+	services := Services()
+	assert.Equal(t, []string{"network", "compute", "image"}, services[:3], "the services should be in their registration order")
+	assert.Equal(t, "metric", services[len(services)-1])
+	assert.Panics(t, func() { Register("metric", exporterFactory(NewGnocchiExporter)) }, "a service type should only be registered once")
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	exporter, err := NewExporter(context.Background(), ExporterOptions{Service: "metric", Prefix: "openstack", Cloud: cloudName, EndpointType: "public", LabelMappings: new(utils.ResourceLabelMappingFlag), CollectConcurrency: 1}, logger)
+	require.NoError(t, err)
+	if assert.IsType(t, &registryTestExporter{}, exporter) {
+		assert.Equal(t, "http://test.cloud/gnocchi/", exporter.(*registryTestExporter).Client.Endpoint, "the client should use the catalog endpoint of the service type")
+	}
+
+	_, err = NewExporter(context.Background(), ExporterOptions{Service: "key-manager", Prefix: "openstack", Cloud: cloudName, EndpointType: "public", LabelMappings: new(utils.ResourceLabelMappingFlag), CollectConcurrency: 1}, logger)
+	assert.ErrorContains(t, err, "couldn't find a handler for key-manager exporter")
+}
+
+func TestServiceType(t *testing.T) {
+	for service, expected := range map[string]string{"compute": "compute", "nova": "compute", "object_store": "object-store", "sharev2": "sharev2"} {
+		serviceType, ok := ServiceType(service)
+		assert.True(t, ok, "service %s should be registered", service)
+		assert.Equal(t, expected, serviceType)
+	}
+	_, ok := ServiceType("barbican")
+	assert.False(t, ok)
+}
@@ -0,0 +1,35 @@
+package exporters
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// CollectionErrors returns the failed metric collections reported by the gathered metric families,
+// the metrics whose <prefix>_collector_success is 0, as service/metric with the region if any.
+func CollectionErrors(mfs []*dto.MetricFamily, prefix string) []string {
+	name := prometheus.BuildFQName(prefix, "collector", "success")
+	failures := []string{}
+	for _, mf := range mfs {
+		if mf.GetName() != name {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			if m.GetGauge().GetValue() != 0 {
+				continue
+			}
+			labels := map[string]string{}
+			for _, label := range m.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			failure := fmt.Sprintf("%s/%s", labels["service"], labels["metric"])
+			if region, ok := labels["region"]; ok {
+				failure += fmt.Sprintf(" (region %s)", region)
+			}
+			failures = append(failures, failure)
+		}
+	}
+	return failures
+}
@@ -0,0 +1,24 @@
+package exporters
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionErrors(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	success := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "openstack_collector_success"}, []string{"metric", "service", "region"})
+	up := prometheus.NewGauge(prometheus.GaugeOpts{Name: "openstack_neutron_up"})
+	registry.MustRegister(success, up)
+	success.WithLabelValues("networks", "neutron", "RegionOne").Set(1)
+	success.WithLabelValues("ports", "neutron", "RegionOne").Set(0)
+	success.WithLabelValues("limits", "nova", "RegionTwo").Set(0)
+
+	mfs, err := registry.Gather()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nova/limits (region RegionTwo)", "neutron/ports (region RegionOne)"}, CollectionErrors(mfs, "openstack"))
+	assert.Empty(t, CollectionErrors(mfs, "custom"))
+}
@@ -0,0 +1,123 @@
+package exporters
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
+	"github.com/gophercloud/gophercloud/openstack/sharedfilesystems/v2/shares"
+	"github.com/gophercloud/gophercloud/pagination"
+)
+
+// snapshot memoizes the OpenStack resources listed during one collection, so a list
+// needed by several metrics or exporters is only fetched once.
+type snapshot struct {
+	mu      sync.Mutex
+	entries map[string]*snapshotEntry
+}
+
+type snapshotEntry struct {
+	done  chan struct{}
+	value any
+	err   error
+}
+
+type snapshotContextKey struct{}
+
+// WithSnapshot returns a copy of parent carrying a new resource snapshot. The exporters
+// collecting with the returned context share the resources they list.
+func WithSnapshot(parent context.Context) context.Context {
+	return context.WithValue(parent, snapshotContextKey{}, &snapshot{entries: make(map[string]*snapshotEntry)})
+}
+
+func hasSnapshot(ctx context.Context) bool {
+	_, ok := ctx.Value(snapshotContextKey{}).(*snapshot)
+	return ok
+}
+
+// memoize returns the resources of key from the snapshot of ctx, calling fetch only
+// if no other collection of the snapshot listed them yet. The errors are memoized too,
+// so a failing list isn't retried by every metric during the same collection.
+func memoize[T any](ctx context.Context, key string, fetch func() (T, error)) (T, error) {
+	var zero T
+
+	s, ok := ctx.Value(snapshotContextKey{}).(*snapshot)
+	if !ok {
+		return fetch()
+	}
+
+	s.mu.Lock()
+	entry, found := s.entries[key]
+	if !found {
+		entry = &snapshotEntry{done: make(chan struct{})}
+		s.entries[key] = entry
+	}
+	s.mu.Unlock()
+
+	if !found {
+		// The waiters are released with an error if fetch panics, the panic goes on in this collection.
+		entry.err = fmt.Errorf("listing %s panicked", key)
+		defer close(entry.done)
+		value, err := fetch()
+		entry.value, entry.err = value, err
+	} else {
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+
+	if entry.err != nil {
+		return zero, entry.err
+	}
+	return entry.value.(T), nil
+}
+
+// listProjects returns the projects of the domain, or of every domain if domainID is empty.
+func listProjects(ctx context.Context, client *gophercloud.ServiceClient, domainID string) ([]projects.Project, error) {
+	key := fmt.Sprintf("projects %s domain=%s", client.Endpoint, domainID)
+	return memoize(ctx, key, func() ([]projects.Project, error) {
+		allPagesProject, err := projects.List(client, projects.ListOpts{DomainID: domainID}).AllPages()
+		if err != nil {
+			return nil, err
+		}
+		return projects.ExtractProjects(allPagesProject)
+	})
+}
+
+// listFlavors returns the flavors of every access type.
+func listFlavors(ctx context.Context, client *gophercloud.ServiceClient) ([]flavors.Flavor, error) {
+	key := fmt.Sprintf("flavors %s", client.Endpoint)
+	return memoize(ctx, key, func() ([]flavors.Flavor, error) {
+		allPagesFlavors, err := flavors.ListDetail(client, flavors.ListOpts{AccessType: "None"}).AllPages()
+		if err != nil {
+			return nil, err
+		}
+		return flavors.ExtractFlavors(allPagesFlavors)
+	})
+}
+
+// listServers returns the pages of the servers, the callers extract them with the extensions they need.
+func listServers(ctx context.Context, client *gophercloud.ServiceClient, opts servers.ListOpts) (pagination.Page, error) {
+	key := fmt.Sprintf("servers %s microversion=%s all_tenants=%t tenant=%s", client.Endpoint, client.Microversion, opts.AllTenants, opts.TenantID)
+	return memoize(ctx, key, func() (pagination.Page, error) {
+		return servers.List(client, opts).AllPages()
+	})
+}
+
+// listShares returns the shares of every tenant.
+func listShares(ctx context.Context, client *gophercloud.ServiceClient) ([]shares.Share, error) {
+	key := fmt.Sprintf("shares %s microversion=%s", client.Endpoint, client.Microversion)
+	return memoize(ctx, key, func() ([]shares.Share, error) {
+		allPagesShares, err := shares.ListDetail(client, shares.ListOpts{AllTenants: true}).AllPages()
+		if err != nil {
+			return nil, err
+		}
+		return shares.ExtractShares(allPagesShares)
+	})
+}
@@ -0,0 +1,82 @@
+package exporters
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoize(t *testing.T) {
+	var calls atomic.Int32
+	fetch := func() ([]string, error) {
+		calls.Add(1)
+		return []string{"a", "b"}, nil
+	}
+
+	ctx := WithSnapshot(context.Background())
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := memoize(ctx, "key", fetch)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"a", "b"}, value)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), calls.Load(), "a snapshot should fetch the resources once")
+
+	_, _ = memoize(WithSnapshot(context.Background()), "key", fetch)
+	assert.Equal(t, int32(2), calls.Load(), "a new snapshot should fetch the resources again")
+
+	_, _ = memoize(context.Background(), "key", fetch)
+	_, _ = memoize(context.Background(), "key", fetch)
+	assert.Equal(t, int32(4), calls.Load(), "the resources should not be memoized without a snapshot")
+}
+
+func TestMemoizeError(t *testing.T) {
+	var calls atomic.Int32
+	fetchErr := errors.New("boom")
+	fetch := func() ([]string, error) {
+		calls.Add(1)
+		return nil, fetchErr
+	}
+
+	ctx := WithSnapshot(context.Background())
+	for i := 0; i < 2; i++ {
+		_, err := memoize(ctx, "key", fetch)
+		assert.ErrorIs(t, err, fetchErr)
+	}
+	assert.Equal(t, int32(1), calls.Load(), "a failed list should not be retried in the same snapshot")
+}
+
+func TestMemoizePanic(t *testing.T) {
+	ctx := WithSnapshot(context.Background())
+	started := make(chan struct{})
+	waiter := make(chan error)
+	go func() {
+		<-started
+		_, err := memoize(ctx, "key", func() ([]string, error) { return []string{"a"}, nil })
+		waiter <- err
+	}()
+
+	assert.Panics(t, func() {
+		_, _ = memoize(ctx, "key", func() ([]string, error) {
+			close(started)
+			time.Sleep(10 * time.Millisecond)
+			panic("boom")
+		})
+	})
+	select {
+	case err := <-waiter:
+		assert.ErrorContains(t, err, "listing key panicked")
+	case <-time.After(time.Second):
+		t.Fatal("the waiters of a panicking list should be released")
+	}
+}
@@ -0,0 +1,184 @@
+package exporters
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// statusLabels returns the labels of the metric. In StateSet mode, the status metrics get a last
+// label named after the metric, holding the state of each series.
+func (exporter *BaseOpenStackExporter) statusLabels(metric *Metric) []string {
+	if !exporter.StateSetStatus || metric.States == nil {
+		return metric.Labels
+	}
+	return append(slices.Clone(metric.Labels), prometheus.BuildFQName(exporter.Prefix, exporter.Name, metric.Name))
+}
+
+// statusHelp returns the help of the metric. In StateSet mode, the value of the status metrics
+// is not the index of the status, so their help describes their series instead.
+func (exporter *BaseOpenStackExporter) statusHelp(metric *Metric, help string) string {
+	if !exporter.StateSetStatus || metric.States == nil {
+		return help
+	}
+	subject, _, _ := strings.Cut(help, ",")
+	return subject + ", one series per known status set to 1 for the current status and 0 for the others"
+}
+
+// SendStatus sends the status of a resource, index being the index of status in the known states of
+// the metric, or -1 when unknown. By default the index is the value of the metric. In StateSet mode,
+// the metric has one series per known state, set to 1 for the current status and 0 for the others,
+// and an unknown status is sent as an additional state set to 1.
+func (exporter *BaseOpenStackExporter) SendStatus(ch chan<- prometheus.Metric, name string, states []string, index int, status string, labelValues ...string) {
+	desc := exporter.Metrics[name].Metric
+	if !exporter.StateSetStatus {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(index), labelValues...)
+		return
+	}
+
+	for i, state := range states {
+		value := 0.0
+		if i == index {
+			value = 1
+		}
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, slices.Concat(labelValues, []string{state})...)
+	}
+	if index == -1 {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, 1, slices.Concat(labelValues, []string{status})...)
+	}
+}
+
+// HandlerFor returns a promhttp handler of the metrics of gatherer. With stateSet, OpenMetrics is
+// negotiated and the status metrics are sent with the StateSet type, and the unit of the metrics whose
+// name ends with their unit, other formats are served as usual. The OpenMetrics responses honor the ErrorLog, ErrorHandling and compression options like promhttp.
+func HandlerFor(gatherer prometheus.Gatherer, opts promhttp.HandlerOpts, stateSet bool) http.Handler {
+	gatherer = WithUnits(gatherer)
+	handler := promhttp.HandlerFor(gatherer, opts)
+	if !stateSet {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		format := expfmt.NegotiateIncludingOpenMetrics(r.Header)
+		if format.FormatType() != expfmt.TypeOpenMetrics {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		mfs, err := gatherer.Gather()
+		if err != nil {
+			if opts.ErrorLog != nil {
+				opts.ErrorLog.Println("error gathering metrics:", err)
+			}
+			switch opts.ErrorHandling {
+			case promhttp.PanicOnError:
+				panic(err)
+			case promhttp.ContinueOnError:
+				if len(mfs) == 0 {
+					// Still report the error if no metrics have been gathered.
+					http.Error(w, "An error has occurred while serving metrics:\n\n"+err.Error(), http.StatusInternalServerError)
+					return
+				}
+			case promhttp.HTTPErrorOnError:
+				http.Error(w, "An error has occurred while serving metrics:\n\n"+err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		var buf bytes.Buffer
+		if err := writeOpenMetrics(&buf, mfs); err != nil {
+			if opts.ErrorLog != nil {
+				opts.ErrorLog.Println("error encoding metric families:", err)
+			}
+			if opts.ErrorHandling == promhttp.PanicOnError {
+				panic(err)
+			}
+			http.Error(w, "An error has occurred while serving metrics:\n\n"+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", string(format))
+		if !acceptsGzip(r, opts) {
+			_, _ = w.Write(buf.Bytes())
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write(buf.Bytes())
+		if err := gz.Close(); err != nil && opts.ErrorLog != nil {
+			opts.ErrorLog.Println("error compressing metrics:", err)
+		}
+	})
+}
+
+// acceptsGzip returns true if the response to r can be compressed with gzip, the client accepting it
+// and the compression being offered by opts.
+func acceptsGzip(r *http.Request, opts promhttp.HandlerOpts) bool {
+	if opts.DisableCompression {
+		return false
+	}
+	if len(opts.OfferedCompressions) > 0 && !slices.Contains(opts.OfferedCompressions, promhttp.Gzip) {
+		return false
+	}
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(encoding, ";")
+		if strings.TrimSpace(name) != "gzip" {
+			continue
+		}
+		q, found := strings.CutPrefix(strings.ReplaceAll(params, " ", ""), "q=")
+		if !found {
+			return true
+		}
+		value, err := strconv.ParseFloat(q, 64)
+		return err == nil && value > 0
+	}
+	return false
+}
+
+// writeOpenMetrics writes the metric families in the OpenMetrics format, with the StateSet type for
+// the families whose series all have a label named after the family, and the unit of the families
+// whose name ends with it.
+func writeOpenMetrics(buf *bytes.Buffer, mfs []*dto.MetricFamily) error {
+	for _, mf := range mfs {
+		start := buf.Len()
+		// OpenMetrics requires the name of a metric having a unit to end with it.
+		var options []expfmt.EncoderOption
+		if mf.Unit != nil && strings.HasSuffix(strings.TrimSuffix(mf.GetName(), "_total"), "_"+mf.GetUnit()) {
+			options = append(options, expfmt.WithUnit())
+		}
+		if _, err := expfmt.MetricFamilyToOpenMetrics(buf, mf, options...); err != nil {
+			return err
+		}
+		if isStateSet(mf) {
+			family := buf.Bytes()[start:]
+			typeLine := []byte("# TYPE " + mf.GetName() + " gauge\n")
+			if i := bytes.Index(family, typeLine); i >= 0 {
+				stateSet := slices.Concat(family[:i], []byte("# TYPE "+mf.GetName()+" stateset\n"), family[i+len(typeLine):])
+				buf.Truncate(start)
+				buf.Write(stateSet)
+			}
+		}
+	}
+	_, err := expfmt.FinalizeOpenMetrics(buf)
+	return err
+}
+
+// isStateSet returns true if the family is a gauge whose series all have a label named after the family.
+func isStateSet(mf *dto.MetricFamily) bool {
+	if mf.GetType() != dto.MetricType_GAUGE || len(mf.Metric) == 0 {
+		return false
+	}
+	for _, metric := range mf.Metric {
+		if !slices.ContainsFunc(metric.Label, func(pair *dto.LabelPair) bool { return pair.GetName() == mf.GetName() }) {
+			return false
+		}
+	}
+	return true
+}
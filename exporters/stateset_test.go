@@ -0,0 +1,143 @@
+package exporters
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testServerStates = []string{"ACTIVE", "ERROR"}
+
+func newStatusExporter(stateSet bool) *BaseOpenStackExporter {
+	exporter := &BaseOpenStackExporter{
+		Name: "test",
+		ExporterConfig: ExporterConfig{
+			Cloud:              "states",
+			Prefix:             "openstack",
+			CollectConcurrency: 1,
+			StateSetStatus:     stateSet,
+		},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	metric := Metric{Name: "server_status", Labels: []string{"id"}, States: testServerStates, Help: "Server status, the index of the status in the known server statuses", Fn: func(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+		for id, status := range map[string]string{"a": "ERROR", "b": "SHELVED"} {
+			index := -1
+			for i, state := range testServerStates {
+				if state == status {
+					index = i
+				}
+			}
+			exporter.SendStatus(ch, "server_status", testServerStates, index, status, id)
+		}
+		return nil
+	}}
+	exporter.addMetric(metric, nil)
+	return exporter
+}
+
+func TestSendStatus(t *testing.T) {
+	collectorErrors = make(map[collectorErrorKey]*collectorErrorStats)
+
+	expected := `
+# HELP openstack_test_server_status Server status, the index of the status in the known server statuses
+# TYPE openstack_test_server_status gauge
+openstack_test_server_status{id="a"} 1
+openstack_test_server_status{id="b"} -1
+`
+	err := testutil.CollectAndCompare(newStatusExporter(false), strings.NewReader(expected), "openstack_test_server_status")
+	assert.NoError(t, err)
+
+	expected = `
+# HELP openstack_test_server_status Server status, one series per known status set to 1 for the current status and 0 for the others
+# TYPE openstack_test_server_status gauge
+openstack_test_server_status{id="a",openstack_test_server_status="ACTIVE"} 0
+openstack_test_server_status{id="a",openstack_test_server_status="ERROR"} 1
+openstack_test_server_status{id="b",openstack_test_server_status="ACTIVE"} 0
+openstack_test_server_status{id="b",openstack_test_server_status="ERROR"} 0
+openstack_test_server_status{id="b",openstack_test_server_status="SHELVED"} 1
+`
+	err = testutil.CollectAndCompare(newStatusExporter(true), strings.NewReader(expected), "openstack_test_server_status")
+	assert.NoError(t, err)
+}
+
+func TestHandlerForStateSet(t *testing.T) {
+	collectorErrors = make(map[collectorErrorKey]*collectorErrorStats)
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(newStatusExporter(true))
+	handler := HandlerFor(registry, promhttp.HandlerOpts{}, true)
+
+	request := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	request.Header.Set("Accept", "application/openmetrics-text;version=1.0.0")
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+
+	body := response.Body.String()
+	assert.Contains(t, response.Header().Get("Content-Type"), "application/openmetrics-text")
+	assert.Contains(t, body, "# TYPE openstack_test_server_status stateset\n")
+	assert.Contains(t, body, `openstack_test_server_status{id="b",openstack_test_server_status="SHELVED"} 1.0`)
+	assert.Contains(t, body, "# TYPE openstack_test_up gauge\n", "the other metrics should keep their type")
+	assert.True(t, strings.HasSuffix(body, "# EOF\n"))
+
+	request.Header.Set("Accept", "text/plain")
+	response = httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	assert.Contains(t, response.Body.String(), "# TYPE openstack_test_server_status gauge\n")
+}
+
+func TestHandlerForStateSetErrors(t *testing.T) {
+	collectorErrors = make(map[collectorErrorKey]*collectorErrorStats)
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(newStatusExporter(true))
+	gatherer := prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		mfs, err := registry.Gather()
+		require.NoError(t, err)
+		return mfs, errors.New("collection failed")
+	})
+	serve := func(opts promhttp.HandlerOpts, acceptEncoding string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		request.Header.Set("Accept", "application/openmetrics-text;version=1.0.0")
+		request.Header.Set("Accept-Encoding", acceptEncoding)
+		response := httptest.NewRecorder()
+		HandlerFor(gatherer, opts, true).ServeHTTP(response, request)
+		return response
+	}
+
+	var errorLog bytes.Buffer
+	response := serve(promhttp.HandlerOpts{ErrorLog: log.New(&errorLog, "", 0)}, "")
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+	assert.Contains(t, response.Body.String(), "collection failed")
+	assert.Equal(t, "error gathering metrics: collection failed\n", errorLog.String())
+
+	response = serve(promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError}, "gzip")
+	assert.Equal(t, http.StatusOK, response.Code, "the metrics gathered should be served despite the error")
+	assert.Equal(t, "gzip", response.Header().Get("Content-Encoding"))
+	reader, err := gzip.NewReader(response.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "# TYPE openstack_test_server_status stateset\n")
+
+	response = serve(promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError, DisableCompression: true}, "gzip")
+	assert.Empty(t, response.Header().Get("Content-Encoding"))
+	assert.True(t, strings.HasSuffix(response.Body.String(), "# EOF\n"))
+
+	assert.Panics(t, func() { serve(promhttp.HandlerOpts{ErrorHandling: promhttp.PanicOnError}, "") })
+}
@@ -1,6 +1,7 @@
 package exporters
 
 import (
+	"context"
 	"log/slog"
 
 	"github.com/gophercloud/gophercloud"
@@ -82,13 +83,13 @@ func list(client *gophercloud.ServiceClient) pagination.Pager {
 }
 
 var defaultTroveMetrics = []Metric{
-	{Name: "total_instances", Fn: ListAllInstances},
-	{Name: "instance_status", Labels: []string{"datastore_type", "datastore_version", "health_status", "id", "name", "region", "status", "tenant_id"}, Fn: nil},
-	{Name: "instance_volume_size_gb", Labels: []string{"datastore_type", "datastore_version", "health_status", "id", "name", "region", "status", "tenant_id"}, Fn: nil},
-	{Name: "instance_volume_used_gb", Labels: []string{"datastore_type", "datastore_version", "health_status", "id", "name", "region", "status", "tenant_id"}, Fn: nil},
+	{Name: "total_instances", Fn: ListAllInstances, Help: "Total number of database instances", Type: prometheus.GaugeValue},
+	{Name: "instance_status", Labels: []string{"datastore_type", "datastore_version", "health_status", "id", "name", "region", "status", "tenant_id"}, Fn: nil, States: instance_status, Help: "Database instance status, the index of the status in the known instance statuses", Type: prometheus.GaugeValue},
+	{Name: "instance_volume_size_gb", Labels: []string{"datastore_type", "datastore_version", "health_status", "id", "name", "region", "status", "tenant_id"}, Fn: nil, Help: "Database instance volume size in GB", Type: prometheus.GaugeValue, Unit: "gigabytes"},
+	{Name: "instance_volume_used_gb", Labels: []string{"datastore_type", "datastore_version", "health_status", "id", "name", "region", "status", "tenant_id"}, Fn: nil, Help: "Database instance volume used in GB", Type: prometheus.GaugeValue, Unit: "gigabytes"},
 }
 
-func NewTroveExporter(config *ExporterConfig, logger *slog.Logger) (*TroveExporter, error) {
+func NewTroveExporter(ctx context.Context, config *ExporterConfig, logger *slog.Logger) (*TroveExporter, error) {
 	exporter := TroveExporter{
 		BaseOpenStackExporter{
 			Name:           "trove",
@@ -99,14 +100,14 @@ func NewTroveExporter(config *ExporterConfig, logger *slog.Logger) (*TroveExport
 
 	for _, metric := range defaultTroveMetrics {
 		if !exporter.isSlowMetric(&metric) {
-			exporter.AddMetric(metric.Name, metric.Fn, metric.Labels, metric.DeprecatedVersion, nil)
+			exporter.addMetric(metric, nil)
 		}
 	}
 
 	return &exporter, nil
 }
 
-func ListAllInstances(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+func ListAllInstances(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
 	var allInstances []instanceAttributesExt
 	allPagesInstances, err := list(exporter.Client).AllPages()
 	if err != nil {
@@ -117,16 +118,16 @@ func ListAllInstances(exporter *BaseOpenStackExporter, ch chan<- prometheus.Metr
 		return err
 	}
 	ch <- prometheus.MustNewConstMetric(exporter.Metrics["total_instances"].Metric,
-		prometheus.GaugeValue, float64(len(allInstances)))
+		exporter.Metrics["total_instances"].Type, float64(len(allInstances)))
 	for _, instance := range allInstances {
 		labelValues := []string{instance.Datastore.Type, instance.Datastore.Version,
 			instance.HealthStatus, instance.ID, instance.Name, instance.Region, instance.Status, instance.TenantID}
-		ch <- prometheus.MustNewConstMetric(exporter.Metrics["instance_status"].Metric,
-			prometheus.GaugeValue, float64(mapInstanceStatus(instance.Status)), labelValues...)
+		exporter.SendStatus(ch, "instance_status", instance_status, mapInstanceStatus(instance.Status), instance.Status,
+			labelValues...)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["instance_volume_size_gb"].Metric,
-			prometheus.GaugeValue, float64(instance.Volume.Size), labelValues...)
+			exporter.Metrics["instance_volume_size_gb"].Type, float64(instance.Volume.Size), labelValues...)
 		ch <- prometheus.MustNewConstMetric(exporter.Metrics["instance_volume_used_gb"].Metric,
-			prometheus.GaugeValue, instance.Volume.Used, labelValues...)
+			exporter.Metrics["instance_volume_used_gb"].Type, instance.Volume.Used, labelValues...)
 	}
 
 	return nil
@@ -12,19 +12,22 @@ type TroveTestSuite struct {
 }
 
 var troveExpectedUp = `
-# HELP openstack_trove_instance_status instance_status
+# HELP openstack_collector_success Whether the last collection of the metric from OpenStack API succeeded
+# TYPE openstack_collector_success gauge
+openstack_collector_success{metric="total_instances",service="trove"} 1
+# HELP openstack_trove_instance_status Database instance status, the index of the status in the known instance statuses
 # TYPE openstack_trove_instance_status gauge
 openstack_trove_instance_status{datastore_type="mysql",datastore_version="5.7",health_status="available",id="0cef87c6-bd23-4f6b-8458-a393c39486d8",name="mysql1",region="RegionOne",status="ACTIVE",tenant_id="0cbd49cbf76d405d9c86562e1d579bd3"} 2
-# HELP openstack_trove_instance_volume_size_gb instance_volume_size_gb
+# HELP openstack_trove_instance_volume_size_gb Database instance volume size in GB
 # TYPE openstack_trove_instance_volume_size_gb gauge
 openstack_trove_instance_volume_size_gb{datastore_type="mysql",datastore_version="5.7",health_status="available",id="0cef87c6-bd23-4f6b-8458-a393c39486d8",name="mysql1",region="RegionOne",status="ACTIVE",tenant_id="0cbd49cbf76d405d9c86562e1d579bd3"} 20
-# HELP openstack_trove_instance_volume_used_gb instance_volume_used_gb
+# HELP openstack_trove_instance_volume_used_gb Database instance volume used in GB
 # TYPE openstack_trove_instance_volume_used_gb gauge
 openstack_trove_instance_volume_used_gb{datastore_type="mysql",datastore_version="5.7",health_status="available",id="0cef87c6-bd23-4f6b-8458-a393c39486d8",name="mysql1",region="RegionOne",status="ACTIVE",tenant_id="0cbd49cbf76d405d9c86562e1d579bd3"} 0.4
-# HELP openstack_trove_total_instances total_instances
+# HELP openstack_trove_total_instances Total number of database instances
 # TYPE openstack_trove_total_instances gauge
 openstack_trove_total_instances 1
-# HELP openstack_trove_up up
+# HELP openstack_trove_up Whether the OpenStack API of the service is up (1=up, 0=down)
 # TYPE openstack_trove_up gauge
 openstack_trove_up 1
 `
@@ -0,0 +1,33 @@
+package exporters
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/proto"
+)
+
+// metricUnits are the units of the exporter metrics by fully-qualified name, as a prometheus.Desc has
+// no unit.
+var metricUnits sync.Map
+
+// setUnit records the unit of a metric, if it has one.
+func setUnit(fqName, unit string) {
+	if unit != "" {
+		metricUnits.Store(fqName, unit)
+	}
+}
+
+// WithUnits returns gatherer setting the unit of the gathered families of the exporter metrics.
+func WithUnits(gatherer prometheus.Gatherer) prometheus.Gatherer {
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		mfs, err := gatherer.Gather()
+		for _, mf := range mfs {
+			if unit, ok := metricUnits.Load(mf.GetName()); ok {
+				mf.Unit = proto.String(unit.(string))
+			}
+		}
+		return mfs, err
+	})
+}
@@ -0,0 +1,56 @@
+package exporters
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithUnits(t *testing.T) {
+	collectorErrors = make(map[collectorErrorKey]*collectorErrorStats)
+
+	exporter := &BaseOpenStackExporter{
+		Name: "test",
+		ExporterConfig: ExporterConfig{
+			Cloud:              "units",
+			Prefix:             "openstack",
+			CollectConcurrency: 1,
+		},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	exporter.addMetric(Metric{Name: "image_bytes", Labels: []string{"id"}, Help: "Image size in bytes", Unit: "bytes", Fn: func(ctx context.Context, exporter *BaseOpenStackExporter, ch chan<- prometheus.Metric) error {
+		ch <- prometheus.MustNewConstMetric(exporter.Metrics["image_bytes"].Metric, prometheus.GaugeValue, 1024, "a")
+		ch <- prometheus.MustNewConstMetric(exporter.Metrics["volume_gb"].Metric, prometheus.GaugeValue, 1, "a")
+		return nil
+	}}, nil)
+	exporter.addMetric(Metric{Name: "volume_gb", Labels: []string{"id"}, Help: "Volume size in GB", Unit: "gigabytes"}, nil)
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(exporter)
+	mfs, err := WithUnits(registry).Gather()
+	require.NoError(t, err)
+	units := map[string]string{}
+	for _, mf := range mfs {
+		units[mf.GetName()] = mf.GetUnit()
+	}
+	assert.Equal(t, "bytes", units["openstack_test_image_bytes"])
+	assert.Equal(t, "gigabytes", units["openstack_test_volume_gb"])
+	assert.Empty(t, units["openstack_test_up"])
+
+	request := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	request.Header.Set("Accept", "application/openmetrics-text;version=1.0.0")
+	response := httptest.NewRecorder()
+	HandlerFor(registry, promhttp.HandlerOpts{}, true).ServeHTTP(response, request)
+	body := response.Body.String()
+	assert.Contains(t, body, "# UNIT openstack_test_image_bytes bytes\n")
+	assert.NotContains(t, body, "# UNIT openstack_test_volume_gb", "the unit should not be written when the name doesn't end with it")
+	assert.Contains(t, body, "openstack_test_volume_gb{id=\"a\"} 1.0\n", "the name should be kept as is")
+}
@@ -21,7 +21,45 @@ import (
 	clientconfigv2 "github.com/gophercloud/utils/v2/openstack/clientconfig"
 )
 
+// transportWrappers wrap the transport of the provider clients, see WrapTransport.
+var transportWrappers []func(http.RoundTripper) http.RoundTripper
+
+// WrapTransport wraps the transport of the provider clients authenticated afterwards with wrap,
+// i.e: to record their requests. The wrappers apply in the order of the calls, the last one being
+// the outermost. It must be called before the exporters are enabled.
+func WrapTransport(wrap func(http.RoundTripper) http.RoundTripper) {
+	transportWrappers = append(transportWrappers, wrap)
+}
+
+// clientTransport returns the round tripper of the provider clients using transport, instrumented
+// by api if not nil, nil to keep the default transport of the clients.
+func clientTransport(transport *http.Transport, api *apiInstrumentation) http.RoundTripper {
+	var roundTripper http.RoundTripper
+	if transport != nil {
+		transport.Proxy = http.ProxyFromEnvironment
+		roundTripper = transport
+	}
+	if len(transportWrappers) == 0 && api == nil {
+		return roundTripper
+	}
+	if roundTripper == nil {
+		roundTripper = http.DefaultTransport
+	}
+	for _, wrap := range transportWrappers {
+		roundTripper = wrap(roundTripper)
+	}
+	if api != nil {
+		roundTripper = api.wrap(roundTripper)
+	}
+	return roundTripper
+}
+
 func AuthenticatedClient(opts *clientconfig.ClientOpts, transport *http.Transport) (*gophercloud.ProviderClient, error) {
+	return authenticatedClient(opts, transport, nil)
+}
+
+// authenticatedClient is AuthenticatedClient with its requests instrumented by api, if not nil.
+func authenticatedClient(opts *clientconfig.ClientOpts, transport *http.Transport, api *apiInstrumentation) (*gophercloud.ProviderClient, error) {
 	options, err := clientconfig.AuthOptions(opts)
 	if err != nil {
 		return nil, err
@@ -35,9 +73,9 @@ func AuthenticatedClient(opts *clientconfig.ClientOpts, transport *http.Transpor
 		return nil, err
 	}
 
-	if transport != nil {
-		transport.Proxy = http.ProxyFromEnvironment
-		client.HTTPClient.Transport = transport
+	api.addEndpoint(options.IdentityEndpoint, "identity", "")
+	if roundTripper := clientTransport(transport, api); roundTripper != nil {
+		client.HTTPClient.Transport = roundTripper
 	}
 
 	err = openstack.Authenticate(client, *options)
@@ -47,7 +85,12 @@ func AuthenticatedClient(opts *clientconfig.ClientOpts, transport *http.Transpor
 	return client, nil
 }
 
-func AuthenticatedClientV2(opts *clientconfigv2.ClientOpts, transport *http.Transport) (*gophercloudv2.ProviderClient, error) {
+func AuthenticatedClientV2(ctx context.Context, opts *clientconfigv2.ClientOpts, transport *http.Transport) (*gophercloudv2.ProviderClient, error) {
+	return authenticatedClientV2(ctx, opts, transport, nil)
+}
+
+// authenticatedClientV2 is AuthenticatedClientV2 with its requests instrumented by api, if not nil.
+func authenticatedClientV2(ctx context.Context, opts *clientconfigv2.ClientOpts, transport *http.Transport, api *apiInstrumentation) (*gophercloudv2.ProviderClient, error) {
 	options, err := clientconfigv2.AuthOptions(opts)
 	if err != nil {
 		return nil, err
@@ -61,20 +104,21 @@ func AuthenticatedClientV2(opts *clientconfigv2.ClientOpts, transport *http.Tran
 		return nil, err
 	}
 
-	if transport != nil {
-		transport.Proxy = http.ProxyFromEnvironment
-		client.HTTPClient.Transport = transport
+	api.addEndpoint(options.IdentityEndpoint, "identity", "")
+	if roundTripper := clientTransport(transport, api); roundTripper != nil {
+		client.HTTPClient.Transport = roundTripper
 	}
 
-	err = openstackv2.Authenticate(context.TODO(), client, *options)
+	err = openstackv2.Authenticate(ctx, client, *options)
 	if err != nil {
 		return nil, err
 	}
 	return client, nil
 }
 
-// NewServiceClient is a convenience function to get a new service client.
-func NewServiceClient(service string, opts *clientconfig.ClientOpts, transport *http.Transport, endpointType string) (*gophercloud.ServiceClient, error) {
+// NewServiceClient is a convenience function to get a new service client
+// from an authenticated provider client.
+func NewServiceClient(service string, opts *clientconfig.ClientOpts, pClient *gophercloud.ProviderClient, endpointType string) (*gophercloud.ServiceClient, error) {
 	cloud := new(clientconfig.Cloud)
 
 	// If no opts were passed in, create an empty ClientOpts.
@@ -110,12 +154,6 @@ func NewServiceClient(service string, opts *clientconfig.ClientOpts, transport *
 		}
 	}
 
-	// Get a Provider Client
-	pClient, err := AuthenticatedClient(opts, transport)
-	if err != nil {
-		return nil, err
-	}
-
 	// Determine the region to use.
 	// First, check if the REGION_NAME environment variable is set.
 	var region string
@@ -210,10 +248,18 @@ func NewServiceClient(service string, opts *clientconfig.ClientOpts, transport *
 		}
 	}
 
-	return nil, fmt.Errorf("unable to create a service client for %s", service)
+	// The services of the exporters registered by other packages get a client of their catalog endpoint.
+	eo.ApplyDefaults(service)
+	url, err := pClient.EndpointLocator(eo)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create a service client for %s: %w", service, err)
+	}
+	return &gophercloud.ServiceClient{ProviderClient: pClient, Endpoint: url, Type: service}, nil
 }
 
-func NewServiceClientV2(service string, opts *clientconfigv2.ClientOpts, transport *http.Transport, endpointType string) (*gophercloudv2.ServiceClient, error) {
+// NewServiceClientV2 is a convenience function to get a new service client
+// from an authenticated provider client.
+func NewServiceClientV2(service string, opts *clientconfigv2.ClientOpts, pClient *gophercloudv2.ProviderClient, endpointType string) (*gophercloudv2.ServiceClient, error) {
 	cloud := new(clientconfigv2.Cloud)
 
 	// If no opts were passed in, create an empty ClientOpts.
@@ -249,12 +295,6 @@ func NewServiceClientV2(service string, opts *clientconfigv2.ClientOpts, transpo
 		}
 	}
 
-	// Get a Provider Client
-	pClient, err := AuthenticatedClientV2(opts, transport)
-	if err != nil {
-		return nil, err
-	}
-
 	// Determine the region to use.
 	// First, check if the REGION_NAME environment variable is set.
 	var region string
@@ -347,7 +387,53 @@ func NewServiceClientV2(service string, opts *clientconfigv2.ClientOpts, transpo
 		}
 	}
 
-	return nil, fmt.Errorf("unable to create a service client for %s", service)
+	// The services of the exporters registered by other packages get a client of their catalog endpoint.
+	eo.ApplyDefaults(service)
+	url, err := pClient.EndpointLocator(eo)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create a service client for %s: %w", service, err)
+	}
+	return &gophercloudv2.ServiceClient{ProviderClient: pClient, Endpoint: url, Type: service}, nil
+}
+
+// serviceClientWithContext returns a copy of client whose requests are sent with ctx.
+// The copy starts with the token of the original provider client, re-authentication
+// goes through the original provider client, so the scrapes sharing it only request
+// one new token, before copying the new token back.
+//
+// NOTE: gophercloud v1 only supports a context per provider client, the
+// authentication itself in AuthenticatedClient therefore isn't bound to any context,
+// as the context would otherwise be kept for every later re-authentication.
+func serviceClientWithContext(ctx context.Context, client *gophercloud.ServiceClient) *gophercloud.ServiceClient {
+	original := client.ProviderClient
+	// The provider client is shared across scrapes, so it can't be copied as a whole
+	// while another scrape may be refreshing its token.
+	provider := &gophercloud.ProviderClient{
+		IdentityBase:      original.IdentityBase,
+		IdentityEndpoint:  original.IdentityEndpoint,
+		EndpointLocator:   original.EndpointLocator,
+		HTTPClient:        original.HTTPClient,
+		UserAgent:         original.UserAgent,
+		Context:           ctx,
+		RetryBackoffFunc:  original.RetryBackoffFunc,
+		MaxBackoffRetries: original.MaxBackoffRetries,
+		RetryFunc:         original.RetryFunc,
+	}
+	provider.UseTokenLock()
+	provider.CopyTokenFrom(original)
+	if original.ReauthFunc != nil {
+		provider.ReauthFunc = func() error {
+			if err := original.Reauthenticate(provider.Token()); err != nil {
+				return err
+			}
+			provider.CopyTokenFrom(original)
+			return nil
+		}
+	}
+
+	scoped := *client
+	scoped.ProviderClient = provider
+	return &scoped
 }
 
 // GetEndpointType return openstack endpoints for configured type
@@ -0,0 +1,237 @@
+// Package fakecloud serves a fake OpenStack cloud backed by the fixtures of the exporters, to run the
+// exporter end to end without a real cloud, i.e: for demos, integration and load tests.
+package fakecloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/openstack-exporter/openstack-exporter/internal/fixtures"
+	"gopkg.in/yaml.v3"
+)
+
+// fixtureURL is the URL of the test cloud in the fixtures, replaced by the URL of the fake cloud.
+const fixtureURL = "http://test.cloud"
+
+// Token is the token issued by the fake cloud.
+const Token = "fake-cloud-token"
+
+// Cloud is the handler of the fake cloud. It issues Keystone tokens, with the service catalog of
+// the fixtures, to any user and answers the GET requests of the exporters with their fixture.
+type Cloud struct {
+	url    string
+	logger *slog.Logger
+	// responses are the bodies of the fixtures, by request.
+	responses map[string][]byte
+	token     []byte
+}
+
+// New returns the fake cloud served at baseURL, i.e: http://127.0.0.1:5000. The lists of the
+// resources of counts, by the key of the list in the responses of the APIs (i.e: servers, ports),
+// are resized to the given number of resources, the fixture resources being copied with new IDs.
+func New(baseURL string, counts map[string]int, logger *slog.Logger) (*Cloud, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid fake cloud URL %q, must be a http or https URL", baseURL)
+	}
+	for resource, count := range counts {
+		if count < 0 {
+			return nil, fmt.Errorf("invalid count %d of %s, must be positive", count, resource)
+		}
+	}
+
+	c := &Cloud{url: strings.TrimSuffix(baseURL, "/"), logger: logger, responses: make(map[string][]byte)}
+	if c.token, err = c.fixture("tokens", nil); err != nil {
+		return nil, err
+	}
+	resized := make(map[string]bool)
+	for request, name := range fixtures.Requests {
+		body, err := c.fixture(name, func(resource string, items []any) []any {
+			count, ok := counts[resource]
+			if !ok {
+				return items
+			}
+			resized[resource] = true
+			return resize(items, count)
+		})
+		if err != nil {
+			return nil, err
+		}
+		c.responses[requestKey(request)] = body
+	}
+	for resource := range counts {
+		if !resized[resource] {
+			return nil, fmt.Errorf("unknown resource %s, no response of the fake cloud lists it", resource)
+		}
+	}
+	return c, nil
+}
+
+// fixture returns the body of a fixture, with the URLs of the cloud and its lists passed to fn if not nil.
+func (c *Cloud) fixture(name string, fn func(resource string, items []any) []any) ([]byte, error) {
+	data, err := fixtures.Read(name)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.ReplaceAll(data, []byte(fixtureURL), []byte(c.url))
+	if fn == nil {
+		return data, nil
+	}
+	return mapLists(data, fn)
+}
+
+// mapLists replaces the lists of objects of a response by the result of fn, called with the key
+// of the list in the response.
+func mapLists(data []byte, fn func(resource string, items []any) []any) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+	object, ok := document.(map[string]any)
+	if !ok {
+		return data, nil
+	}
+	changed := false
+	for key, value := range object {
+		if items, ok := value.([]any); ok && isObjectList(items) {
+			object[key] = fn(key, items)
+			changed = true
+		}
+	}
+	if !changed {
+		return data, nil
+	}
+	return json.Marshal(object)
+}
+
+func isObjectList(items []any) bool {
+	for _, item := range items {
+		if _, ok := item.(map[string]any); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// resize returns count resources, cycling through the items. The copies get new IDs and names.
+func resize(items []any, count int) []any {
+	if len(items) == 0 || count <= len(items) {
+		return items[:min(count, len(items))]
+	}
+	resized := make([]any, count)
+	for i := range resized {
+		if i < len(items) {
+			resized[i] = items[i]
+			continue
+		}
+		item := make(map[string]any)
+		for key, value := range items[i%len(items)].(map[string]any) {
+			item[key] = value
+		}
+		for _, key := range []string{"id", "uuid"} {
+			if id, ok := item[key].(string); ok {
+				item[key] = copyID(id, i)
+			}
+		}
+		if name, ok := item["name"].(string); ok {
+			item["name"] = fmt.Sprintf("%s-%d", name, i)
+		}
+		resized[i] = item
+	}
+	return resized
+}
+
+// copyID returns the ID of the i-th copy of a resource, keeping the format of the UUIDs.
+func copyID(id string, i int) string {
+	prefix := fmt.Sprintf("%08x", i)
+	if len(id) > len(prefix) {
+		return prefix + id[len(prefix):]
+	}
+	return fmt.Sprintf("%s-%d", id, i)
+}
+
+// requestKey returns the key of a request by its path and query, with the parameters sorted.
+func requestKey(requestURI string) string {
+	path, query, _ := strings.Cut(requestURI, "?")
+	values, _ := url.ParseQuery(query)
+	if len(values) == 0 {
+		return path
+	}
+	return path + "?" + values.Encode()
+}
+
+func (c *Cloud) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/v3/auth/tokens") {
+		w.Header().Set("X-Subject-Token", Token)
+		writeJSON(w, http.StatusCreated, c.token)
+		return
+	}
+
+	// The fixtures are single pages, the next pages are empty.
+	query := r.URL.Query()
+	marker := query.Get("marker")
+	query.Del("marker")
+	key := r.URL.Path
+	if len(query) > 0 {
+		key += "?" + query.Encode()
+	}
+	body, ok := c.responses[key]
+	if !ok {
+		// As the mocks of the tests, the requests without fixture for their query get the fixture of their path.
+		body, ok = c.responses[r.URL.Path]
+	}
+	if r.Method != http.MethodGet || !ok {
+		c.logger.Warn("Request unknown to the fake cloud", "method", r.Method, "url", r.URL.RequestURI())
+		writeJSON(w, http.StatusNotFound, []byte(`{"itemNotFound": {"code": 404, "message": "Unknown to the fake cloud"}}`))
+		return
+	}
+	if marker != "" {
+		body = emptyPage(body)
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+// emptyPage returns the response with its lists emptied.
+func emptyPage(body []byte) []byte {
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+		return []byte("[]")
+	}
+	empty, err := mapLists(body, func(string, []any) []any { return []any{} })
+	if err != nil {
+		return body
+	}
+	return empty
+}
+
+func writeJSON(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// CloudsYAML returns the clouds.yaml of the fake cloud, named name.
+func (c *Cloud) CloudsYAML(name string) ([]byte, error) {
+	return yaml.Marshal(map[string]any{
+		"clouds": map[string]any{
+			name: map[string]any{
+				"region_name":          "RegionOne",
+				"identity_api_version": 3,
+				"auth": map[string]any{
+					"auth_url":            c.url + "/v3",
+					"username":            "admin",
+					"password":            "admin",
+					"project_name":        "admin",
+					"project_domain_name": "Default",
+					"user_domain_name":    "Default",
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,95 @@
+package fakecloud
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openstack-exporter/openstack-exporter/exporters"
+	"github.com/openstack-exporter/openstack-exporter/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newServer serves a fake cloud, and points OS_CLIENT_CONFIG_FILE to its clouds.yaml.
+func newServer(t *testing.T, counts map[string]int) *httptest.Server {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	server := httptest.NewUnstartedServer(nil)
+	server.Start()
+	t.Cleanup(server.Close)
+
+	cloud, err := New(server.URL, counts, logger)
+	require.NoError(t, err)
+	server.Config.Handler = cloud
+
+	cloudsYAML, err := cloud.CloudsYAML("fake.cloud")
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "clouds.yaml")
+	require.NoError(t, os.WriteFile(path, cloudsYAML, 0o600))
+	t.Setenv("OS_CLIENT_CONFIG_FILE", path)
+	exporters.ResetExporters()
+	t.Cleanup(exporters.ResetExporters)
+	return server
+}
+
+func TestFakeCloud(t *testing.T) {
+	newServer(t, nil)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	ctx := context.Background()
+	registry := prometheus.NewPedanticRegistry()
+	for _, service := range exporters.Services() {
+		exp, err := exporters.EnableExporter(ctx, exporters.ExporterOptions{Service: service, Prefix: "openstack", Cloud: "fake.cloud", EndpointType: "public", LabelMappings: new(utils.ResourceLabelMappingFlag), CollectConcurrency: 4}, logger)
+		require.NoError(t, err, service)
+		registry.MustRegister(exporters.WithContext(ctx, *exp))
+	}
+	mfs, err := registry.Gather()
+	require.NoError(t, err)
+	assert.Empty(t, exporters.CollectionErrors(mfs, "openstack"), "the fake cloud should answer every request of the exporters")
+
+	for _, result := range exporters.CheckCloud(ctx, "fake.cloud", "public", exporters.Services(), logger) {
+		assert.NoError(t, result.Err, "the check of %s should pass", result.Service)
+	}
+}
+
+func TestResourceCounts(t *testing.T) {
+	server := newServer(t, map[string]int{"servers": 50, "networks": 1})
+
+	var body struct {
+		Servers []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"servers"`
+	}
+	resp, err := http.Get(server.URL + "/compute/servers/detail?all_tenants=true")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body.Servers, 50)
+	ids := make(map[string]bool)
+	for _, server := range body.Servers {
+		ids[server.ID] = true
+	}
+	assert.Len(t, ids, 50, "the copies of the servers should have their IDs")
+
+	resp, err = http.Get(server.URL + "/compute/servers/detail?all_tenants=true&marker=" + body.Servers[49].ID)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Empty(t, body.Servers, "the next pages should be empty")
+
+	resp, err = http.Get(server.URL + "/neutron/v2.0/unknown")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	_, err = New(server.URL, map[string]int{"unicorns": 3}, nil)
+	assert.ErrorContains(t, err, "unknown resource unicorns")
+}
@@ -0,0 +1,133 @@
+// Package fixtures holds the responses of the OpenStack APIs of the test cloud, used by the tests of
+// the exporters and served by the fake cloud.
+package fixtures
+
+import "embed"
+
+// FS holds the fixtures, named after their Requests without the .json extension.
+//
+//go:embed *.json
+var FS embed.FS
+
+// Requests maps the GET requests to the OpenStack APIs of the test cloud, by path and query, to the
+// name of their fixture in FS without the .json extension. The token of the test cloud is the tokens
+// fixture.
+var Requests = map[string]string{
+	"/container-infra/":              "container_infra_api_discovery",
+	"/container-infra/clusters":      "container_infra_clusters",
+	"/compute/":                      "nova_api_discovery",
+	"/compute/v2.1/":                 "nova_api_v2.1",
+	"/compute/os-services":           "nova_os_services",
+	"/compute/os-hypervisors/detail": "nova_os_hypervisors",
+	"/compute/flavors/detail":        "nova_os_flavors",
+	"/compute/os-availability-zone":  "nova_os_availability_zones",
+	"/compute/os-security-groups":    "nova_os_security_groups",
+	"/compute/os-aggregates":         "nova_os_aggregates",
+	"/compute/limits?tenant_id=0c4e939acacf4376bdcd1129f1a054ad": "nova_os_limits",
+	"/compute/limits?tenant_id=0cbd49cbf76d405d9c86562e1d579bd3": "nova_os_limits",
+	"/compute/limits?tenant_id=2db68fed84324f29bb73130c6c2094fb": "nova_os_limits",
+	"/compute/limits?tenant_id=3d594eb0f04741069dbbb521635b21c7": "nova_os_limits",
+	"/compute/limits?tenant_id=43ebde53fc314b1c9ea2b8c5dc744927": "nova_os_limits",
+	"/compute/limits?tenant_id=4b1eb781a47440acb8af9850103e537f": "nova_os_limits",
+	"/compute/limits?tenant_id=5961c443439d4fcebe42643723755e9d": "nova_os_limits",
+	"/compute/limits?tenant_id=fdb8424c4e4f4c0ba32c52e2de3bd80e": "nova_os_limits",
+	"/compute/servers/detail?all_tenants=true":                   "nova_os_servers",
+	"/compute/os-simple-tenant-usage?detailed=1":                 "nova_os_simple_tenant_usage",
+	"/glance/":          "glance_api_discovery",
+	"/glance/v2/images": "glance_images",
+	"/gnocchi/v1/metric?marker=5e9b3ee0-aee1-4461-8849-3f4ae5e30d8d": "gnocchi_metric",
+	"/gnocchi/v1/metric":                         "gnocchi_metric",
+	"/gnocchi/v1/status":                         "gnocchi_status",
+	"/gnocchi/v1/status?details=true":            "gnocchi_status",
+	"/identity/v3/projects":                      "identity_projects",
+	"/identity/v3/domains":                       "identity_domains",
+	"/identity/v3/users":                         "identity_users",
+	"/identity/v3/groups":                        "identity_groups",
+	"/identity/v3/regions":                       "identity_regions",
+	"/neutron/":                                  "neutron_api_discovery",
+	"/neutron/v2.0/floatingips":                  "neutron_floating_ips",
+	"/neutron/v2.0/agents":                       "neutron_agents",
+	"/neutron/v2.0/networks":                     "neutron_networks",
+	"/neutron/v2.0/security-groups":              "neutron_security_groups",
+	"/neutron/v2.0/subnets":                      "neutron_subnets",
+	"/neutron/v2.0/subnetpools":                  "neutron_subnet_pools",
+	"/neutron/v2.0/ports":                        "neutron_ports",
+	"/neutron/v2.0/network-ip-availabilities":    "neutron_network_ip_availabilities",
+	"/neutron/v2.0/routers":                      "neutron_routers",
+	"/neutron/v2.0/agents?binary=ovn-controller": "neutron_ovn_controller_agents",
+	"/neutron/v2.0/routers/f8a44de0-fc8e-45df-93c7-f79bf3b01c95/l3-agents": "neutron_routers_l3_agents",
+	"/neutron/v2.0/routers/9daeb7dd-7e3f-4e44-8c42-c7a0e8c8a42f/l3-agents": "neutron_routers_l3_agents",
+	"/loadbalancer/":                         "loadbalancer_api_discovery",
+	"/loadbalancer/v2.0/lbaas/loadbalancers": "loadbalancer_loadbalancers",
+	"/loadbalancer/v2.0/lbaas/loadbalancers/607226db-27ef-4d41-ae89-f2a800e9c2db/stats": "loadbalancer_stats",
+	"/loadbalancer/v2.0/octavia/amphorae":                                               "loadbalancer_amphorae",
+	"/loadbalancer/v2.0/lbaas/pools":                                                    "loadbalancer_pools",
+	"/ironic/":                                                                          "ironic_api_discovery",
+	"/ironic/v1":                                                                        "ironic_v1",
+	"/ironic/nodes":                                                                     "ironic_nodes",
+	"/ironic/nodes/detail":                                                              "ironic_nodes",
+	"/volumes":                                                                          "cinder_api_discovery",
+	"/volumes/":                                                                         "cinder_api_discovery",
+	"/volumes/volumes/detail?all_tenants=true":                                          "cinder_volumes",
+	"/volumes/snapshots":                                                                "cinder_snapshots",
+	"/volumes/os-services":                                                              "cinder_os_services",
+	"/volumes/scheduler-stats/get_pools?detail=true":                                    "cinder_scheduler_stats_pools",
+	"/volumes/os-quota-sets/0c4e939acacf4376bdcd1129f1a054ad?usage=true": "cinder_os_quota_sets_usage",
+	"/volumes/os-quota-sets/0cbd49cbf76d405d9c86562e1d579bd3?usage=true": "cinder_os_quota_sets_usage",
+	"/volumes/os-quota-sets/2db68fed84324f29bb73130c6c2094fb?usage=true": "cinder_os_quota_sets_usage",
+	"/volumes/os-quota-sets/3d594eb0f04741069dbbb521635b21c7?usage=true": "cinder_os_quota_sets_usage",
+	"/volumes/os-quota-sets/43ebde53fc314b1c9ea2b8c5dc744927?usage=true": "cinder_os_quota_sets_usage",
+	"/volumes/os-quota-sets/4b1eb781a47440acb8af9850103e537f?usage=true": "cinder_os_quota_sets_usage",
+	"/volumes/os-quota-sets/5961c443439d4fcebe42643723755e9d?usage=true": "cinder_os_quota_sets_usage",
+	"/volumes/os-quota-sets/fdb8424c4e4f4c0ba32c52e2de3bd80e?usage=true": "cinder_os_quota_sets_usage",
+	"/volumes/os-quota-sets/0c4e939acacf4376bdcd1129f1a054ad":            "cinder_os_quota_sets",
+	"/volumes/os-quota-sets/0cbd49cbf76d405d9c86562e1d579bd3":            "cinder_os_quota_sets",
+	"/volumes/os-quota-sets/2db68fed84324f29bb73130c6c2094fb":            "cinder_os_quota_sets",
+	"/volumes/os-quota-sets/3d594eb0f04741069dbbb521635b21c7":            "cinder_os_quota_sets",
+	"/volumes/os-quota-sets/43ebde53fc314b1c9ea2b8c5dc744927":            "cinder_os_quota_sets",
+	"/volumes/os-quota-sets/4b1eb781a47440acb8af9850103e537f":            "cinder_os_quota_sets",
+	"/volumes/os-quota-sets/5961c443439d4fcebe42643723755e9d":            "cinder_os_quota_sets",
+	"/volumes/os-quota-sets/fdb8424c4e4f4c0ba32c52e2de3bd80e":            "cinder_os_quota_sets",
+	"/designate/":         "designate_api_discovery",
+	"/designate/v2/zones": "designate_zones",
+	"/designate/v2/zones/a86dba58-0043-4cc6-a1bb-69d5e86f3ca3/recordsets": "designate_recordsets",
+	"/database/": "trove_api_discovery",
+	"/database/mgmt/instances?include_clustered=False&deleted=False": "trove_instances",
+	"/orchestration/":               "heat_api_discovery",
+	"/orchestration/stacks":         "heat_stacks",
+	"/placement/":                   "placement_api_discovery",
+	"/placement/resource_providers": "resource_providers",
+	"/placement/resource_providers/b985be15-99bf-4baf-9ef7-3ef166cd7f31/inventories": "resource_provider_1_inventory",
+	"/placement/resource_providers/328c9f0a-5a3c-4ad6-9347-689eb7632d7b/inventories": "resource_provider_2_inventory",
+	"/placement/resource_providers/b985be15-99bf-4baf-9ef7-3ef166cd7f31/usages":      "resource_provider_1_usage",
+	"/placement/resource_providers/328c9f0a-5a3c-4ad6-9347-689eb7632d7b/usages":      "resource_provider_2_usage",
+	"/compute/os-quota-sets/0c4e939acacf4376bdcd1129f1a054ad/detail":                 "nova_quotas_1_usage",
+	"/compute/os-quota-sets/0cbd49cbf76d405d9c86562e1d579bd3/detail":                 "nova_quotas_1_usage",
+	"/compute/os-quota-sets/2db68fed84324f29bb73130c6c2094fb/detail":                 "nova_quotas_1_usage",
+	"/compute/os-quota-sets/3d594eb0f04741069dbbb521635b21c7/detail":                 "nova_quotas_1_usage",
+	"/compute/os-quota-sets/43ebde53fc314b1c9ea2b8c5dc744927/detail":                 "nova_quotas_1_usage",
+	"/compute/os-quota-sets/5961c443439d4fcebe42643723755e9d/detail":                 "nova_quotas_1_usage",
+	"/compute/os-quota-sets/fdb8424c4e4f4c0ba32c52e2de3bd80e/detail":                 "nova_quotas_1_usage",
+	"/compute/os-quota-sets/4b1eb781a47440acb8af9850103e537f/detail":                 "nova_quotas_1_usage",
+	"/neutron/v2.0/quotas/0c4e939acacf4376bdcd1129f1a054ad/details.json":             "neutron_quotas_1_usage",
+	"/neutron/v2.0/quotas/0cbd49cbf76d405d9c86562e1d579bd3/details.json":             "neutron_quotas_1_usage",
+	"/neutron/v2.0/quotas/2db68fed84324f29bb73130c6c2094fb/details.json":             "neutron_quotas_1_usage",
+	"/neutron/v2.0/quotas/3d594eb0f04741069dbbb521635b21c7/details.json":             "neutron_quotas_1_usage",
+	"/neutron/v2.0/quotas/43ebde53fc314b1c9ea2b8c5dc744927/details.json":             "neutron_quotas_1_usage",
+	"/neutron/v2.0/quotas/5961c443439d4fcebe42643723755e9d/details.json":             "neutron_quotas_1_usage",
+	"/neutron/v2.0/quotas/fdb8424c4e4f4c0ba32c52e2de3bd80e/details.json":             "neutron_quotas_1_usage",
+	"/neutron/v2.0/quotas/4b1eb781a47440acb8af9850103e537f/details.json":             "neutron_quotas_1_usage",
+	"/object-store/":                            "object_store_list_containers",
+	"/object-store/?marker=container":           "object_store_list_containers_end",
+	"/shares/v2/shares/detail?all_tenants=true": "manila_shares",
+
+	// The requests of the service checks, see exporters.CheckCloud.
+	"/volumes/volumes":    "cinder_volumes",
+	"/database/instances": "trove_instances",
+	"/shares/v2/shares":   "manila_shares",
+}
+
+// Read returns the content of a fixture given by its name without the .json extension.
+func Read(name string) ([]byte, error) {
+	return FS.ReadFile(name + ".json")
+}
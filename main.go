@@ -1,12 +1,19 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -15,13 +22,20 @@ import (
 	"log/slog"
 
 	kingpin "github.com/alecthomas/kingpin/v2"
+	"github.com/gophercloud/utils/openstack/clientconfig"
 	"github.com/hashicorp/vault-client-go"
 	"github.com/hashicorp/vault-client-go/schema"
 	"github.com/openstack-exporter/openstack-exporter/cache"
+	"github.com/openstack-exporter/openstack-exporter/config"
 	"github.com/openstack-exporter/openstack-exporter/exporters"
+	"github.com/openstack-exporter/openstack-exporter/fakecloud"
+	"github.com/openstack-exporter/openstack-exporter/otlp"
+	"github.com/openstack-exporter/openstack-exporter/recording"
+	"github.com/openstack-exporter/openstack-exporter/remotewrite"
 	"github.com/openstack-exporter/openstack-exporter/utils"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/common/expfmt"
 	"github.com/prometheus/common/promslog"
 	"github.com/prometheus/common/promslog/flag"
 	"github.com/prometheus/common/version"
@@ -29,10 +43,27 @@ import (
 	webflag "github.com/prometheus/exporter-toolkit/web/kingpinflag"
 )
 
-var defaultEnabledServices = []string{"network", "compute", "image", "volume", "identity", "object-store", "load-balancer", "container-infra", "dns", "baremetal", "gnocchi", "database", "orchestration", "placement", "sharev2"}
-
 var DEFAULT_OS_CLIENT_CONFIG = "/etc/openstack/clouds.yaml"
 
+var (
+	serveCommand        = kingpin.Command("serve", "Serve the metrics of the cloud over HTTP").Default()
+	metricsCommand      = kingpin.Command("metrics", "Describe the metrics of the exporter")
+	catalogCommand      = metricsCommand.Command("catalog", "Print the metrics of every registered service exporter")
+	catalogFormat       = catalogCommand.Flag("format", "Format of the catalog (json or markdown)").Default(exporters.CatalogJSON).Enum(exporters.CatalogJSON, exporters.CatalogMarkdown)
+	checkCommand        = kingpin.Command("check", "Check the authentication to a cloud and the access to its enabled services, then exit")
+	checkCloud          = checkCommand.Flag("cloud", "Name or id of the cloud to check").Required().String()
+	scrapeCommand       = kingpin.Command("scrape", "Collect the metrics of a cloud once and write them in the text format, then exit with an error status if a collection failed")
+	scrapeCloud         = scrapeCommand.Flag("cloud", "Name or id of the cloud to scrape").Required().String()
+	scrapeServices      = scrapeCommand.Flag("service", "Only collect the given services, by service type or exporter name, multiple --service can be specified (i.e: nova,neutron)").Strings()
+	scrapeOutput        = scrapeCommand.Flag("output", "Write the metrics atomically to the given file (i.e: a file of the node_exporter textfile collector directory) instead of stdout").String()
+	fakeCloudCommand    = kingpin.Command("fake-cloud", "Serve a fake OpenStack cloud answering with the fixtures of the tests, and print its clouds.yaml, for demos and integration tests")
+	fakeCloudListen     = fakeCloudCommand.Flag("listen-address", "Address on which the fake cloud listens").Default("127.0.0.1:5000").String()
+	fakeCloudURL        = fakeCloudCommand.Flag("external-url", "URL of the fake cloud for the exporter, in its catalog and clouds.yaml (defaults to http://<listen-address>)").String()
+	fakeCloudName       = fakeCloudCommand.Flag("name", "Name of the fake cloud in its clouds.yaml").Default("fake").String()
+	fakeCloudCloudsFile = fakeCloudCommand.Flag("clouds-file", "Write the clouds.yaml of the fake cloud to the given file instead of stdout").String()
+	fakeCloudCounts     = fakeCloudCommand.Flag("count", "Number of resources listed by the fake cloud, by key of the list in the API responses, multiple --count can be specified (i.e: servers=1000)").PlaceHolder("RESOURCE=COUNT").StringMap()
+)
+
 var (
 	metrics                  = kingpin.Flag("web.telemetry-path", "uri path to expose metrics").Default("/metrics").String()
 	osClientConfig           = kingpin.Flag("os-client-config", "Path to the cloud configuration file").Default(DEFAULT_OS_CLIENT_CONFIG).String()
@@ -43,20 +74,70 @@ var (
 	disableSlowMetrics       = kingpin.Flag("disable-slow-metrics", "Disable slow metrics for performance reasons").Default("false").Bool()
 	disableDeprecatedMetrics = kingpin.Flag("disable-deprecated-metrics", "Disable deprecated metrics").Default("false").Bool()
 	disableCinderAgentUUID   = kingpin.Flag("disable-cinder-agent-uuid", "Disable UUID generation for Cinder agents").Default("false").Bool()
-	cloud                    = kingpin.Arg("cloud", "name or id of the cloud to gather metrics from").String()
+	cloud                    = serveCommand.Arg("cloud", "name or id of the cloud to gather metrics from").String()
 	multiCloud               = kingpin.Flag("multi-cloud", "Toggle the multiple cloud scraping mode under /probe?cloud=").Default("false").Bool()
 	domainID                 = kingpin.Flag("domain-id", "Gather metrics only for the given Domain ID (defaults to all domains)").String()
 	cacheEnable              = kingpin.Flag("cache", "Enable Cache mechanism globally").Default("false").Bool()
 	cacheTTL                 = kingpin.Flag("cache-ttl", "TTL duration for cache expiry(eg. 10s, 11m, 1h)").Default("300s").Duration()
 	tenantID                 = kingpin.Flag("tenant-id", "Gather metrics only for the given Tenant ID (default to all tenants)").String()
+	collectConcurrency       = kingpin.Flag("collect.concurrency", "Maximum number of metrics collected concurrently by each service exporter").Default("4").Int()
+	cloudCollectConcurrency  = kingpin.Flag("collect.cloud-concurrency", "Maximum number of metrics collected concurrently across all service exporters of a cloud (0 means no limit)").Default("0").Int()
+	scrapeTimeoutOffset      = kingpin.Flag("scrape-timeout-offset", "Offset to subtract from the timeout advertised by Prometheus in the X-Prometheus-Scrape-Timeout-Seconds header").Default("500ms").Duration()
 	novaMetadataMapping      = utils.LabelMapping(kingpin.Flag("nova.metadata-extra-labels", "Map provided server metadata keys to labels in openstack_nova_server_status metric").PlaceHolder("LABEL=KEY,KEY").Default(""))
+	labelMappings            = utils.ResourceLabelMapping(kingpin.Flag("extra-labels", "Map provided metadata, property or tag keys of a resource type to labels in its metrics, multiple --extra-labels can be specified (i.e: cinder.volume:cost_center=cost-center,owner)").PlaceHolder("RESOURCE:LABEL=KEY,KEY"))
+	configFile               = kingpin.Flag("config.file", "Path to the exporter configuration file, overriding the collection options globally and per cloud").String()
+	regionLabel              = kingpin.Flag("region-label", "Add the region of the cloud as a region label to every metric").Default("false").Bool()
+	multiRegion              = kingpin.Flag("multi-region", "Discover the regions from the Keystone catalog and collect every service in each region, with a region label").Default("false").Bool()
+	metricAllow              = kingpin.Flag("metric.allow", "Only export the metrics whose name matches one of the given regular expressions (i.e: openstack_neutron_.*)").Strings()
+	metricDeny               = kingpin.Flag("metric.deny", "Do not export the metrics whose name matches one of the given regular expressions (i.e: openstack_neutron_port)").Strings()
+	metricSeriesLimit        = kingpin.Flag("metric.series-limit", "Do not export the metrics having a series per resource with more series than the given limit during a collection, 0 means no limit").Default("0").Int()
+	statusMetrics            = kingpin.Flag("status-metrics", "Send the status metrics as the index of the status (index), or as one series per status (stateset), with the StateSet type when OpenMetrics is negotiated").Default(config.StatusMetricsIndex).Enum(config.StatusMetricsIndex, config.StatusMetricsStateSet)
+	enableLifecycle          = kingpin.Flag("web.enable-lifecycle", "Enable the reload of the configuration via HTTP POST requests to /-/reload").Default("false").Bool()
+	pushURL                  = kingpin.Flag("push.url", "Push the metrics of the clouds to the given Pushgateway after each collection of the cache background service, grouped by cloud and service (i.e: http://pushgateway:9091)").String()
+	pushJob                  = kingpin.Flag("push.job", "Job name of the metrics pushed to the Pushgateway").Default("openstack_exporter").String()
+	pushUsername             = kingpin.Flag("push.username", "Username of the basic authentication to the Pushgateway").String()
+	pushPasswordFile         = kingpin.Flag("push.password-file", "Path to the file containing the password of the basic authentication to the Pushgateway").String()
+	pushCAFile               = kingpin.Flag("push.tls.ca-file", "Path to the CA certificate verifying the certificate of the Pushgateway").String()
+	pushCertFile             = kingpin.Flag("push.tls.cert-file", "Path to the client certificate sent to the Pushgateway").String()
+	pushKeyFile              = kingpin.Flag("push.tls.key-file", "Path to the key of the client certificate sent to the Pushgateway").String()
+	pushInsecureSkipVerify   = kingpin.Flag("push.tls.insecure-skip-verify", "Do not verify the certificate of the Pushgateway").Default("false").Bool()
+	otlpEndpoint             = kingpin.Flag("otlp.endpoint", "Send the metrics of the clouds collected by the cache background service to the given OpenTelemetry collector, https enables TLS (i.e: http://otel-collector:4318)").String()
+	otlpProtocol             = kingpin.Flag("otlp.protocol", "Protocol of the OpenTelemetry collector (http/protobuf or grpc)").Default(otlp.ProtocolHTTP).Enum(otlp.ProtocolHTTP, otlp.ProtocolGRPC)
+	otlpInterval             = kingpin.Flag("otlp.interval", "Interval between the sends of the metrics to the OpenTelemetry collector").Default("60s").Duration()
+	remoteWriteURL           = kingpin.Flag("remote-write.url", "Send the metrics of the clouds to the given Prometheus remote write endpoint after each collection of the cache background service (i.e: http://mimir:9009/api/v1/push)").String()
+	remoteWriteUsername      = kingpin.Flag("remote-write.username", "Username of the basic authentication to the remote write endpoint").String()
+	remoteWritePasswordFile  = kingpin.Flag("remote-write.password-file", "Path to the file containing the password of the basic authentication to the remote write endpoint").String()
+	remoteWriteLabels        = utils.ExternalLabels(kingpin.Flag("remote-write.external-label", "Add the given label to the series sent by remote write, multiple --remote-write.external-label can be specified (i.e: datacenter=dc1)").PlaceHolder("NAME=VALUE"))
+	remoteWriteBatchSize     = kingpin.Flag("remote-write.batch-size", "Maximum number of samples sent by a remote write request").Default("500").Int()
+	remoteWriteQueueSize     = kingpin.Flag("remote-write.queue-size", "Maximum number of samples waiting to be sent by remote write, the oldest ones are dropped when the queue is full").Default("100000").Int()
+	remoteWriteMaxRetries    = kingpin.Flag("remote-write.max-retries", "Number of retries of a remote write request failing with a server error or a rate limit, before its samples are dropped").Default("10").Int()
+	remoteWriteMinBackoff    = kingpin.Flag("remote-write.min-backoff", "Initial backoff before retrying a remote write request, doubled on each retry").Default("30ms").Duration()
+	remoteWriteMaxBackoff    = kingpin.Flag("remote-write.max-backoff", "Maximum backoff before retrying a remote write request").Default("5s").Duration()
+	recordDir                = kingpin.Flag("record-dir", "Record the requests to the OpenStack APIs and their responses in the given directory, with the tokens and passwords redacted").String()
+	replayDir                = kingpin.Flag("replay-dir", "Answer the requests to the OpenStack APIs with the responses recorded by --record-dir in the given directory, without reaching the clouds").String()
+	apiMaxRetries            = kingpin.Flag("api.max-retries", "Number of retries of a GET request to the OpenStack APIs failing with a server error or a rate limit").Default("2").Int()
+	apiMinBackoff            = kingpin.Flag("api.min-backoff", "Initial backoff before retrying a request to the OpenStack APIs, doubled on each retry with jitter").Default("100ms").Duration()
+	apiMaxBackoff            = kingpin.Flag("api.max-backoff", "Maximum backoff before retrying a request to the OpenStack APIs, the requests whose Retry-After exceeds it are not retried").Default("5s").Duration()
+	apiBreakerFailures       = kingpin.Flag("api.circuit-breaker.failures", "Number of consecutive failed requests to the OpenStack API of a service opening its circuit breaker, failing the requests fast, 0 to disable the circuit breakers").Default("5").Int()
+	apiBreakerTimeout        = kingpin.Flag("api.circuit-breaker.timeout", "Time a circuit breaker stays open before probing the OpenStack API of the service again").Default("30s").Duration()
 )
 
+// exporterConfig is the content of --config.file, nil if not set. It's replaced on reload.
+var exporterConfig atomic.Pointer[config.File]
+
+// metricFilter holds the patterns of --metric.allow and --metric.deny, and --metric.series-limit.
+var metricFilter = &utils.MetricFilter{}
+
+// apiRegistry holds the metrics of the requests to the OpenStack APIs, served with the metrics of the exporters.
+var apiRegistry = prometheus.NewRegistry()
+
 func main() {
 
 	services := make(map[string]*bool)
 
-	for _, service := range defaultEnabledServices {
+	// The services are the ones registered in the exporters package, including the exporters
+	// registered by the packages imported for their side effects.
+	for _, service := range exporters.Services() {
 		flagName := fmt.Sprintf("disable-service.%s", service)
 		flagHelp := fmt.Sprintf("Disable the %s service exporter", service)
 		services[service] = kingpin.Flag(flagName, flagHelp).Default().Bool()
@@ -67,11 +148,28 @@ func main() {
 	flag.AddFlags(kingpin.CommandLine, promlogConfig)
 	kingpin.Version(version.Print("openstack-exporter"))
 	kingpin.HelpFlag.Short('h')
-	kingpin.Parse()
+	command := kingpin.Parse()
+
+	if command == catalogCommand.FullCommand() {
+		if err := exporters.WriteCatalog(os.Stdout, *catalogFormat, exporters.Catalog(*prefix)); err != nil {
+			fmt.Fprintln(os.Stderr, "Could not write the metric catalog:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	logger := promslog.New(promlogConfig)
 	logger.Info("Build Version", "version_info", version.Info(), "build_context", version.BuildContext())
 
-	if *cloud == "" && !*multiCloud {
+	if command == fakeCloudCommand.FullCommand() {
+		if err := runFakeCloud(logger); err != nil {
+			logger.Error("Fake cloud failed", "err", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if command == serveCommand.FullCommand() && *cloud == "" && !*multiCloud {
 		logger.Error("openstack-exporter: error: required argument 'cloud' or flag --multi-cloud not provided, try --help")
 	}
 
@@ -80,53 +178,319 @@ func main() {
 		os.Setenv("OS_CLIENT_CONFIG_FILE", *osClientConfig)
 	}
 
-	SetPasswordIfVaultIsUsed(logger)
+	// --nova.metadata-extra-labels predates --extra-labels, it maps the metadata of the servers.
+	if err := labelMappings.Set("nova.server:" + novaMetadataMapping.String()); err != nil {
+		logger.Error("Invalid --nova.metadata-extra-labels", "error", err)
+		os.Exit(1)
+	}
+
+	var err error
+	if metricFilter.Allow, err = utils.CompilePatterns(*metricAllow); err != nil {
+		logger.Error("Invalid --metric.allow pattern", "error", err)
+		os.Exit(1)
+	}
+	if metricFilter.Deny, err = utils.CompilePatterns(*metricDeny); err != nil {
+		logger.Error("Invalid --metric.deny pattern", "error", err)
+		os.Exit(1)
+	}
+	if *metricSeriesLimit < 0 {
+		logger.Error("Invalid --metric.series-limit, must be positive", "limit", *metricSeriesLimit)
+		os.Exit(1)
+	}
+	metricFilter.SeriesLimit = *metricSeriesLimit
+
+	if *recordDir != "" && *replayDir != "" {
+		logger.Error("--record-dir and --replay-dir are mutually exclusive")
+		os.Exit(1)
+	}
+	if *recordDir != "" {
+		recorder, err := recording.NewRecorder(*recordDir, logger)
+		if err != nil {
+			logger.Error("Invalid --record-dir", "error", err)
+			os.Exit(1)
+		}
+		exporters.WrapTransport(recorder.Wrap)
+		logger.Warn("Recording the requests to the OpenStack APIs, the recordings may contain sensitive data", "dir", *recordDir)
+	}
+	if *replayDir != "" {
+		replayer, err := recording.NewReplayer(*replayDir)
+		if err != nil {
+			logger.Error("Invalid --replay-dir", "error", err)
+			os.Exit(1)
+		}
+		exporters.WrapTransport(replayer.Wrap)
+		logger.Info("Replaying the recorded responses of the OpenStack APIs", "dir", *replayDir)
+	}
+	if err := exporters.ConfigureAPIRequests(exporters.APIRequestConfig{
+		MaxRetries:      *apiMaxRetries,
+		MinBackoff:      *apiMinBackoff,
+		MaxBackoff:      *apiMaxBackoff,
+		BreakerFailures: *apiBreakerFailures,
+		BreakerTimeout:  *apiBreakerTimeout,
+	}); err != nil {
+		logger.Error("Invalid OpenStack API request configuration", "error", err)
+		os.Exit(1)
+	}
+	apiMetrics := exporters.InstrumentAPIRequests(*prefix)
+	apiRegistry.MustRegister(apiMetrics)
+	if *multiCloud {
+		prometheus.MustRegister(apiMetrics)
+	}
+
+	if err := SetPasswordIfVaultIsUsed(logger); err != nil {
+		logger.Error("Could not set the password from Vault", "error", err)
+		os.Exit(1)
+	}
 
 	if _, err := os.Stat(*osClientConfig); err != nil {
 		logger.Error("Could not read config file", "error", err)
 		os.Exit(1)
 	}
 
+	if *configFile != "" {
+		cfg, err := loadExporterConfig(*configFile)
+		if err != nil {
+			logger.Error("Could not load exporter config file", "error", err)
+			os.Exit(1)
+		}
+		exporterConfig.Store(cfg)
+	}
+
+	if command == checkCommand.FullCommand() {
+		if !runCheck(*checkCloud, services, logger) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if command == scrapeCommand.FullCommand() {
+		if !runScrape(*scrapeCloud, services, logger) {
+			os.Exit(1)
+		}
+		return
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	errChan := make(chan error, 1)
 
-	// Start the backend service.
-	if *cacheEnable {
-		go cacheBackgroundService(ctx, services, errChan, logger)
+	var pushConfig *cache.PushConfig
+	if *pushURL != "" {
+		if pushConfig, err = newPushConfig(); err != nil {
+			logger.Error("Invalid Pushgateway configuration", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	var otlpExporter *otlp.Exporter
+	if *otlpEndpoint != "" {
+		if otlpExporter, err = otlp.NewExporter(*otlpEndpoint, *otlpProtocol); err != nil {
+			logger.Error("Invalid OpenTelemetry collector configuration", "error", err)
+			os.Exit(1)
+		}
+		defer otlpExporter.Close()
+	}
+
+	var remoteWriter *remotewrite.Client
+	if *remoteWriteURL != "" {
+		if remoteWriter, err = newRemoteWriter(logger); err != nil {
+			logger.Error("Invalid remote write configuration", "error", err)
+			os.Exit(1)
+		}
+		go remoteWriter.Run(ctx)
+	}
+
+	// Start the backend service, which also feeds the pushes, the remote write and the OpenTelemetry collector.
+	if *cacheEnable || pushConfig != nil || remoteWriter != nil || otlpExporter != nil {
+		go cacheBackgroundService(ctx, services, afterCollect(services, pushConfig, remoteWriter, logger), errChan, logger)
+	}
+	if otlpExporter != nil {
+		go otlpService(ctx, services, otlpExporter, logger)
 	}
 
 	// Start the HTTP server.
 	go startHTTPServer(ctx, services, toolkitFlags, errChan, logger)
 
-	// Wait for an error from any service or a termination signal.
+	// Wait for an error from any service or a termination signal, reload on SIGHUP.
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+
+	for {
+		select {
+		case err := <-errChan:
+			logger.Error("Shutting down due to error", "err", err)
+			cancel()
+			return
+		case <-sigChan:
+			logger.Info("Termination signal received. Shutting down...")
+			cancel()
+			return
+		case <-hupChan:
+			if err := reload(logger); err != nil {
+				logger.Error("Failed to reload the configuration", "err", err)
+			}
+		}
+	}
+}
+
+// runCheck checks the cloud and its enabled services, printing the results on stdout.
+// It returns false if a check failed.
+func runCheck(cloud string, services map[string]*bool, logger *slog.Logger) bool {
+	options := cloudOptions(services)(cloud)
+	results := exporters.CheckCloud(context.Background(), cloud, options.EndpointType, options.EnabledServices, logger)
+	passed, err := exporters.WriteCheckResults(os.Stdout, results)
+	if err != nil {
+		logger.Error("Could not write the check results", "err", err)
+		return false
+	}
+	return passed
+}
+
+// runFakeCloud serves the fake cloud until it fails, after writing its clouds.yaml.
+func runFakeCloud(logger *slog.Logger) error {
+	counts := make(map[string]int)
+	for resource, value := range *fakeCloudCounts {
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid --count of %s: %w", resource, err)
+		}
+		counts[resource] = count
+	}
+
+	listener, err := net.Listen("tcp", *fakeCloudListen)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+	baseURL := *fakeCloudURL
+	if baseURL == "" {
+		baseURL = "http://" + listener.Addr().String()
+	}
+	fakeCloud, err := fakecloud.New(baseURL, counts, logger)
+	if err != nil {
+		return err
+	}
 
-	select {
-	case err := <-errChan:
-		logger.Error("Shutting down due to error", "err", err)
-		cancel()
-	case <-sigChan:
-		logger.Info("Termination signal received. Shutting down...")
-		cancel()
+	cloudsYAML, err := fakeCloud.CloudsYAML(*fakeCloudName)
+	if err != nil {
+		return err
+	}
+	if *fakeCloudCloudsFile != "" {
+		if err := utils.WriteFileAtomic(*fakeCloudCloudsFile, cloudsYAML, 0o644); err != nil {
+			return err
+		}
+	} else if _, err := os.Stdout.Write(cloudsYAML); err != nil {
+		return err
+	}
+
+	logger.Info("Serving the fake cloud", "url", baseURL, "cloud", *fakeCloudName)
+	return http.Serve(listener, fakeCloud)
+}
+
+// runScrape collects the metrics of the cloud once, and writes them to --output or stdout.
+// It returns false if an exporter could not be enabled or a metric collection failed.
+func runScrape(cloud string, services map[string]*bool, logger *slog.Logger) bool {
+	options := cloudOptions(services)(cloud)
+	enabledServices := options.EnabledServices
+	if len(*scrapeServices) > 0 {
+		enabledServices = []string{}
+		for _, value := range *scrapeServices {
+			for _, service := range strings.Split(value, ",") {
+				serviceType, ok := exporters.ServiceType(service)
+				if !ok {
+					logger.Error("Unknown service, see the --disable-service flags", "service", service)
+					return false
+				}
+				enabledServices = append(enabledServices, serviceType)
+			}
+		}
+	}
+
+	// The exporters of the scrape share the resources they list.
+	ctx := exporters.WithSnapshot(context.Background())
+	exporters.RefreshClouds(logger)
+	regions, err := exporters.CollectedRegions(ctx, cloud, options.MultiRegion, options.RegionLabel, options.EndpointType, logger)
+	if err != nil {
+		logger.Error("Listing the regions failed", "cloud", cloud, "error", err)
+		return false
+	}
+
+	succeeded := true
+	gatherers := prometheus.Gatherers{}
+	for _, region := range regions {
+		registry := prometheus.NewPedanticRegistry()
+		for _, service := range enabledServices {
+			exp, err := exporters.EnableExporter(ctx, options.ExporterOptions(exporterDefaults(), service, cloud, region), logger)
+			if err != nil {
+				logger.Error("Enabling exporter for service failed", "service", service, "region", region, "error", err)
+				succeeded = false
+				continue
+			}
+			registry.MustRegister(exporters.WithContext(ctx, *exp))
+		}
+		gatherers = append(gatherers, registry)
+	}
+	gatherers = append(gatherers, apiRegistry)
+
+	// The metrics gathered despite an error are written all the same.
+	mfs, err := gatherers.Gather()
+	if err != nil {
+		logger.Error("Gathering the metrics failed", "error", err)
+		succeeded = false
+	}
+	for _, failure := range exporters.CollectionErrors(mfs, *prefix) {
+		logger.Error("Metric collection failed", "metric", failure)
+		succeeded = false
 	}
 
+	var buf bytes.Buffer
+	for _, mf := range mfs {
+		if _, err := expfmt.MetricFamilyToText(&buf, mf); err != nil {
+			logger.Error("Encoding the metrics failed", "error", err)
+			return false
+		}
+	}
+	if *scrapeOutput == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+	} else {
+		err = utils.WriteFileAtomic(*scrapeOutput, buf.Bytes(), 0o644)
+	}
+	if err != nil {
+		logger.Error("Writing the metrics failed", "error", err)
+		return false
+	}
+	return succeeded
 }
 
 // cacheBackgroundService runs a background service to collect the metrics and stores in the cache.
 // It collects data every cache-ttl/2 time and flush every cache-ttl time.
-// The cache data will be read by the Prometheus HandleFunc.
-func cacheBackgroundService(ctx context.Context, services map[string]*bool, errChan chan<- error, logger *slog.Logger) {
+// The cache data will be read by the Prometheus HandleFunc, and by afterCollect after each collection.
+func cacheBackgroundService(ctx context.Context, services map[string]*bool, afterCollect func(context.Context), errChan chan<- error, logger *slog.Logger) {
 	logger.Info("Start cache background service")
-	collectTicker := time.NewTicker(*cacheTTL / 2)
+	// The clouds can have their own cache TTL, the shortest one sets the pace.
+	ttl := exporterConfig.Load().MinCacheTTL(defaultOptions(services))
+	collectTicker := time.NewTicker(ttl / 2)
 	defer collectTicker.Stop()
-	ttlTicker := time.NewTicker(*cacheTTL)
+	ttlTicker := time.NewTicker(ttl)
 	defer ttlTicker.Stop()
 
+	collect := func() error {
+		if err := cache.CollectCache(ctx, exporters.EnableExporter, *multiCloud, *cloud, cloudOptions(services), ttl/2, exporterDefaults(), logger); err != nil {
+			return err
+		}
+		afterCollect(ctx)
+		return nil
+	}
+
 	// Collect cache data in the beginning.
-	if err := cache.CollectCache(exporters.EnableExporter, *multiCloud, services, *prefix, *cloud, *disabledMetrics, *endpointType, *collectTime, *disableSlowMetrics, *disableDeprecatedMetrics, *disableCinderAgentUUID, *domainID, *tenantID, novaMetadataMapping, nil, logger); err != nil {
+	if err := collect(); err != nil {
+		if ctx.Err() != nil {
+			logger.Info("Backend service is stopping")
+			return
+		}
 		logger.Error("Failed to collect from cache", "err", err)
 		errChan <- err
 		return
@@ -135,13 +499,31 @@ func cacheBackgroundService(ctx context.Context, services map[string]*bool, errC
 	for {
 		select {
 		case <-collectTicker.C:
-			if err := cache.CollectCache(exporters.EnableExporter, *multiCloud, services, *prefix, *cloud, *disabledMetrics, *endpointType, *collectTime, *disableSlowMetrics, *disableDeprecatedMetrics, *disableCinderAgentUUID, *domainID, *tenantID, novaMetadataMapping, nil, logger); err != nil {
+			if err := collect(); err != nil {
+				if ctx.Err() != nil {
+					logger.Info("Backend service is stopping")
+					return
+				}
 				errChan <- err
 				return
 			}
 		case <-ttlTicker.C:
-			cache.FlushExpiredCloudCaches(*cacheTTL)
+			flushCloudCaches(services, logger)
 			logger.Info("Cache TTL flush")
+		case <-cacheReload:
+			// Drop the caches of the removed clouds, and collect the new clouds right away.
+			flushCloudCaches(services, logger)
+			ttl = exporterConfig.Load().MinCacheTTL(defaultOptions(services))
+			collectTicker.Reset(ttl / 2)
+			ttlTicker.Reset(ttl)
+			if err := collect(); err != nil {
+				if ctx.Err() != nil {
+					logger.Info("Backend service is stopping")
+					return
+				}
+				errChan <- err
+				return
+			}
 		case <-ctx.Done():
 			logger.Info("Backend service is stopping")
 			return
@@ -149,6 +531,104 @@ func cacheBackgroundService(ctx context.Context, services map[string]*bool, errC
 	}
 }
 
+// afterCollect returns the function sending the cache after each collection to the Pushgateway
+// and to the remote write endpoint, when configured.
+func afterCollect(services map[string]*bool, pushConfig *cache.PushConfig, remoteWriter *remotewrite.Client, logger *slog.Logger) func(context.Context) {
+	return func(ctx context.Context) {
+		// A failed push is retried with the next collection.
+		if pushConfig != nil {
+			if err := cache.PushCache(ctx, *pushConfig, *multiCloud, *cloud, cloudOptions(services), logger); err != nil {
+				logger.Error("Failed to push the cache to the Pushgateway", "err", err)
+			}
+		}
+		if remoteWriter != nil {
+			if err := remoteWriter.EnqueueCache(*multiCloud, *cloud, cloudOptions(services)); err != nil {
+				logger.Error("Failed to queue the cache for remote write", "err", err)
+			}
+		}
+	}
+}
+
+// newRemoteWriter returns the remote write client configured by the --remote-write flags.
+func newRemoteWriter(logger *slog.Logger) (*remotewrite.Client, error) {
+	remoteWriteConfig := remotewrite.Config{
+		URL:        *remoteWriteURL,
+		Username:   *remoteWriteUsername,
+		BatchSize:  *remoteWriteBatchSize,
+		QueueSize:  *remoteWriteQueueSize,
+		MinBackoff: *remoteWriteMinBackoff,
+		MaxBackoff: *remoteWriteMaxBackoff,
+		MaxRetries: *remoteWriteMaxRetries,
+	}
+	if *remoteWritePasswordFile != "" {
+		password, err := os.ReadFile(*remoteWritePasswordFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the password file: %w", err)
+		}
+		remoteWriteConfig.Password = strings.TrimSpace(string(password))
+	}
+	return remotewrite.NewClient(remoteWriteConfig, logger)
+}
+
+// otlpService sends the cached metrics updated since their last send to the OpenTelemetry collector
+// every --otlp.interval.
+func otlpService(ctx context.Context, services map[string]*bool, exporter *otlp.Exporter, logger *slog.Logger) {
+	logger.Info("Start sending the metrics to the OpenTelemetry collector", "endpoint", *otlpEndpoint, "protocol", *otlpProtocol)
+	ticker := time.NewTicker(*otlpInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			sendCtx, cancel := context.WithTimeout(ctx, *otlpInterval)
+			if err := exporter.ExportCache(sendCtx, *multiCloud, *cloud, cloudOptions(services)); err != nil {
+				logger.Error("Failed to send the cache to the OpenTelemetry collector", "err", err)
+			}
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// newPushConfig returns the configuration of the pushes to the Pushgateway set by the --push flags.
+func newPushConfig() (*cache.PushConfig, error) {
+	pushConfig := &cache.PushConfig{URL: *pushURL, Job: *pushJob, Username: *pushUsername}
+	if *pushPasswordFile != "" {
+		password, err := os.ReadFile(*pushPasswordFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the password file: %w", err)
+		}
+		pushConfig.Password = strings.TrimSpace(string(password))
+	}
+
+	if *pushCAFile == "" && *pushCertFile == "" && !*pushInsecureSkipVerify {
+		return pushConfig, nil
+	}
+	tlsConfig := &tls.Config{InsecureSkipVerify: *pushInsecureSkipVerify}
+	if *pushCAFile != "" {
+		ca, err := os.ReadFile(*pushCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CA file: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificate found in the CA file %s", *pushCAFile)
+		}
+	}
+	if *pushCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(*pushCertFile, *pushKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	pushConfig.Client = &http.Client{Transport: transport}
+	return pushConfig, nil
+}
+
 func startHTTPServer(ctx context.Context, services map[string]*bool, toolkitFlags *web.FlagConfig, errChan chan<- error, logger *slog.Logger) {
 	links := []web.LandingLinks{}
 
@@ -172,6 +652,10 @@ func startHTTPServer(ctx context.Context, services map[string]*bool, toolkitFlag
 		})
 	}
 
+	if *enableLifecycle {
+		http.HandleFunc("/-/reload", reloadHandler(logger))
+	}
+
 	if *metrics != "/" && *metrics != "" {
 		landingConfig := web.LandingConfig{
 			Name:        "openstack_exporter",
@@ -212,9 +696,15 @@ func startHTTPServer(ctx context.Context, services map[string]*bool, toolkitFlag
 
 func probeHandler(services map[string]*bool, logger *slog.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ctx, cancel := context.WithCancel(r.Context())
+		ctx, cancel, err := scrapeContext(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		defer cancel()
 		r = r.WithContext(ctx)
+		// The exporters of the scrape share the resources they list.
+		ctx = exporters.WithSnapshot(ctx)
 
 		cloud := r.URL.Query().Get("cloud")
 		if cloud == "" {
@@ -222,13 +712,8 @@ func probeHandler(services map[string]*bool, logger *slog.Logger) http.HandlerFu
 			return
 		}
 
-		enabledServices := []string{}
-
-		for service, disabled := range services {
-			if !*disabled {
-				enabledServices = append(enabledServices, service)
-			}
-		}
+		options := cloudOptions(services)(cloud)
+		enabledServices := options.EnabledServices
 
 		includeServices := r.URL.Query().Get("include_services")
 		if includeServices != "" {
@@ -240,6 +725,12 @@ func probeHandler(services map[string]*bool, logger *slog.Logger) http.HandlerFu
 		logger.Info("Enabled services", "enabled_services", enabledServices)
 
 		// Get data from cache
+		if *cacheEnable && options.StateSetStatus {
+			// The StateSet type is only written by the OpenMetrics handler.
+			h := exporters.HandlerFor(cache.Gatherer(cloud, enabledServices), promhttp.HandlerOpts{ErrorLog: slog.NewLogLogger(logger.Handler(), slog.LevelError)}, true)
+			h.ServeHTTP(w, r)
+			return
+		}
 		if *cacheEnable {
 			if err := cache.WriteCacheToResponse(w, r, cloud, enabledServices, logger); err != nil {
 				logger.Error("Write cache to response failed", "error", err)
@@ -247,76 +738,226 @@ func probeHandler(services map[string]*bool, logger *slog.Logger) http.HandlerFu
 			return
 		}
 
-		registry := prometheus.NewPedanticRegistry()
-		for _, service := range enabledServices {
-			exp, err := exporters.EnableExporter(service, *prefix, cloud, *disabledMetrics, *endpointType, *collectTime, *disableSlowMetrics, *disableDeprecatedMetrics, *disableCinderAgentUUID, *domainID, *tenantID, novaMetadataMapping, nil, logger)
-			if err != nil {
-				logger.Error("Enabling exporter for service failed", "service", service, "error", err)
-				continue
+		exporters.RefreshClouds(logger)
+		regions, err := exporters.CollectedRegions(ctx, cloud, options.MultiRegion, options.RegionLabel, options.EndpointType, logger)
+		if err != nil {
+			// Without regions no exporter collects the cloud, the scrape fails to make it visible.
+			logger.Error("Listing the regions failed", "cloud", cloud, "error", err)
+			http.Error(w, fmt.Sprintf("listing the regions of cloud %s failed: %s", cloud, err), http.StatusServiceUnavailable)
+			return
+		}
+
+		// The exporters of a service collect the same metrics in every region,
+		// so each region has its own registry.
+		gatherers := prometheus.Gatherers{}
+		for _, region := range regions {
+			registry := prometheus.NewPedanticRegistry()
+			for _, service := range enabledServices {
+				exp, err := exporters.EnableExporter(ctx, options.ExporterOptions(exporterDefaults(), service, cloud, region), logger)
+				if err != nil {
+					logger.Error("Enabling exporter for service failed", "service", service, "region", region, "error", err)
+					continue
+				}
+				registry.MustRegister(exporters.WithContext(ctx, *exp))
+				logger.Info("Enabled exporter for service", "service", service, "region", region)
 			}
-			registry.MustRegister(*exp)
-			logger.Info("Enabled exporter for service", "service", service)
+			gatherers = append(gatherers, registry)
 		}
 
-		h := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+		h := exporters.HandlerFor(gatherers, promhttp.HandlerOpts{ErrorLog: slog.NewLogLogger(logger.Handler(), slog.LevelError)}, options.StateSetStatus)
 		h.ServeHTTP(w, r)
 	}
 }
 
+// writeAPIMetrics writes the metrics of the requests to the OpenStack APIs after the cached metrics,
+// in the text format of the cache.
+func writeAPIMetrics(w io.Writer, logger *slog.Logger) {
+	mfs, err := apiRegistry.Gather()
+	if err != nil {
+		logger.Error("Gathering the API request metrics failed", "error", err)
+		return
+	}
+	for _, mf := range mfs {
+		if _, err := expfmt.MetricFamilyToText(w, mf); err != nil {
+			logger.Error("Writing the API request metrics failed", "error", err)
+			return
+		}
+	}
+}
+
 func metricHandler(services map[string]*bool, logger *slog.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		logger.Info("Starting openstack exporter version for cloud", "version", version.Info(), "cloud", *cloud)
 		logger.Info("Build context", "build_context", version.BuildContext())
 
+		ctx, cancel, err := scrapeContext(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer cancel()
+		r = r.WithContext(ctx)
+		// The exporters of the scrape share the resources they list.
+		ctx = exporters.WithSnapshot(ctx)
+
 		if *osClientConfig != DEFAULT_OS_CLIENT_CONFIG {
 			logger.Debug("Setting Env var OS_CLIENT_CONFIG_FILE", "os_client_config_file", *osClientConfig)
 			os.Setenv("OS_CLIENT_CONFIG_FILE", *osClientConfig)
 		}
 
-		enabledServices := []string{}
-		for service, disabled := range services {
-			if !*disabled {
-				enabledServices = append(enabledServices, service)
-			}
-		}
+		options := cloudOptions(services)(*cloud)
+		enabledServices := options.EnabledServices
 
 		// Get data from cache
+		if *cacheEnable && options.StateSetStatus {
+			// The StateSet type is only written by the OpenMetrics handler.
+			gatherers := prometheus.Gatherers{cache.Gatherer(*cloud, enabledServices), apiRegistry}
+			h := exporters.HandlerFor(gatherers, promhttp.HandlerOpts{ErrorLog: slog.NewLogLogger(logger.Handler(), slog.LevelError)}, true)
+			h.ServeHTTP(w, r)
+			return
+		}
 		if *cacheEnable {
 			if err := cache.WriteCacheToResponse(w, r, *cloud, enabledServices, logger); err != nil {
 				logger.Error("Write cache to response failed", "error", err)
+				return
 			}
+			writeAPIMetrics(w, logger)
 			return
 		}
 
-		registry := prometheus.NewPedanticRegistry()
+		exporters.RefreshClouds(logger)
+		regions, err := exporters.CollectedRegions(ctx, *cloud, options.MultiRegion, options.RegionLabel, options.EndpointType, logger)
+		if err != nil {
+			// Without regions no exporter collects the cloud, the scrape fails to make it visible.
+			logger.Error("Listing the regions failed", "cloud", *cloud, "error", err)
+			http.Error(w, fmt.Sprintf("listing the regions of cloud %s failed: %s", *cloud, err), http.StatusServiceUnavailable)
+			return
+		}
+
+		// The exporters of a service collect the same metrics in every region,
+		// so each region has its own registry.
+		gatherers := prometheus.Gatherers{}
 		enabledExporters := 0
-		for _, service := range enabledServices {
-			exp, err := exporters.EnableExporter(service, *prefix, *cloud, *disabledMetrics, *endpointType, *collectTime, *disableSlowMetrics, *disableDeprecatedMetrics, *disableCinderAgentUUID, *domainID, *tenantID, novaMetadataMapping, nil, logger)
-			if err != nil {
-				// Log error and continue with enabling other exporters
-				logger.Error("enabling exporter for service failed", "service", service, "error", err)
-				continue
+		for _, region := range regions {
+			registry := prometheus.NewPedanticRegistry()
+			for _, service := range enabledServices {
+				exp, err := exporters.EnableExporter(ctx, options.ExporterOptions(exporterDefaults(), service, *cloud, region), logger)
+				if err != nil {
+					// Log error and continue with enabling other exporters
+					logger.Error("enabling exporter for service failed", "service", service, "region", region, "error", err)
+					continue
+				}
+				registry.MustRegister(exporters.WithContext(ctx, *exp))
+				logger.Info("Enabled exporter for service", "service", service, "region", region)
+				enabledExporters++
 			}
-			registry.MustRegister(*exp)
-			logger.Info("Enabled exporter for service", "service", service)
-			enabledExporters++
+			gatherers = append(gatherers, registry)
 		}
+		gatherers = append(gatherers, apiRegistry)
 
 		if enabledExporters == 0 {
 			logger.Error("No exporter has been enabled, exiting")
 			os.Exit(-1)
 		}
 
-		h := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+		h := exporters.HandlerFor(gatherers, promhttp.HandlerOpts{ErrorLog: slog.NewLogLogger(logger.Handler(), slog.LevelError)}, options.StateSetStatus)
 		h.ServeHTTP(w, r)
 	}
 }
 
-func SetPasswordIfVaultIsUsed(logger *slog.Logger) {
+// defaultOptions returns the collection options set by the command line flags.
+func defaultOptions(services map[string]*bool) config.Options {
+	enabledServices := []string{}
+	for _, service := range exporters.Services() {
+		if !*services[service] {
+			enabledServices = append(enabledServices, service)
+		}
+	}
+
+	return config.Options{
+		EnabledServices: enabledServices,
+		DisabledMetrics: *disabledMetrics,
+		EndpointType:    *endpointType,
+		DomainID:        *domainID,
+		TenantID:        *tenantID,
+		LabelMappings:   labelMappings,
+		CacheTTL:        *cacheTTL,
+		RegionLabel:     *regionLabel,
+		MultiRegion:     *multiRegion,
+		MetricFilter:    metricFilter,
+		StateSetStatus:  *statusMetrics == config.StatusMetricsStateSet,
+		ExternalLabels:  remoteWriteLabels.Labels,
+	}
+}
+
+// exporterDefaults returns the options of the exporters set by the command line flags only,
+// completed by the collection options of each cloud.
+func exporterDefaults() exporters.ExporterOptions {
+	return exporters.ExporterOptions{
+		Prefix:                   *prefix,
+		CollectTime:              *collectTime,
+		DisableSlowMetrics:       *disableSlowMetrics,
+		DisableDeprecatedMetrics: *disableDeprecatedMetrics,
+		DisableCinderAgentUUID:   *disableCinderAgentUUID,
+		CollectConcurrency:       *collectConcurrency,
+		CloudCollectConcurrency:  *cloudCollectConcurrency,
+	}
+}
+
+// cloudOptions returns a function resolving the collection options of a cloud,
+// the command line flags overridden by the exporter config file.
+func cloudOptions(services map[string]*bool) func(cloud string) config.Options {
+	defaults := defaultOptions(services)
+	return func(cloud string) config.Options {
+		return exporterConfig.Load().Options(cloud, defaults)
+	}
+}
+
+// loadExporterConfig loads the exporter config file, and checks that its clouds are defined in clouds.yaml.
+func loadExporterConfig(path string) (*config.File, error) {
+	exporterConfig, err := config.Load(path, exporters.Services())
+	if err != nil {
+		return nil, err
+	}
+
+	clouds, err := clientconfig.LoadCloudsYAML()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range exporterConfig.CloudNames() {
+		if _, ok := clouds[name]; !ok {
+			return nil, fmt.Errorf("invalid config file %s: clouds.%s: cloud not found in clouds.yaml", path, name)
+		}
+	}
+	return exporterConfig, nil
+}
+
+// scrapeContext returns the context of a scrape request. When Prometheus advertises its
+// scrape timeout, the context expires before Prometheus abandons the scrape.
+func scrapeContext(r *http.Request) (context.Context, context.CancelFunc, error) {
+	v := r.Header.Get("X-Prometheus-Scrape-Timeout-Seconds")
+	if v == "" {
+		ctx, cancel := context.WithCancel(r.Context())
+		return ctx, cancel, nil
+	}
+
+	seconds, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse timeout from Prometheus header: %w", err)
+	}
+	timeout := time.Duration(seconds * float64(time.Second))
+	if timeout > *scrapeTimeoutOffset {
+		timeout -= *scrapeTimeoutOffset
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	return ctx, cancel, nil
+}
+
+// SetPasswordIfVaultIsUsed sets OS_PASSWORD from the Vault secret configured in clouds.yaml, if any.
+func SetPasswordIfVaultIsUsed(logger *slog.Logger) error {
 	configFileData, err := os.ReadFile(*osClientConfig)
 	if err != nil {
-		logger.Error("failed to read config file", "err", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to read config file: %w", err)
 	}
 
 	type VaultConfig struct {
@@ -333,17 +974,15 @@ func SetPasswordIfVaultIsUsed(logger *slog.Logger) {
 
 	err = yaml.Unmarshal(configFileData, &vaultConfig)
 	if err != nil {
-		logger.Error("failed to parse config data", "err", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to parse config data: %w", err)
 	}
 
 	if !vaultConfig.UseVault {
-		return
+		return nil
 	}
 	client, err := vault.New(vault.WithAddress(vaultConfig.VaultAddress),)
 	if err != nil {
-		logger.Error("failed to create Vault client", "err", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to create Vault client: %w", err)
 	}
 	ctx := context.Background()
 	resp, err := client.Auth.AppRoleLogin(
@@ -354,12 +993,10 @@ func SetPasswordIfVaultIsUsed(logger *slog.Logger) {
 		},
 	)
 	if err != nil {
-		logger.Error("failed to login to Vault", "err", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to login to Vault: %w", err)
 	}
 	if err := client.SetToken(resp.Auth.ClientToken); err != nil {
-		logger.Error("failed to set Vault token", "err", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to set Vault token: %w", err)
 	}
 	secret, err := client.Secrets.KvV2Read(
 		ctx,
@@ -367,9 +1004,14 @@ func SetPasswordIfVaultIsUsed(logger *slog.Logger) {
 		vault.WithMountPath(vaultConfig.VaultSecretMountPath),
 	)
 	if err != nil {
-		logger.Error("failed to get secret from Vault", "err", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to get secret from Vault: %w", err)
 	}
 
-	os.Setenv("OS_PASSWORD", secret.Data.Data[vaultConfig.CredentialNameInVaultSecret].(string))
+	password, ok := secret.Data.Data[vaultConfig.CredentialNameInVaultSecret].(string)
+	if !ok {
+		return fmt.Errorf("credential %q not found in Vault secret", vaultConfig.CredentialNameInVaultSecret)
+	}
+	logger.Debug("Setting Env var OS_PASSWORD from Vault")
+	os.Setenv("OS_PASSWORD", password)
+	return nil
 }
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openstack-exporter/openstack-exporter/exporters"
+	"github.com/stretchr/testify/assert"
+)
+
+// enabledServices returns the --disable-service flags with every service enabled.
+func enabledServices() map[string]*bool {
+	services := map[string]*bool{}
+	for _, service := range exporters.Services() {
+		services[service] = new(bool)
+	}
+	return services
+}
+
+func TestProbeHandlerRegionsFailure(t *testing.T) {
+	setupReload(t)
+	setFlag(t, regionLabel, true)
+	handler := probeHandler(enabledServices(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/probe?cloud=unknowncloud", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, "the probe should fail when the regions of the cloud can't be listed")
+	assert.Contains(t, w.Body.String(), "listing the regions of cloud unknowncloud failed")
+}
@@ -0,0 +1,249 @@
+package otlp
+
+import (
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/openstack-exporter/openstack-exporter/cache"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/version"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+// scopeName is the name of the instrumentation scope of the metrics.
+const scopeName = "github.com/openstack-exporter/openstack-exporter"
+
+// units are the OTLP units (UCUM) of the units of the metric families.
+var units = map[string]string{
+	"seconds":   "s",
+	"bytes":     "By",
+	"megabytes": "MBy",
+	"gigabytes": "GBy",
+}
+
+// resourceMetrics converts the cached metric families of the services of a cloud, with one resource
+// per region. The families shared by several services of a region are merged. The points without
+// timestamp are timestamped with the time of the cache, and the cumulative points start at their
+// start in starts unless they have their own created timestamp.
+func resourceMetrics(cloud string, cloudCache cache.CloudCache, services []string, starts *cumulativeStarts) []*metricspb.ResourceMetrics {
+	regions := make(map[string]map[string]*dto.MetricFamily)
+	for _, mfCache := range cloudCache.MetricFamilyCaches {
+		if !slices.Contains(services, mfCache.Service) {
+			continue
+		}
+		families, ok := regions[mfCache.Region]
+		if !ok {
+			families = make(map[string]*dto.MetricFamily)
+			regions[mfCache.Region] = families
+		}
+		name := mfCache.MF.GetName()
+		if mf, ok := families[name]; ok {
+			mf.Metric = append(mf.Metric, mfCache.MF.Metric...)
+			continue
+		}
+		families[name] = &dto.MetricFamily{
+			Name:   mfCache.MF.Name,
+			Help:   mfCache.MF.Help,
+			Type:   mfCache.MF.Type,
+			Unit:   mfCache.MF.Unit,
+			Metric: slices.Clone(mfCache.MF.Metric),
+		}
+	}
+
+	resources := []*metricspb.ResourceMetrics{}
+	for _, region := range slices.Sorted(maps.Keys(regions)) {
+		families := regions[region]
+		metrics := make([]*metricspb.Metric, 0, len(families))
+		for _, name := range slices.Sorted(maps.Keys(families)) {
+			if metric := convertMetricFamily(families[name], region, cloudCache.Time, starts); metric != nil {
+				metrics = append(metrics, metric)
+			}
+		}
+		resources = append(resources, &metricspb.ResourceMetrics{
+			Resource: &resourcepb.Resource{Attributes: resourceAttributes(cloud, region)},
+			ScopeMetrics: []*metricspb.ScopeMetrics{{
+				Scope:   &commonpb.InstrumentationScope{Name: scopeName, Version: version.Version},
+				Metrics: metrics,
+			}},
+		})
+	}
+	return resources
+}
+
+// resourceAttributes returns the attributes of the resource of the metrics of a cloud region,
+// following the OpenTelemetry semantic conventions for the region.
+func resourceAttributes(cloud, region string) []*commonpb.KeyValue {
+	attributes := []*commonpb.KeyValue{
+		stringAttribute("service.name", "openstack-exporter"),
+		stringAttribute("cloud.provider", "openstack"),
+		stringAttribute("openstack.cloud", cloud),
+	}
+	if region != "" {
+		attributes = append(attributes, stringAttribute("cloud.region", region))
+	}
+	return attributes
+}
+
+// convertMetricFamily converts a metric family of a region into an OTLP metric, nil if its type has
+// no OTLP equivalent. The counters and the histograms are cumulative.
+func convertMetricFamily(mf *dto.MetricFamily, region string, now time.Time, starts *cumulativeStarts) *metricspb.Metric {
+	metric := &metricspb.Metric{Name: mf.GetName(), Description: mf.GetHelp(), Unit: mf.GetUnit()}
+	if unit, ok := units[metric.Unit]; ok {
+		metric.Unit = unit
+	}
+
+	switch mf.GetType() {
+	case dto.MetricType_GAUGE, dto.MetricType_UNTYPED:
+		gauge := &metricspb.Gauge{}
+		for _, m := range mf.GetMetric() {
+			value := m.GetGauge().GetValue()
+			if mf.GetType() == dto.MetricType_UNTYPED {
+				value = m.GetUntyped().GetValue()
+			}
+			gauge.DataPoints = append(gauge.DataPoints, &metricspb.NumberDataPoint{
+				Attributes:   labelAttributes(m),
+				TimeUnixNano: timestamp(m, now),
+				Value:        &metricspb.NumberDataPoint_AsDouble{AsDouble: value},
+			})
+		}
+		metric.Data = &metricspb.Metric_Gauge{Gauge: gauge}
+	case dto.MetricType_COUNTER:
+		sum := &metricspb.Sum{AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, IsMonotonic: true}
+		for _, m := range mf.GetMetric() {
+			sum.DataPoints = append(sum.DataPoints, &metricspb.NumberDataPoint{
+				Attributes:        labelAttributes(m),
+				StartTimeUnixNano: startTimestamp(m.GetCounter().GetCreatedTimestamp().AsTime(), starts.start(region, mf, m, m.GetCounter().GetValue(), now)),
+				TimeUnixNano:      timestamp(m, now),
+				Value:             &metricspb.NumberDataPoint_AsDouble{AsDouble: m.GetCounter().GetValue()},
+			})
+		}
+		metric.Data = &metricspb.Metric_Sum{Sum: sum}
+	case dto.MetricType_HISTOGRAM:
+		histogram := &metricspb.Histogram{AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE}
+		for _, m := range mf.GetMetric() {
+			start := starts.start(region, mf, m, float64(m.GetHistogram().GetSampleCount()), now)
+			histogram.DataPoints = append(histogram.DataPoints, histogramDataPoint(m, now, start))
+		}
+		metric.Data = &metricspb.Metric_Histogram{Histogram: histogram}
+	case dto.MetricType_SUMMARY:
+		summary := &metricspb.Summary{}
+		for _, m := range mf.GetMetric() {
+			point := &metricspb.SummaryDataPoint{
+				Attributes:        labelAttributes(m),
+				StartTimeUnixNano: startTimestamp(m.GetSummary().GetCreatedTimestamp().AsTime(), starts.start(region, mf, m, float64(m.GetSummary().GetSampleCount()), now)),
+				TimeUnixNano:      timestamp(m, now),
+				Count:             m.GetSummary().GetSampleCount(),
+				Sum:               m.GetSummary().GetSampleSum(),
+			}
+			for _, quantile := range m.GetSummary().GetQuantile() {
+				point.QuantileValues = append(point.QuantileValues, &metricspb.SummaryDataPoint_ValueAtQuantile{
+					Quantile: quantile.GetQuantile(),
+					Value:    quantile.GetValue(),
+				})
+			}
+			summary.DataPoints = append(summary.DataPoints, point)
+		}
+		metric.Data = &metricspb.Metric_Summary{Summary: summary}
+	default:
+		return nil
+	}
+	return metric
+}
+
+// histogramDataPoint converts a histogram, whose cumulative bucket counts become the counts of
+// each bucket, the last one counting the observations above the last bound.
+func histogramDataPoint(m *dto.Metric, now, start time.Time) *metricspb.HistogramDataPoint {
+	h := m.GetHistogram()
+	sum := h.GetSampleSum()
+	point := &metricspb.HistogramDataPoint{
+		Attributes:        labelAttributes(m),
+		StartTimeUnixNano: startTimestamp(h.GetCreatedTimestamp().AsTime(), start),
+		TimeUnixNano:      timestamp(m, now),
+		Count:             h.GetSampleCount(),
+		Sum:               &sum,
+	}
+	var previous uint64
+	for _, bucket := range h.GetBucket() {
+		if math.IsInf(bucket.GetUpperBound(), +1) {
+			continue
+		}
+		point.ExplicitBounds = append(point.ExplicitBounds, bucket.GetUpperBound())
+		point.BucketCounts = append(point.BucketCounts, bucket.GetCumulativeCount()-previous)
+		previous = bucket.GetCumulativeCount()
+	}
+	point.BucketCounts = append(point.BucketCounts, h.GetSampleCount()-previous)
+	return point
+}
+
+func labelAttributes(m *dto.Metric) []*commonpb.KeyValue {
+	attributes := make([]*commonpb.KeyValue, 0, len(m.GetLabel()))
+	for _, label := range m.GetLabel() {
+		attributes = append(attributes, stringAttribute(label.GetName(), label.GetValue()))
+	}
+	return attributes
+}
+
+func stringAttribute(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
+
+// timestamp returns the time of a point, the time of its collection when the metric has no timestamp.
+func timestamp(m *dto.Metric, now time.Time) uint64 {
+	if m.TimestampMs != nil {
+		return uint64(m.GetTimestampMs()) * uint64(time.Millisecond)
+	}
+	return uint64(now.UnixNano())
+}
+
+// startTimestamp returns the start time of a cumulative point, its created time if set.
+func startTimestamp(created, start time.Time) uint64 {
+	if created.Unix() > 0 {
+		return uint64(created.UnixNano())
+	}
+	return uint64(start.UnixNano())
+}
+
+// cumulativePoint is the last point of a cumulative series.
+type cumulativePoint struct {
+	start time.Time
+	time  time.Time
+	value float64
+}
+
+// cumulativeStarts are the start times of the cumulative series of a cloud. A series starts at the
+// default start when first seen, and restarts at the time of its last point when its value decreases,
+// i.e: when the counters of an Octavia amphora are reset by its restart.
+type cumulativeStarts struct {
+	defaultStart time.Time
+	previous     map[string]cumulativePoint
+	// current are the points of the series seen in this conversion, the previous points of the next one.
+	current map[string]cumulativePoint
+}
+
+func newCumulativeStarts(defaultStart time.Time, previous map[string]cumulativePoint) *cumulativeStarts {
+	return &cumulativeStarts{defaultStart: defaultStart, previous: previous, current: make(map[string]cumulativePoint)}
+}
+
+// start returns the start time of a point of a cumulative series of a region, value being its
+// cumulative value (i.e: the count of a histogram).
+func (s *cumulativeStarts) start(region string, mf *dto.MetricFamily, m *dto.Metric, value float64, now time.Time) time.Time {
+	var key strings.Builder
+	key.WriteString(region + "\xff" + mf.GetName())
+	for _, label := range m.GetLabel() {
+		key.WriteString("\xff" + label.GetName() + "=" + label.GetValue())
+	}
+	point := cumulativePoint{start: s.defaultStart, time: time.Unix(0, int64(timestamp(m, now))), value: value}
+	if previous, ok := s.previous[key.String()]; ok {
+		point.start = previous.start
+		if value < previous.value {
+			point.start = previous.time
+		}
+	}
+	s.current[key.String()] = point
+	return point.start
+}
@@ -0,0 +1,177 @@
+// Package otlp sends the cached metrics of the clouds to an OpenTelemetry collector with OTLP.
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/openstack-exporter/openstack-exporter/cache"
+	"github.com/openstack-exporter/openstack-exporter/config"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+)
+
+// Protocols of the OTLP exporter.
+const (
+	ProtocolHTTP = "http/protobuf"
+	ProtocolGRPC = "grpc"
+)
+
+// Exporter sends metrics to an OpenTelemetry collector.
+type Exporter struct {
+	export func(ctx context.Context, request *colmetricspb.ExportMetricsServiceRequest) (*colmetricspb.ExportMetricsServiceResponse, error)
+	close  func() error
+	// start is the start time of the cumulative series without created time, until they reset.
+	start time.Time
+
+	mu sync.Mutex
+	// sent are the times of the cloud caches already sent, by cloud.
+	sent map[string]time.Time
+	// series are the last points of the cumulative series of the clouds, by cloud.
+	series map[string]map[string]cumulativePoint
+}
+
+// NewExporter returns an exporter sending the metrics to the collector at endpoint with protocol.
+// endpoint is a URL, whose https scheme enables TLS (i.e: http://otel-collector:4318). With
+// http/protobuf, the metrics are sent to its /v1/metrics path, unless it has its own path.
+func NewExporter(endpoint, protocol string) (*Exporter, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTLP endpoint: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q, must be a http or https URL", endpoint)
+	}
+
+	switch protocol {
+	case ProtocolHTTP:
+		if u.Path == "" || u.Path == "/" {
+			u.Path = "/v1/metrics"
+		}
+		return newExporter(httpExport(http.DefaultClient, u.String()), func() error { return nil }), nil
+	case ProtocolGRPC:
+		creds := insecure.NewCredentials()
+		if u.Scheme == "https" {
+			creds = credentials.NewTLS(&tls.Config{})
+		}
+		conn, err := grpc.NewClient(u.Host, grpc.WithTransportCredentials(creds))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the OTLP gRPC client: %w", err)
+		}
+		client := colmetricspb.NewMetricsServiceClient(conn)
+		export := func(ctx context.Context, request *colmetricspb.ExportMetricsServiceRequest) (*colmetricspb.ExportMetricsServiceResponse, error) {
+			return client.Export(ctx, request)
+		}
+		return newExporter(export, conn.Close), nil
+	}
+	return nil, fmt.Errorf("unknown OTLP protocol %q, must be one of %s or %s", protocol, ProtocolHTTP, ProtocolGRPC)
+}
+
+func newExporter(export func(context.Context, *colmetricspb.ExportMetricsServiceRequest) (*colmetricspb.ExportMetricsServiceResponse, error), close func() error) *Exporter {
+	return &Exporter{
+		export: export,
+		close:  close,
+		start:  time.Now(),
+		sent:   make(map[string]time.Time),
+		series: make(map[string]map[string]cumulativePoint),
+	}
+}
+
+// httpExport returns a function sending the export requests to the URL of a collector with OTLP/HTTP.
+func httpExport(client *http.Client, url string) func(context.Context, *colmetricspb.ExportMetricsServiceRequest) (*colmetricspb.ExportMetricsServiceResponse, error) {
+	return func(ctx context.Context, request *colmetricspb.ExportMetricsServiceRequest) (*colmetricspb.ExportMetricsServiceResponse, error) {
+		body, err := proto.Marshal(request)
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-protobuf")
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status code %d while sending to %s: %s", resp.StatusCode, url, data)
+		}
+		response := &colmetricspb.ExportMetricsServiceResponse{}
+		if err := proto.Unmarshal(data, response); err != nil {
+			return nil, fmt.Errorf("invalid response of %s: %w", url, err)
+		}
+		return response, nil
+	}
+}
+
+// ExportCache sends the cached metrics of the enabled services of the clouds, with the cloud and
+// its region as resource attributes. The clouds are the ones collected by the cache, and the cache
+// of a cloud is sent once after each of its updates, until the collector accepts it.
+func (e *Exporter) ExportCache(ctx context.Context, multiCloud bool, cloud string, cloudOptions func(cloud string) config.Options) error {
+	clouds, err := cache.CollectedClouds(multiCloud, cloud)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	metrics := []*metricspb.ResourceMetrics{}
+	exported := make(map[string]time.Time)
+	cacheBackend := cache.GetCache()
+	for _, cloud := range clouds {
+		cloudCache, exists := cacheBackend.GetCloudCache(cloud)
+		if !exists || e.sent[cloud].Equal(cloudCache.Time) {
+			continue
+		}
+		starts := newCumulativeStarts(e.start, e.series[cloud])
+		metrics = append(metrics, resourceMetrics(cloud, cloudCache, cloudOptions(cloud).EnabledServices, starts)...)
+		e.series[cloud] = starts.current
+		exported[cloud] = cloudCache.Time
+	}
+	maps.DeleteFunc(e.series, func(cloud string, _ map[string]cumulativePoint) bool { return !slices.Contains(clouds, cloud) })
+	if len(metrics) == 0 {
+		return nil
+	}
+	if err := e.Export(ctx, metrics); err != nil {
+		return err
+	}
+	maps.Copy(e.sent, exported)
+	return nil
+}
+
+// Export sends the metrics to the collector. The metrics rejected by the collector are reported
+// as an error.
+func (e *Exporter) Export(ctx context.Context, metrics []*metricspb.ResourceMetrics) error {
+	response, err := e.export(ctx, &colmetricspb.ExportMetricsServiceRequest{ResourceMetrics: metrics})
+	if err != nil {
+		return err
+	}
+	if rejected := response.GetPartialSuccess().GetRejectedDataPoints(); rejected > 0 {
+		return fmt.Errorf("the collector rejected %d data points: %s", rejected, response.GetPartialSuccess().GetErrorMessage())
+	}
+	return nil
+}
+
+// Close releases the connection to the collector.
+func (e *Exporter) Close() error {
+	return e.close()
+}
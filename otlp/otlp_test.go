@@ -0,0 +1,207 @@
+package otlp
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/openstack-exporter/openstack-exporter/cache"
+	"github.com/openstack-exporter/openstack-exporter/config"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// receiver is a stub of the metrics service of an OpenTelemetry collector.
+type receiver struct {
+	colmetricspb.UnimplementedMetricsServiceServer
+	requests chan *colmetricspb.ExportMetricsServiceRequest
+}
+
+func (r *receiver) Export(ctx context.Context, request *colmetricspb.ExportMetricsServiceRequest) (*colmetricspb.ExportMetricsServiceResponse, error) {
+	r.requests <- request
+	return &colmetricspb.ExportMetricsServiceResponse{}, nil
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil || req.URL.Path != "/v1/metrics" || req.Header.Get("Content-Type") != "application/x-protobuf" {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	request := &colmetricspb.ExportMetricsServiceRequest{}
+	if err := proto.Unmarshal(body, request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	r.requests <- request
+	response, _ := proto.Marshal(&colmetricspb.ExportMetricsServiceResponse{
+		PartialSuccess: &colmetricspb.ExportMetricsPartialSuccess{RejectedDataPoints: 0},
+	})
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	_, _ = w.Write(response)
+}
+
+func metricFamily(name string, metricType dto.MetricType, metrics ...*dto.Metric) *dto.MetricFamily {
+	return &dto.MetricFamily{Name: proto.String(name), Help: proto.String("Help " + name), Type: metricType.Enum(), Metric: metrics}
+}
+
+func label(name, value string) *dto.LabelPair {
+	return &dto.LabelPair{Name: proto.String(name), Value: proto.String(value)}
+}
+
+func setCloudCache(t *testing.T) time.Time {
+	cloudCache := cache.NewCloudCache()
+	cloudCache.SetMetricFamilyCache("RegionOne/network/openstack_neutron_up", cache.MetricFamilyCache{Service: "network", Region: "RegionOne",
+		MF: metricFamily("openstack_neutron_up", dto.MetricType_GAUGE, &dto.Metric{Label: []*dto.LabelPair{label("region", "RegionOne")}, Gauge: &dto.Gauge{Value: proto.Float64(1)}})})
+	cloudCache.SetMetricFamilyCache("RegionTwo/network/openstack_neutron_up", cache.MetricFamilyCache{Service: "network", Region: "RegionTwo",
+		MF: metricFamily("openstack_neutron_up", dto.MetricType_GAUGE, &dto.Metric{Label: []*dto.LabelPair{label("region", "RegionTwo")}, Gauge: &dto.Gauge{Value: proto.Float64(0)}})})
+	cloudCache.SetMetricFamilyCache("RegionOne/network/openstack_collector_errors_total", cache.MetricFamilyCache{Service: "network", Region: "RegionOne",
+		MF: metricFamily("openstack_collector_errors_total", dto.MetricType_COUNTER, &dto.Metric{Label: []*dto.LabelPair{label("metric", "ports")}, Counter: &dto.Counter{Value: proto.Float64(3)}})})
+	cloudCache.SetMetricFamilyCache("RegionOne/compute/openstack_collector_errors_total", cache.MetricFamilyCache{Service: "compute", Region: "RegionOne",
+		MF: metricFamily("openstack_collector_errors_total", dto.MetricType_COUNTER, &dto.Metric{Label: []*dto.LabelPair{label("metric", "servers")}, Counter: &dto.Counter{Value: proto.Float64(2)}})})
+	cloudCache.SetMetricFamilyCache("RegionOne/volume/openstack_cinder_up", cache.MetricFamilyCache{Service: "volume", Region: "RegionOne",
+		MF: metricFamily("openstack_cinder_up", dto.MetricType_GAUGE, &dto.Metric{Gauge: &dto.Gauge{Value: proto.Float64(1)}})})
+	cache.GetCache().SetCloudCache("test.cloud", cloudCache)
+	t.Cleanup(func() { cache.FlushExpiredCloudCaches(0) })
+
+	cached, _ := cache.GetCache().GetCloudCache("test.cloud")
+	return cached.Time
+}
+
+func stringValue(value string) *commonpb.AnyValue {
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}
+}
+
+func TestExportCache(t *testing.T) {
+	cloudOptions := func(string) config.Options {
+		return config.Options{EnabledServices: []string{"network", "compute"}}
+	}
+
+	for _, protocol := range []string{ProtocolHTTP, ProtocolGRPC} {
+		t.Run(protocol, func(t *testing.T) {
+			cacheTime := setCloudCache(t)
+			stub := &receiver{requests: make(chan *colmetricspb.ExportMetricsServiceRequest, 1)}
+			var endpoint string
+			if protocol == ProtocolHTTP {
+				server := httptest.NewServer(stub)
+				defer server.Close()
+				endpoint = server.URL
+			} else {
+				listener, err := net.Listen("tcp", "127.0.0.1:0")
+				require.NoError(t, err)
+				server := grpc.NewServer()
+				colmetricspb.RegisterMetricsServiceServer(server, stub)
+				go func() { _ = server.Serve(listener) }()
+				defer server.Stop()
+				endpoint = "http://" + listener.Addr().String()
+			}
+
+			exporter, err := NewExporter(endpoint, protocol)
+			require.NoError(t, err)
+			defer exporter.Close()
+			require.NoError(t, exporter.ExportCache(context.Background(), false, "test.cloud", cloudOptions))
+
+			request := <-stub.requests
+			require.Len(t, request.ResourceMetrics, 2, "the metrics of each region should have their resource")
+			regionOne := request.ResourceMetrics[0]
+			assert.Contains(t, regionOne.Resource.Attributes, &commonpb.KeyValue{Key: "openstack.cloud", Value: stringValue("test.cloud")})
+			assert.Contains(t, regionOne.Resource.Attributes, &commonpb.KeyValue{Key: "cloud.region", Value: stringValue("RegionOne")})
+			assert.Contains(t, request.ResourceMetrics[1].Resource.Attributes, &commonpb.KeyValue{Key: "cloud.region", Value: stringValue("RegionTwo")})
+
+			metrics := regionOne.ScopeMetrics[0].Metrics
+			require.Len(t, metrics, 2, "the metrics of the disabled services should not be sent")
+			assert.Equal(t, "openstack_collector_errors_total", metrics[0].Name)
+			sum := metrics[0].GetSum()
+			require.NotNil(t, sum, "a counter should be a sum")
+			assert.True(t, sum.IsMonotonic)
+			assert.Equal(t, metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, sum.AggregationTemporality)
+			assert.Len(t, sum.DataPoints, 2, "the families shared by services should be merged")
+			assert.Equal(t, uint64(cacheTime.UnixNano()), sum.DataPoints[0].TimeUnixNano)
+			assert.Equal(t, uint64(exporter.start.UnixNano()), sum.DataPoints[0].StartTimeUnixNano)
+
+			assert.Equal(t, "openstack_neutron_up", metrics[1].Name)
+			assert.Equal(t, "Help openstack_neutron_up", metrics[1].Description)
+			gauge := metrics[1].GetGauge()
+			require.NotNil(t, gauge)
+			assert.Equal(t, 1.0, gauge.DataPoints[0].GetAsDouble())
+			assert.Equal(t, []*commonpb.KeyValue{{Key: "region", Value: stringValue("RegionOne")}}, gauge.DataPoints[0].Attributes)
+
+			require.NoError(t, exporter.ExportCache(context.Background(), false, "test.cloud", cloudOptions))
+			select {
+			case <-stub.requests:
+				assert.Fail(t, "the cache should not be sent again until it's updated")
+			default:
+			}
+			setCloudCache(t)
+			require.NoError(t, exporter.ExportCache(context.Background(), false, "test.cloud", cloudOptions))
+			assert.Len(t, (<-stub.requests).ResourceMetrics, 2, "the updated cache should be sent")
+		})
+	}
+}
+
+func TestConvertHistogram(t *testing.T) {
+	now, start := time.Unix(1700000000, 0), time.Unix(1600000000, 0)
+	mf := metricFamily("openstack_api_request_duration_seconds", dto.MetricType_HISTOGRAM, &dto.Metric{Histogram: &dto.Histogram{
+		SampleCount: proto.Uint64(10),
+		SampleSum:   proto.Float64(4.5),
+		Bucket: []*dto.Bucket{
+			{UpperBound: proto.Float64(0.1), CumulativeCount: proto.Uint64(2)},
+			{UpperBound: proto.Float64(1), CumulativeCount: proto.Uint64(7)},
+		},
+	}})
+	mf.Unit = proto.String("seconds")
+
+	metric := convertMetricFamily(mf, "RegionOne", now, newCumulativeStarts(start, nil))
+	assert.Equal(t, "s", metric.Unit)
+	histogram := metric.GetHistogram()
+	require.NotNil(t, histogram)
+	point := histogram.DataPoints[0]
+	assert.Equal(t, []float64{0.1, 1}, point.ExplicitBounds)
+	assert.Equal(t, []uint64{2, 5, 3}, point.BucketCounts, "the counts should be the counts of each bucket")
+	assert.Equal(t, uint64(10), point.Count)
+	assert.Equal(t, 4.5, point.GetSum())
+	assert.Equal(t, uint64(start.UnixNano()), point.StartTimeUnixNano)
+	assert.Equal(t, uint64(now.UnixNano()), point.TimeUnixNano)
+}
+
+func TestNewExporter(t *testing.T) {
+	_, err := NewExporter("otel-collector:4318", ProtocolHTTP)
+	assert.ErrorContains(t, err, "must be a http or https URL")
+	_, err = NewExporter("http://otel-collector:4318", "http/json")
+	assert.ErrorContains(t, err, "unknown OTLP protocol")
+}
+
+func TestCounterReset(t *testing.T) {
+	start := time.Unix(1600000000, 0)
+	counter := func(value float64) *dto.MetricFamily {
+		return metricFamily("openstack_loadbalancer_stats_bytes_in_total", dto.MetricType_COUNTER,
+			&dto.Metric{Label: []*dto.LabelPair{label("id", "a")}, Counter: &dto.Counter{Value: proto.Float64(value)}})
+	}
+
+	var previous map[string]cumulativePoint
+	for i, point := range []struct {
+		value float64
+		start time.Time
+	}{
+		{value: 5, start: start},
+		{value: 7, start: start},
+		{value: 2, start: time.Unix(1700000060, 0)},
+		{value: 4, start: time.Unix(1700000060, 0)},
+	} {
+		starts := newCumulativeStarts(start, previous)
+		metric := convertMetricFamily(counter(point.value), "RegionOne", time.Unix(1700000000+int64(i)*60, 0), starts)
+		assert.Equal(t, uint64(point.start.UnixNano()), metric.GetSum().DataPoints[0].StartTimeUnixNano,
+			"the series should restart after the last point before its reset: point %d", i)
+		previous = starts.current
+	}
+}
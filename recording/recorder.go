@@ -0,0 +1,91 @@
+package recording
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/openstack-exporter/openstack-exporter/utils"
+)
+
+// Recorder saves the exchanges of the transports it wraps in a directory.
+type Recorder struct {
+	dir    string
+	logger *slog.Logger
+	// seq is the sequence number of the last exchange, numbering the files of the directory.
+	seq atomic.Int64
+}
+
+// NewRecorder returns a recorder saving the exchanges in dir, created if missing. The exchanges are
+// numbered after the recordings already in dir.
+func NewRecorder(dir string, logger *slog.Logger) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create the recording directory: %w", err)
+	}
+	files, err := recordingFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	r := &Recorder{dir: dir, logger: logger}
+	r.seq.Store(int64(len(files)))
+	return r, nil
+}
+
+// Wrap returns a transport sending the requests with next and recording them with their responses.
+// A failed recording is logged, it doesn't fail the request.
+func (r *Recorder) Wrap(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		// Numbered before the request is sent, so the files follow the order of the requests.
+		seq := int(r.seq.Add(1))
+		reqBody, body, err := readBody(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		sent := req.Clone(req.Context())
+		sent.Body = body
+
+		resp, err := next.RoundTrip(sent)
+		if err != nil {
+			return nil, err
+		}
+		respBody, body, err := readBody(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = body
+
+		if err := r.save(seq, sent, reqBody, resp, respBody); err != nil {
+			r.logger.Warn("Failed to record the request", "method", req.Method, "url", req.URL.Redacted(), "err", err)
+		}
+		return resp, nil
+	})
+}
+
+func (r *Recorder) save(seq int, req *http.Request, reqBody []byte, resp *http.Response, respBody []byte) error {
+	exchange := Exchange{
+		Request:  Request{Method: req.Method, URL: req.URL.Redacted(), Header: redactHeader(req.Header)},
+		Response: Response{StatusCode: resp.StatusCode, Header: redactHeader(resp.Header)},
+	}
+	var err error
+	if exchange.Request.Body, err = newBody(reqBody); err != nil {
+		return err
+	}
+	if exchange.Response.Body, err = newBody(respBody); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(exchange, "", "  ")
+	if err != nil {
+		return err
+	}
+	return utils.WriteFileAtomic(filepath.Join(r.dir, exchangeFileName(seq, req)), append(data, '\n'), 0o644)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
@@ -0,0 +1,187 @@
+// Package recording records the requests to the OpenStack APIs and their responses in a directory,
+// and replays them offline, to reproduce the collection of a cloud without reaching it. Each
+// exchange is a JSON file of the directory, with the tokens, passwords and secrets redacted.
+package recording
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Redacted replaces the values of the recorded credentials.
+const Redacted = "REDACTED"
+
+// redactedHeaders are the headers carrying credentials whose name doesn't match sensitiveName.
+var redactedHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Proxy-Authorization",
+	"Set-Cookie",
+}
+
+// sensitiveName matches the names of the headers, JSON fields and text parameters carrying credentials,
+// ignoring the case: the tokens (i.e: X-Auth-Token), the passwords of the Keystone authentication
+// requests and of the Ironic drivers, the secrets of the application credentials and the temp URL keys
+// of the Swift accounts and containers (i.e: X-Account-Meta-Temp-Url-Key-2). The names only mentioning
+// them, i.e: password_expires_at, are kept.
+var sensitiveName = regexp.MustCompile(`(?i)(temp[-_]?url[-_]?key|secret|passw(or)?d|passcode|token|adminpass)([-_]?[0-9]+)?$`)
+
+// textParameter matches the parameters of the text bodies, i.e: the form values and the lines of
+// a configuration file.
+var textParameter = regexp.MustCompile(`([A-Za-z0-9_.-]+)(\s*[:=]\s*)("[^"]*"|[^\s&,;"]+)`)
+
+// Exchange is a recorded request and its response.
+type Exchange struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body"`
+}
+
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body"`
+}
+
+// Body is the body of a request or a response, in JSON when it's valid JSON so that the recordings
+// are readable and editable, as text otherwise.
+type Body struct {
+	JSON json.RawMessage `json:"json,omitempty"`
+	Text string          `json:"text,omitempty"`
+}
+
+func newBody(data []byte) (Body, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return Body{}, nil
+	}
+	if !json.Valid(data) {
+		return Body{Text: redactText(string(data))}, nil
+	}
+	redacted, err := redactJSON(data)
+	if err != nil {
+		return Body{}, err
+	}
+	return Body{JSON: redacted}, nil
+}
+
+// Bytes returns the content of the body.
+func (b Body) Bytes() []byte {
+	if len(b.JSON) > 0 {
+		return b.JSON
+	}
+	return []byte(b.Text)
+}
+
+// redactJSON redacts the credentials of a JSON document: the string fields whose name matches
+// sensitiveName, and the id of the token objects of the Keystone authentication requests and v2
+// responses.
+func redactJSON(data []byte) (json.RawMessage, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+	return json.Marshal(redactValue(document))
+}
+
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if _, ok := field.(string); ok && sensitiveName.MatchString(key) {
+				v[key] = Redacted
+				continue
+			}
+			if token, ok := field.(map[string]any); ok && key == "token" {
+				if _, ok := token["id"].(string); ok {
+					token["id"] = Redacted
+				}
+			}
+			v[key] = redactValue(field)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+// redactHeader returns a copy of the header with the credentials redacted.
+func redactHeader(header http.Header) http.Header {
+	redacted := header.Clone()
+	for name := range redacted {
+		if slices.Contains(redactedHeaders, name) || sensitiveName.MatchString(name) {
+			redacted.Set(name, Redacted)
+		}
+	}
+	// The recorded bodies are decoded, and may be redacted.
+	redacted.Del("Content-Length")
+	redacted.Del("Content-Encoding")
+	return redacted
+}
+
+// redactText redacts the credentials of a text body: the values of the parameters whose name matches
+// sensitiveName.
+func redactText(text string) string {
+	return textParameter.ReplaceAllStringFunc(text, func(parameter string) string {
+		match := textParameter.FindStringSubmatch(parameter)
+		if !sensitiveName.MatchString(match[1]) {
+			return parameter
+		}
+		return match[1] + match[2] + Redacted
+	})
+}
+
+// readBody reads a body and returns a copy of it, which can be read again.
+func readBody(body io.ReadCloser) ([]byte, io.ReadCloser, error) {
+	if body == nil || body == http.NoBody {
+		return nil, body, nil
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// exchangeKey is the key matching a replayed request with the recorded exchanges.
+func exchangeKey(method, url string) string {
+	return method + " " + url
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// exchangeFileName returns the name of the file of the exchange with the given sequence number, the
+// recordings of a directory being replayed in the order of their names.
+func exchangeFileName(seq int, req *http.Request) string {
+	path := strings.Trim(unsafeFileChars.ReplaceAllString(req.URL.Host+req.URL.Path, "_"), "_")
+	if len(path) > 100 {
+		path = path[:100]
+	}
+	return fmt.Sprintf("%06d-%s-%s.json", seq, req.Method, path)
+}
+
+// ErrNoRecording is the error of the replayed requests without recorded exchange.
+var ErrNoRecording = errors.New("no recorded response")
+
+// recordingFiles returns the recordings of a directory, in the order of their names.
+func recordingFiles(dir string) ([]string, error) {
+	return filepath.Glob(filepath.Join(dir, "[0-9]*-*.json"))
+}
@@ -0,0 +1,181 @@
+package recording
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const authRequest = `{"auth":{"identity":{"methods":["password"],"password":{"user":{"name":"admin","domain":{"id":"default"},"password":"s3cr3t"}}}}}`
+
+// newCloud returns a stub of an OpenStack cloud, whose server list changes on each request.
+func newCloud(t *testing.T) *httptest.Server {
+	var servers atomic.Int64
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v3/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Subject-Token", "gAAAAABtoken")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"token":{"methods":["password"],"user":{"id":"u1","name":"admin"},"expires_at":"2030-01-01T00:00:00Z"}}`)
+	})
+	mux.HandleFunc("GET /compute/v2.1/servers", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "gAAAAABtoken", r.Header.Get("X-Auth-Token"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"servers":[`+strings.Repeat(`{"id":"s"},`, int(servers.Add(1))-1)+`{"id":"s"}]}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func do(t *testing.T, client *http.Client, method, url, body string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("X-Auth-Token", "gAAAAABtoken")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(data)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	cloud := newCloud(t)
+	dir := t.TempDir()
+	recorder, err := NewRecorder(dir, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	client := &http.Client{Transport: recorder.Wrap(http.DefaultTransport)}
+
+	resp, _ := do(t, client, http.MethodPost, cloud.URL+"/v3/auth/tokens", authRequest)
+	assert.Equal(t, "gAAAAABtoken", resp.Header.Get("X-Subject-Token"), "the recorded responses should not be redacted")
+	_, firstServers := do(t, client, http.MethodGet, cloud.URL+"/compute/v2.1/servers", "")
+	_, secondServers := do(t, client, http.MethodGet, cloud.URL+"/compute/v2.1/servers", "")
+
+	files, err := recordingFiles(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	assert.True(t, strings.HasPrefix(filepath.Base(files[0]), "000001-POST-127.0.0.1_"), files[0])
+	assert.True(t, strings.HasSuffix(files[0], "_v3_auth_tokens.json"), files[0])
+
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "s3cr3t")
+	assert.NotContains(t, string(data), "gAAAAABtoken")
+	var exchange Exchange
+	require.NoError(t, json.Unmarshal(data, &exchange))
+	assert.Equal(t, Redacted, exchange.Response.Header.Get("X-Subject-Token"))
+	assert.JSONEq(t, strings.Replace(authRequest, "s3cr3t", Redacted, 1), string(exchange.Request.Body.JSON))
+	assert.Equal(t, http.StatusCreated, exchange.Response.StatusCode)
+
+	// The cloud is gone, the replay doesn't reach it.
+	cloud.Close()
+	replayer, err := NewReplayer(dir)
+	require.NoError(t, err)
+	client = &http.Client{Transport: replayer.Wrap(http.DefaultTransport)}
+
+	resp, _ = do(t, client, http.MethodPost, cloud.URL+"/v3/auth/tokens", authRequest)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, Redacted, resp.Header.Get("X-Subject-Token"))
+	_, servers := do(t, client, http.MethodGet, cloud.URL+"/compute/v2.1/servers", "")
+	assert.JSONEq(t, firstServers, servers)
+	_, servers = do(t, client, http.MethodGet, cloud.URL+"/compute/v2.1/servers", "")
+	assert.JSONEq(t, secondServers, servers, "the responses should be replayed in the order of the recording")
+	_, servers = do(t, client, http.MethodGet, cloud.URL+"/compute/v2.1/servers", "")
+	assert.JSONEq(t, secondServers, servers, "the last response should be replayed once all are served")
+
+	_, err = client.Get(cloud.URL + "/compute/v2.1/flavors")
+	assert.ErrorIs(t, err, ErrNoRecording)
+
+	recorder, err = NewRecorder(dir, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), recorder.seq.Load(), "the recordings should be numbered after the existing ones")
+	_, err = NewReplayer(t.TempDir())
+	assert.ErrorContains(t, err, "no recording")
+}
+
+func TestRedactJSON(t *testing.T) {
+	redacted, err := redactJSON([]byte(`{
+		"auth": {"identity": {"methods": ["token", "application_credential"],
+			"token": {"id": "gAAAAAB"},
+			"application_credential": {"id": "c1", "secret": "s"}}},
+		"access": {"token": {"id": "gAAAAAC", "expires": "2030-01-01T00:00:00Z"}},
+		"users": [{"id": "u1", "password": "p", "password_expires_at": null, "size": 12345678901234567890}],
+		"nodes": [{"driver_info": {"ipmi_Password": "p", "ipmi_username": "admin"}, "secret_ref": null}],
+		"options": {"password_expires_at": "2030-01-01T00:00:00Z", "Client-Secret": "s"}
+	}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"auth": {"identity": {"methods": ["token", "application_credential"],
+			"token": {"id": "REDACTED"},
+			"application_credential": {"id": "c1", "secret": "REDACTED"}}},
+		"access": {"token": {"id": "REDACTED", "expires": "2030-01-01T00:00:00Z"}},
+		"users": [{"id": "u1", "password": "REDACTED", "password_expires_at": null, "size": 12345678901234567890}],
+		"nodes": [{"driver_info": {"ipmi_Password": "REDACTED", "ipmi_username": "admin"}, "secret_ref": null}],
+		"options": {"password_expires_at": "2030-01-01T00:00:00Z", "Client-Secret": "REDACTED"}
+	}`, string(redacted))
+	assert.Contains(t, string(redacted), "12345678901234567890", "the numbers should be kept as is")
+}
+
+func TestRedactSwiftAccount(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /object-store/v1/AUTH_admin", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Account-Meta-Temp-Url-Key", "k3y")
+		w.Header().Set("X-Account-Meta-Temp-Url-Key-2", "k3y2")
+		w.Header().Set("X-Account-Container-Count", "1")
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = io.WriteString(w, "backups\n")
+	})
+	mux.HandleFunc("HEAD /object-store/v1/AUTH_admin/backups", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-container-meta-temp-url-key", "c0nta1ner")
+		w.Header().Set("X-Container-Object-Count", "3")
+	})
+	mux.HandleFunc("POST /object-store/v1/AUTH_admin/backups", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	cloud := httptest.NewServer(mux)
+	defer cloud.Close()
+
+	dir := t.TempDir()
+	recorder, err := NewRecorder(dir, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	client := &http.Client{Transport: recorder.Wrap(http.DefaultTransport)}
+	resp, body := do(t, client, http.MethodGet, cloud.URL+"/object-store/v1/AUTH_admin", "")
+	assert.Equal(t, "k3y", resp.Header.Get("X-Account-Meta-Temp-Url-Key"), "the recorded responses should not be redacted")
+	assert.Equal(t, "backups\n", body)
+	do(t, client, http.MethodHead, cloud.URL+"/object-store/v1/AUTH_admin/backups", "")
+	do(t, client, http.MethodPost, cloud.URL+"/object-store/v1/AUTH_admin/backups", "temp_url_key=c0nta1ner&Secret: s3cr3t&read=.r:*")
+
+	files, err := recordingFiles(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	var exchanges []Exchange
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		for _, secret := range []string{"k3y", "c0nta1ner", "s3cr3t", "gAAAAABtoken"} {
+			assert.NotContains(t, string(data), secret, "%s should not be recorded", file)
+		}
+		var exchange Exchange
+		require.NoError(t, json.Unmarshal(data, &exchange))
+		exchanges = append(exchanges, exchange)
+	}
+	assert.Equal(t, Redacted, exchanges[0].Response.Header.Get("X-Account-Meta-Temp-Url-Key"))
+	assert.Equal(t, Redacted, exchanges[0].Response.Header.Get("X-Account-Meta-Temp-Url-Key-2"))
+	assert.Equal(t, "1", exchanges[0].Response.Header.Get("X-Account-Container-Count"))
+	assert.Equal(t, "backups\n", exchanges[0].Response.Body.Text)
+	assert.Equal(t, Redacted, exchanges[1].Response.Header.Get("X-Container-Meta-Temp-Url-Key"))
+	assert.Equal(t, "3", exchanges[1].Response.Header.Get("X-Container-Object-Count"))
+	assert.Equal(t, "temp_url_key=REDACTED&Secret: REDACTED&read=.r:*", exchanges[2].Request.Body.Text)
+}
@@ -0,0 +1,87 @@
+package recording
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// Replayer is a transport answering the requests with the responses recorded in a directory.
+// The exchanges recorded for the same method and URL are replayed in the order of the recording,
+// the last one being replayed once they are all served, so a recording can serve several scrapes.
+type Replayer struct {
+	mu        sync.Mutex
+	responses map[string][]Response
+	served    map[string]int
+}
+
+// NewReplayer loads the exchanges recorded in dir.
+func NewReplayer(dir string) (*Replayer, error) {
+	files, err := recordingFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no recording in %s", dir)
+	}
+
+	r := &Replayer{responses: make(map[string][]Response), served: make(map[string]int)}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var exchange Exchange
+		if err := json.Unmarshal(data, &exchange); err != nil {
+			return nil, fmt.Errorf("invalid recording %s: %w", file, err)
+		}
+		key := exchangeKey(exchange.Request.Method, exchange.Request.URL)
+		r.responses[key] = append(r.responses[key], exchange.Response)
+	}
+	return r, nil
+}
+
+// Wrap returns the replayer, the requests never reach next.
+func (r *Replayer) Wrap(next http.RoundTripper) http.RoundTripper {
+	return r
+}
+
+// RoundTrip answers the request with its recorded response, ErrNoRecording if there is none.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_, _ = io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+
+	key := exchangeKey(req.Method, req.URL.Redacted())
+	r.mu.Lock()
+	responses := r.responses[key]
+	if len(responses) == 0 {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("%w for %s %s", ErrNoRecording, req.Method, req.URL.Redacted())
+	}
+	recorded := responses[min(r.served[key], len(responses)-1)]
+	r.served[key]++
+	r.mu.Unlock()
+
+	header := recorded.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	body := recorded.Body.Bytes()
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
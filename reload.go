@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gophercloud/utils/openstack/clientconfig"
+	"github.com/openstack-exporter/openstack-exporter/cache"
+	"github.com/openstack-exporter/openstack-exporter/config"
+	"github.com/openstack-exporter/openstack-exporter/exporters"
+)
+
+var reloadMu sync.Mutex
+
+// cacheReload notifies the cache background service of a reload.
+var cacheReload = make(chan struct{}, 1)
+
+// resetExporters drops the exporters built from the previous configuration, replaced in tests.
+var resetExporters = exporters.ResetExporters
+
+// reload re-reads clouds.yaml, the exporter config file and the Vault secret, then drops the exporters
+// and clients built from the previous configuration. The previous configuration is kept on error.
+func reload(logger *slog.Logger) error {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	logger.Info("Reloading the configuration")
+
+	var cfg *config.File
+	if *configFile != "" {
+		var err error
+		if cfg, err = loadExporterConfig(*configFile); err != nil {
+			return err
+		}
+	} else if _, err := clientconfig.LoadCloudsYAML(); err != nil {
+		return fmt.Errorf("failed to load clouds.yaml: %w", err)
+	}
+
+	if err := SetPasswordIfVaultIsUsed(logger); err != nil {
+		return err
+	}
+
+	exporterConfig.Store(cfg)
+	resetExporters()
+
+	select {
+	case cacheReload <- struct{}{}:
+	default:
+	}
+
+	logger.Info("Configuration reloaded")
+	return nil
+}
+
+// reloadHandler reloads the configuration on POST requests.
+func reloadHandler(logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost && r.Method != http.MethodPut {
+			w.Header().Set("Allow", "POST, PUT")
+			http.Error(w, "Only POST or PUT requests allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if err := reload(logger); err != nil {
+			logger.Error("Failed to reload the configuration", "err", err)
+			http.Error(w, fmt.Sprintf("failed to reload config: %s", err), http.StatusInternalServerError)
+		}
+	}
+}
+
+// flushCloudCaches flushes the expired cloud caches, and the caches of the clouds
+// which are no longer collected.
+func flushCloudCaches(services map[string]*bool, logger *slog.Logger) {
+	clouds := []string{*cloud}
+	if *multiCloud {
+		cloudsConfig, err := clientconfig.LoadCloudsYAML()
+		if err != nil {
+			// Keep the caches of every cloud until they expire.
+			logger.Error("Failed to load clouds.yaml", "err", err)
+			clouds = nil
+		} else {
+			clouds = clouds[:0]
+			for cloud := range cloudsConfig {
+				clouds = append(clouds, cloud)
+			}
+		}
+	}
+
+	options := cloudOptions(services)
+	cache.FlushExpiredCloudCachesFunc(func(cloud string) time.Duration {
+		if clouds != nil && !slices.Contains(clouds, cloud) {
+			return 0
+		}
+		return options(cloud).CacheTTL
+	})
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openstack-exporter/openstack-exporter/cache"
+	"github.com/openstack-exporter/openstack-exporter/config"
+	"github.com/openstack-exporter/openstack-exporter/exporters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCloudsYAML = `
+clouds:
+  mycloud:
+    auth:
+      auth_url: http://test.cloud:5000/v3
+  othercloud:
+    auth:
+      auth_url: http://other.cloud:5000/v3
+`
+
+// setFlag sets a flag for the duration of a test.
+func setFlag[T any](t *testing.T, flag *T, value T) {
+	previous := *flag
+	*flag = value
+	t.Cleanup(func() { *flag = previous })
+}
+
+// setupReload writes clouds.yaml and the exporter config file, and returns the path of the latter.
+func setupReload(t *testing.T) string {
+	dir := t.TempDir()
+	cloudsFile := filepath.Join(dir, "clouds.yaml")
+	require.NoError(t, os.WriteFile(cloudsFile, []byte(testCloudsYAML), 0o600))
+	t.Setenv("OS_CLIENT_CONFIG_FILE", cloudsFile)
+	setFlag(t, osClientConfig, cloudsFile)
+
+	configPath := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("clouds:\n  mycloud:\n    endpoint_type: internal\n"), 0o600))
+	setFlag(t, configFile, configPath)
+
+	previous := exporterConfig.Load()
+	t.Cleanup(func() { exporterConfig.Store(previous) })
+	t.Cleanup(func() {
+		select {
+		case <-cacheReload:
+		default:
+		}
+	})
+	return configPath
+}
+
+func TestReload(t *testing.T) {
+	configPath := setupReload(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	resets := 0
+	resetExporters = func() { resets++ }
+	t.Cleanup(func() { resetExporters = exporters.ResetExporters })
+
+	require.NoError(t, reload(logger))
+	cfg := exporterConfig.Load()
+	require.NotNil(t, cfg)
+	assert.Equal(t, "internal", cfg.Options("mycloud", config.Options{}).EndpointType)
+	assert.Equal(t, 1, resets, "the exporters of the previous configuration should be dropped")
+	select {
+	case <-cacheReload:
+	default:
+		assert.Fail(t, "the cache background service should be notified of the reload")
+	}
+
+	require.NoError(t, os.WriteFile(configPath, []byte("clouds:\n  unknown:\n    endpoint_type: admin\n"), 0o600))
+	assert.ErrorContains(t, reload(logger), "cloud not found in clouds.yaml")
+	assert.Same(t, cfg, exporterConfig.Load(), "the previous configuration should be kept when the reload fails")
+	assert.Equal(t, 1, resets, "the exporters should be kept when the reload fails")
+	select {
+	case <-cacheReload:
+		assert.Fail(t, "the cache background service should not be notified of a failed reload")
+	default:
+	}
+}
+
+func TestReloadHandler(t *testing.T) {
+	configPath := setupReload(t)
+	handler := reloadHandler(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/-/reload", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "POST, PUT", w.Header().Get("Allow"))
+	assert.Nil(t, exporterConfig.Load(), "a GET request should not reload the configuration")
+
+	w = httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodPost, "/-/reload", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotNil(t, exporterConfig.Load())
+
+	require.NoError(t, os.WriteFile(configPath, []byte("clouds: [invalid"), 0o600))
+	w = httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodPut, "/-/reload", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "failed to reload config")
+}
+
+func TestFlushCloudCaches(t *testing.T) {
+	setupReload(t)
+	setFlag(t, multiCloud, true)
+	setFlag(t, cacheTTL, time.Hour)
+	services := enabledServices()
+
+	cacheBackend := cache.GetCache()
+	for _, cloud := range []string{"mycloud", "othercloud", "removedcloud"} {
+		cacheBackend.SetCloudCache(cloud, cache.NewCloudCache())
+	}
+	t.Cleanup(func() { cache.FlushExpiredCloudCaches(0) })
+
+	flushCloudCaches(services, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	for cloud, kept := range map[string]bool{"mycloud": true, "othercloud": true, "removedcloud": false} {
+		_, exists := cacheBackend.GetCloudCache(cloud)
+		assert.Equal(t, kept, exists, "only the caches of the clouds removed from clouds.yaml should be flushed: %s", cloud)
+	}
+
+	setFlag(t, cacheTTL, 0)
+	flushCloudCaches(services, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	_, exists := cacheBackend.GetCloudCache("mycloud")
+	assert.False(t, exists, "the expired caches should be flushed")
+}
@@ -0,0 +1,221 @@
+// Package remotewrite sends the cached metrics of the clouds to a Prometheus remote write endpoint,
+// i.e: Mimir or Thanos Receive. The samples wait in a bounded in-memory queue, without WAL: the
+// oldest samples are dropped when the queue is full, and all of them are lost on restart.
+package remotewrite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/common/version"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Config configures the remote write client.
+type Config struct {
+	URL string
+	// Username and Password set the basic authentication of the requests, when Username is set.
+	Username string
+	Password string
+	// Client sends the requests, http.DefaultClient if nil.
+	Client *http.Client
+	// BatchSize is the maximum number of samples sent by a request.
+	BatchSize int
+	// QueueSize is the maximum number of samples waiting to be sent.
+	QueueSize int
+	// MinBackoff and MaxBackoff bound the exponential backoff between the retries of a request.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// MaxRetries is the number of retries of a request failing with a recoverable error,
+	// a server error or a rate limit, before its samples are dropped.
+	MaxRetries int
+}
+
+type label struct {
+	name  string
+	value string
+}
+
+// timeSeries is a sample with its labels, sorted by name and including the __name__ label.
+type timeSeries struct {
+	labels    []label
+	value     float64
+	timestamp int64
+}
+
+// Client queues the samples and sends them in batches to the remote write endpoint.
+type Client struct {
+	config Config
+	logger *slog.Logger
+
+	mu    sync.Mutex
+	queue []timeSeries
+	// sent are the times of the cloud caches already queued, by cloud.
+	sent map[string]time.Time
+	// pending is signaled when samples are queued.
+	pending chan struct{}
+}
+
+// NewClient returns a client sending to the remote write endpoint of the config, once running.
+func NewClient(config Config, logger *slog.Logger) (*Client, error) {
+	u, err := url.Parse(config.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid remote write URL %q, must be a http or https URL", config.URL)
+	}
+	if config.BatchSize < 1 || config.QueueSize < config.BatchSize {
+		return nil, fmt.Errorf("invalid remote write queue, the batch size %d must be positive and not exceed the queue size %d", config.BatchSize, config.QueueSize)
+	}
+	if config.MinBackoff <= 0 || config.MaxBackoff < config.MinBackoff {
+		return nil, fmt.Errorf("invalid remote write backoff, the min backoff %s must be positive and not exceed the max backoff %s", config.MinBackoff, config.MaxBackoff)
+	}
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+	return &Client{config: config, logger: logger, sent: make(map[string]time.Time), pending: make(chan struct{}, 1)}, nil
+}
+
+// enqueue queues the samples, dropping the oldest ones beyond the size of the queue.
+func (c *Client) enqueue(series []timeSeries) {
+	c.mu.Lock()
+	c.queue = append(c.queue, series...)
+	if dropped := len(c.queue) - c.config.QueueSize; dropped > 0 {
+		c.queue = slices.Clone(c.queue[dropped:])
+		c.logger.Warn("Remote write queue is full, dropping the oldest samples", "samples", dropped)
+	}
+	c.mu.Unlock()
+
+	select {
+	case c.pending <- struct{}{}:
+	default:
+	}
+}
+
+// next removes the next batch of samples from the queue.
+func (c *Client) next() []timeSeries {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := min(len(c.queue), c.config.BatchSize)
+	batch := slices.Clone(c.queue[:n])
+	c.queue = c.queue[n:]
+	return batch
+}
+
+// Run sends the queued samples until ctx is done. The samples of a request failing with an
+// unrecoverable error, or after its retries, are dropped.
+func (c *Client) Run(ctx context.Context) {
+	for {
+		batch := c.next()
+		if len(batch) == 0 {
+			select {
+			case <-c.pending:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err := c.sendWithRetries(ctx, batch); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			c.logger.Error("Failed to send the samples to the remote write endpoint, dropping them", "samples", len(batch), "err", err)
+		}
+	}
+}
+
+// recoverableError is an error of a request worth retrying.
+type recoverableError struct {
+	error
+}
+
+func (c *Client) sendWithRetries(ctx context.Context, batch []timeSeries) error {
+	body := snappy.Encode(nil, encodeWriteRequest(batch))
+	backoff := c.config.MinBackoff
+	for try := 0; ; try++ {
+		err := c.send(ctx, body)
+		var recoverable recoverableError
+		if err == nil || !errors.As(err, &recoverable) || try >= c.config.MaxRetries {
+			return err
+		}
+		c.logger.Warn("Failed to send the samples to the remote write endpoint, retrying", "retry", try+1, "backoff", backoff, "err", err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff = min(2*backoff, c.config.MaxBackoff)
+	}
+}
+
+// send makes a remote write request. The network errors, the server errors and the rate limits
+// are recoverable.
+func (c *Client) send(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", "openstack-exporter/"+version.Version)
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	if c.config.Username != "" {
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+
+	resp, err := c.config.Client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		return recoverableError{err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("unexpected status code %d while sending to %s: %s", resp.StatusCode, c.config.URL, bytes.TrimSpace(message))
+	if resp.StatusCode/100 == 5 || resp.StatusCode == http.StatusTooManyRequests {
+		return recoverableError{err}
+	}
+	return err
+}
+
+// encodeWriteRequest encodes the samples as the protobuf WriteRequest of the remote write protocol,
+// one TimeSeries per sample.
+func encodeWriteRequest(series []timeSeries) []byte {
+	var request, ts, field []byte
+	for _, s := range series {
+		ts = ts[:0]
+		for _, l := range s.labels {
+			field = protowire.AppendTag(field[:0], 1, protowire.BytesType)
+			field = protowire.AppendString(field, l.name)
+			field = protowire.AppendTag(field, 2, protowire.BytesType)
+			field = protowire.AppendString(field, l.value)
+			ts = protowire.AppendTag(ts, 1, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, field)
+		}
+		field = protowire.AppendTag(field[:0], 1, protowire.Fixed64Type)
+		field = protowire.AppendFixed64(field, math.Float64bits(s.value))
+		field = protowire.AppendTag(field, 2, protowire.VarintType)
+		field = protowire.AppendVarint(field, uint64(s.timestamp))
+		ts = protowire.AppendTag(ts, 2, protowire.BytesType)
+		ts = protowire.AppendBytes(ts, field)
+
+		request = protowire.AppendTag(request, 1, protowire.BytesType)
+		request = protowire.AppendBytes(request, ts)
+	}
+	return request
+}
@@ -0,0 +1,244 @@
+package remotewrite
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/openstack-exporter/openstack-exporter/cache"
+	"github.com/openstack-exporter/openstack-exporter/config"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// decodeWriteRequest decodes the samples of a WriteRequest, as a remote write receiver.
+func decodeWriteRequest(t *testing.T, request []byte) []timeSeries {
+	t.Helper()
+	fields := func(b []byte, fn func(num protowire.Number, typ protowire.Type, b []byte) int) {
+		for len(b) > 0 {
+			num, typ, n := protowire.ConsumeTag(b)
+			require.GreaterOrEqual(t, n, 0)
+			b = b[n:]
+			n = fn(num, typ, b)
+			require.GreaterOrEqual(t, n, 0)
+			b = b[n:]
+		}
+	}
+
+	series := []timeSeries{}
+	fields(request, func(_ protowire.Number, _ protowire.Type, b []byte) int {
+		tsBytes, n := protowire.ConsumeBytes(b)
+		s := timeSeries{}
+		fields(tsBytes, func(num protowire.Number, _ protowire.Type, b []byte) int {
+			field, n := protowire.ConsumeBytes(b)
+			if num == 1 {
+				l := label{}
+				fields(field, func(num protowire.Number, _ protowire.Type, b []byte) int {
+					value, n := protowire.ConsumeString(b)
+					if num == 1 {
+						l.name = value
+					} else {
+						l.value = value
+					}
+					return n
+				})
+				s.labels = append(s.labels, l)
+				return n
+			}
+			fields(field, func(num protowire.Number, typ protowire.Type, b []byte) int {
+				if num == 1 {
+					bits, n := protowire.ConsumeFixed64(b)
+					s.value = math.Float64frombits(bits)
+					return n
+				}
+				value, n := protowire.ConsumeVarint(b)
+				s.timestamp = int64(value)
+				return n
+			})
+			return n
+		})
+		series = append(series, s)
+		return n
+	})
+	return series
+}
+
+// receiver is a stub of a remote write endpoint, answering the requests with the given status codes.
+type receiver struct {
+	t        *testing.T
+	mu       sync.Mutex
+	statuses []int
+	requests [][]timeSeries
+	received chan struct{}
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	assert.Equal(r.t, "snappy", req.Header.Get("Content-Encoding"))
+	assert.Equal(r.t, "0.1.0", req.Header.Get("X-Prometheus-Remote-Write-Version"))
+	compressed, err := io.ReadAll(req.Body)
+	require.NoError(r.t, err)
+	body, err := snappy.Decode(nil, compressed)
+	require.NoError(r.t, err)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	status := http.StatusNoContent
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	if status == http.StatusNoContent {
+		r.requests = append(r.requests, decodeWriteRequest(r.t, body))
+	}
+	w.WriteHeader(status)
+	r.received <- struct{}{}
+}
+
+func newReceiver(t *testing.T, statuses ...int) (*receiver, *httptest.Server) {
+	r := &receiver{t: t, statuses: statuses, received: make(chan struct{}, 10)}
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return r, server
+}
+
+func testConfig(url string) Config {
+	return Config{URL: url, BatchSize: 3, QueueSize: 10, MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond, MaxRetries: 2}
+}
+
+func runClient(t *testing.T, client *Client) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		client.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+func waitRequests(t *testing.T, r *receiver, count int) {
+	t.Helper()
+	for range count {
+		select {
+		case <-r.received:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the remote write endpoint didn't receive the request")
+		}
+	}
+}
+
+func gauge(name string, value float64, labels ...string) *dto.MetricFamily {
+	m := &dto.Metric{Gauge: &dto.Gauge{Value: proto.Float64(value)}}
+	for i := 0; i < len(labels); i += 2 {
+		m.Label = append(m.Label, &dto.LabelPair{Name: proto.String(labels[i]), Value: proto.String(labels[i+1])})
+	}
+	return &dto.MetricFamily{Name: proto.String(name), Type: dto.MetricType_GAUGE.Enum(), Metric: []*dto.Metric{m}}
+}
+
+func TestEnqueueCache(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	r, server := newReceiver(t)
+	client, err := NewClient(testConfig(server.URL), logger)
+	require.NoError(t, err)
+
+	cloudCache := cache.NewCloudCache()
+	cloudCache.SetMetricFamilyCache("/network/openstack_neutron_up", cache.MetricFamilyCache{Service: "network", MF: gauge("openstack_neutron_up", 1)})
+	cloudCache.SetMetricFamilyCache("/network/openstack_neutron_networks", cache.MetricFamilyCache{Service: "network", MF: gauge("openstack_neutron_networks", 4, "env", "dev")})
+	cloudCache.SetMetricFamilyCache("/volume/openstack_cinder_up", cache.MetricFamilyCache{Service: "volume", MF: gauge("openstack_cinder_up", 1)})
+	cloudCache.SetMetricFamilyCache("/compute/openstack_api_request_duration_seconds", cache.MetricFamilyCache{Service: "compute", MF: &dto.MetricFamily{
+		Name: proto.String("openstack_api_request_duration_seconds"),
+		Type: dto.MetricType_HISTOGRAM.Enum(),
+		Metric: []*dto.Metric{{Histogram: &dto.Histogram{
+			SampleCount: proto.Uint64(3),
+			SampleSum:   proto.Float64(0.6),
+			Bucket:      []*dto.Bucket{{UpperBound: proto.Float64(0.25), CumulativeCount: proto.Uint64(2)}},
+		}}},
+	}})
+	cache.GetCache().SetCloudCache("test.cloud", cloudCache)
+	defer cache.FlushExpiredCloudCaches(0)
+	cached, _ := cache.GetCache().GetCloudCache("test.cloud")
+	timestamp := cached.Time.UnixMilli()
+
+	cloudOptions := func(string) config.Options {
+		return config.Options{EnabledServices: []string{"network", "compute"}, ExternalLabels: map[string]string{"env": "prod"}}
+	}
+	require.NoError(t, client.EnqueueCache(false, "test.cloud", cloudOptions))
+	require.NoError(t, client.EnqueueCache(false, "test.cloud", cloudOptions))
+	runClient(t, client)
+	waitRequests(t, r, 2)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	require.Len(t, r.requests, 2, "the samples should be sent in batches, and the cache queued once")
+	assert.Len(t, r.requests[0], 3)
+	samples := append(r.requests[0], r.requests[1]...)
+	labels := func(name string, extra ...label) []label {
+		return append([]label{{"__name__", name}, {"cloud", "test.cloud"}}, extra...)
+	}
+	assert.Equal(t, []timeSeries{
+		{labels: labels("openstack_api_request_duration_seconds_bucket", label{"env", "prod"}, label{"le", "0.25"}), value: 2, timestamp: timestamp},
+		{labels: labels("openstack_api_request_duration_seconds_bucket", label{"env", "prod"}, label{"le", "+Inf"}), value: 3, timestamp: timestamp},
+		{labels: labels("openstack_api_request_duration_seconds_sum", label{"env", "prod"}), value: 0.6, timestamp: timestamp},
+		{labels: labels("openstack_api_request_duration_seconds_count", label{"env", "prod"}), value: 3, timestamp: timestamp},
+		{labels: labels("openstack_neutron_networks", label{"env", "dev"}), value: 4, timestamp: timestamp},
+		{labels: labels("openstack_neutron_up", label{"env", "prod"}), value: 1, timestamp: timestamp},
+	}, samples, "the external labels should not override the labels of the metrics")
+}
+
+func TestRetries(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	series := []timeSeries{{labels: []label{{"__name__", "openstack_neutron_up"}}, value: 1, timestamp: 1000}}
+
+	r, server := newReceiver(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	client, err := NewClient(testConfig(server.URL), logger)
+	require.NoError(t, err)
+	runClient(t, client)
+	client.enqueue(series)
+	waitRequests(t, r, 3)
+	r.mu.Lock()
+	assert.Equal(t, [][]timeSeries{series}, r.requests, "the recoverable errors should be retried")
+	r.mu.Unlock()
+
+	r, server = newReceiver(t, http.StatusBadRequest, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	client, err = NewClient(testConfig(server.URL), logger)
+	require.NoError(t, err)
+	runClient(t, client)
+	client.enqueue(series)
+	waitRequests(t, r, 1)
+	client.enqueue(series)
+	waitRequests(t, r, 3)
+	r.mu.Lock()
+	assert.Empty(t, r.requests, "the samples should be dropped after an unrecoverable error or the last retry")
+	r.mu.Unlock()
+}
+
+func TestQueueSize(t *testing.T) {
+	client, err := NewClient(testConfig("http://remote-write.example.com/api/v1/push"), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+
+	series := make([]timeSeries, 12)
+	for i := range series {
+		series[i] = timeSeries{labels: []label{{"__name__", "openstack_neutron_up"}}, value: 1, timestamp: int64(i)}
+	}
+	client.enqueue(series)
+	batch := client.next()
+	assert.Len(t, batch, 3)
+	assert.Equal(t, int64(2), batch[0].timestamp, "the oldest samples should be dropped when the queue is full")
+	assert.Len(t, client.queue, 7)
+
+	_, err = NewClient(Config{URL: "http://remote-write.example.com/api/v1/push", BatchSize: 10, QueueSize: 5, MinBackoff: time.Second, MaxBackoff: time.Second}, nil)
+	assert.ErrorContains(t, err, "invalid remote write queue")
+	_, err = NewClient(Config{URL: "remote-write.example.com"}, nil)
+	assert.ErrorContains(t, err, "invalid remote write URL")
+}
@@ -0,0 +1,122 @@
+package remotewrite
+
+import (
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/openstack-exporter/openstack-exporter/cache"
+	"github.com/openstack-exporter/openstack-exporter/config"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// EnqueueCache queues the samples of the cached metrics of the enabled services of the clouds,
+// with the external labels of each cloud and a cloud label set to the name of the cloud, unless
+// the external labels set it. The cache of a cloud is queued once, after each of its updates.
+func (c *Client) EnqueueCache(multiCloud bool, cloud string, cloudOptions func(cloud string) config.Options) error {
+	clouds, err := cache.CollectedClouds(multiCloud, cloud)
+	if err != nil {
+		return err
+	}
+
+	cacheBackend := cache.GetCache()
+	for _, cloud := range clouds {
+		cloudCache, exists := cacheBackend.GetCloudCache(cloud)
+		if !exists {
+			continue
+		}
+		c.mu.Lock()
+		queued := c.sent[cloud].Equal(cloudCache.Time)
+		c.sent[cloud] = cloudCache.Time
+		c.mu.Unlock()
+		if queued {
+			continue
+		}
+
+		options := cloudOptions(cloud)
+		externalLabels := map[string]string{"cloud": cloud}
+		maps.Copy(externalLabels, options.ExternalLabels)
+		c.enqueue(cacheSeries(cloudCache, options.EnabledServices, externalLabels))
+	}
+	return nil
+}
+
+// cacheSeries returns the samples of the cached metric families of the services. The external
+// labels are added to the series, unless they already have the label. The samples without timestamp
+// are timestamped with the time of the cache.
+func cacheSeries(cloudCache cache.CloudCache, services []string, externalLabels map[string]string) []timeSeries {
+	timestamp := cloudCache.Time.UnixMilli()
+	series := []timeSeries{}
+	for _, key := range slices.Sorted(maps.Keys(cloudCache.MetricFamilyCaches)) {
+		mfCache := cloudCache.MetricFamilyCaches[key]
+		if !slices.Contains(services, mfCache.Service) {
+			continue
+		}
+		name := mfCache.MF.GetName()
+		for _, m := range mfCache.MF.GetMetric() {
+			ts := timestamp
+			if m.TimestampMs != nil {
+				ts = m.GetTimestampMs()
+			}
+			sample := func(suffix string, value float64, extra ...label) {
+				series = append(series, timeSeries{labels: seriesLabels(name+suffix, m, extra, externalLabels), value: value, timestamp: ts})
+			}
+
+			switch mfCache.MF.GetType() {
+			case dto.MetricType_GAUGE:
+				sample("", m.GetGauge().GetValue())
+			case dto.MetricType_COUNTER:
+				sample("", m.GetCounter().GetValue())
+			case dto.MetricType_UNTYPED:
+				sample("", m.GetUntyped().GetValue())
+			case dto.MetricType_HISTOGRAM:
+				h := m.GetHistogram()
+				infSeen := false
+				for _, bucket := range h.GetBucket() {
+					infSeen = infSeen || math.IsInf(bucket.GetUpperBound(), +1)
+					sample("_bucket", float64(bucket.GetCumulativeCount()), label{"le", formatFloat(bucket.GetUpperBound())})
+				}
+				if !infSeen {
+					sample("_bucket", float64(h.GetSampleCount()), label{"le", "+Inf"})
+				}
+				sample("_sum", h.GetSampleSum())
+				sample("_count", float64(h.GetSampleCount()))
+			case dto.MetricType_SUMMARY:
+				s := m.GetSummary()
+				for _, quantile := range s.GetQuantile() {
+					sample("", quantile.GetValue(), label{"quantile", formatFloat(quantile.GetQuantile())})
+				}
+				sample("_sum", s.GetSampleSum())
+				sample("_count", float64(s.GetSampleCount()))
+			}
+		}
+	}
+	return series
+}
+
+// seriesLabels returns the labels of a series sorted by name: its name, the labels of the metric,
+// the extra labels of the series and the external labels missing from the metric.
+func seriesLabels(name string, m *dto.Metric, extra []label, externalLabels map[string]string) []label {
+	labels := make([]label, 0, 1+len(m.GetLabel())+len(extra)+len(externalLabels))
+	labels = append(labels, label{"__name__", name})
+	for _, l := range m.GetLabel() {
+		labels = append(labels, label{l.GetName(), l.GetValue()})
+	}
+	labels = append(labels, extra...)
+	for externalName, value := range externalLabels {
+		if !slices.ContainsFunc(labels, func(l label) bool { return l.name == externalName }) {
+			labels = append(labels, label{externalName, value})
+		}
+	}
+	slices.SortFunc(labels, func(a, b label) int { return strings.Compare(a.name, b.name) })
+	return labels
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, +1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
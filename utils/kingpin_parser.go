@@ -3,6 +3,7 @@ package utils
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"regexp"
 	"slices"
 	"strings"
@@ -13,8 +14,22 @@ import (
 var (
 	ErrLabelDup  = errors.New("duplicate label")
 	ErrLabelName = errors.New("bad label name")
+	ErrResource  = errors.New("unknown resource")
 )
 
+// MappedResources are the resource types whose metadata, properties or tags can be mapped to labels.
+var MappedResources = []string{
+	"nova.server",
+	"cinder.volume",
+	"neutron.network",
+	"neutron.port",
+	"neutron.router",
+	"glance.image",
+	"octavia.loadbalancer",
+	"manila.share",
+	"keystone.project",
+}
+
 // Prometheus label names must:
 // - Not start from number
 // - Not use `__` prefix
@@ -101,3 +116,132 @@ func LabelMapping(s kingpin.Settings) *LabelMappingFlag {
 	s.SetValue(ret)
 	return ret
 }
+
+// ResourceLabelMappingFlag parse the metadata, properties or tags to label kingpin option of several resource types
+//
+// Supported format: `resource:mappings`, where *resource* is one of MappedResources and *mappings*
+// follow the LabelMappingFlag format, i.e: `cinder.volume:cost_center=cost-center,owner`.
+//
+// The tags of a resource are mapped as metadata: the `key=value` tag maps *key* to *value*,
+// other tags map themselves to `true`.
+type ResourceLabelMappingFlag struct {
+	Mappings map[string]*LabelMappingFlag
+}
+
+func (s *ResourceLabelMappingFlag) Set(value string) error {
+	if s.Mappings == nil {
+		s.Mappings = make(map[string]*LabelMappingFlag)
+	}
+
+	resource, mappings, _ := strings.Cut(value, ":")
+	if !slices.Contains(MappedResources, resource) {
+		return fmt.Errorf("%w: %s", ErrResource, resource)
+	}
+
+	mapping, ok := s.Mappings[resource]
+	if !ok {
+		mapping = new(LabelMappingFlag)
+	}
+	if err := mapping.Set(mappings); err != nil {
+		return fmt.Errorf("%s: %w", resource, err)
+	}
+	s.Mappings[resource] = mapping
+
+	return nil
+}
+
+func (s *ResourceLabelMappingFlag) String() string {
+	buf := make([]string, 0, len(s.Mappings))
+	for _, resource := range MappedResources {
+		if mapping, ok := s.Mappings[resource]; ok {
+			buf = append(buf, resource+":"+mapping.String())
+		}
+	}
+
+	return strings.Join(buf, " ")
+}
+
+func (s *ResourceLabelMappingFlag) IsCumulative() bool {
+	return true
+}
+
+// Get returns the mapping of the resource, empty when the resource has none.
+func (s *ResourceLabelMappingFlag) Get(resource string) *LabelMappingFlag {
+	if s == nil || s.Mappings[resource] == nil {
+		return new(LabelMappingFlag)
+	}
+
+	return s.Mappings[resource]
+}
+
+// Clone returns a copy of the mappings which can be changed without changing s.
+func (s *ResourceLabelMappingFlag) Clone() *ResourceLabelMappingFlag {
+	ret := &ResourceLabelMappingFlag{Mappings: make(map[string]*LabelMappingFlag)}
+	if s == nil {
+		return ret
+	}
+	for resource, mapping := range s.Mappings {
+		ret.Mappings[resource] = &LabelMappingFlag{Labels: slices.Clone(mapping.Labels), Keys: slices.Clone(mapping.Keys)}
+	}
+
+	return ret
+}
+
+func ResourceLabelMapping(s kingpin.Settings) *ResourceLabelMappingFlag {
+	ret := new(ResourceLabelMappingFlag)
+	s.SetValue(ret)
+	return ret
+}
+
+// ExternalLabelsFlag parse the external labels kingpin option, added to the series sent by remote write
+//
+// Supported format: `name=value`, the value may contain commas. A name set again replaces its value.
+type ExternalLabelsFlag struct {
+	Labels map[string]string
+}
+
+func (s *ExternalLabelsFlag) Set(value string) error {
+	if s.Labels == nil {
+		s.Labels = make(map[string]string)
+	}
+
+	name, labelValue, ok := strings.Cut(value, "=")
+	if !ok || !labelNameConstraintRe.MatchString(name) {
+		return fmt.Errorf("%w: %s", ErrLabelName, name)
+	}
+	s.Labels[name] = labelValue
+	return nil
+}
+
+func (s *ExternalLabelsFlag) String() string {
+	buf := make([]string, 0, len(s.Labels))
+	for _, name := range slices.Sorted(maps.Keys(s.Labels)) {
+		buf = append(buf, name+"="+s.Labels[name])
+	}
+	return strings.Join(buf, ",")
+}
+
+func (s *ExternalLabelsFlag) IsCumulative() bool {
+	return true
+}
+
+func ExternalLabels(s kingpin.Settings) *ExternalLabelsFlag {
+	ret := new(ExternalLabelsFlag)
+	s.SetValue(ret)
+	return ret
+}
+
+// TagsMetadata returns the tags as metadata: the `key=value` tag maps *key* to *value*,
+// other tags map themselves to `true`.
+func TagsMetadata(tags []string) map[string]string {
+	ret := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		key, value, ok := strings.Cut(tag, "=")
+		if !ok {
+			value = "true"
+		}
+		ret[key] = value
+	}
+
+	return ret
+}
@@ -66,3 +66,53 @@ func TestLabelMappingFlag_Extract(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceLabelMappingFlag_Set(t *testing.T) {
+	assert := assertpkg.New(t)
+
+	flg := new(ResourceLabelMappingFlag)
+
+	err := flg.Set("cinder.volume:cost_center=cost-center,owner")
+	assert.NoError(err)
+	err = flg.Set("neutron.port:owner")
+	assert.NoError(err)
+	err = flg.Set("cinder.volume:team")
+	assert.NoError(err)
+	assert.Equal([]string{"cost_center", "owner", "team"}, flg.Get("cinder.volume").Labels)
+	assert.Equal([]string{"cost-center", "owner", "team"}, flg.Get("cinder.volume").Keys)
+	assert.Equal("cinder.volume:cost_center=cost-center,owner,team neutron.port:owner", flg.String())
+	assert.Empty(flg.Get("glance.image").Labels)
+
+	err = flg.Set("cinder.snapshot:owner")
+	assert.ErrorIs(err, ErrResource)
+	assert.EqualError(err, "unknown resource: cinder.snapshot")
+
+	err = flg.Set("neutron.port:owner")
+	assert.ErrorIs(err, ErrLabelDup)
+	assert.EqualError(err, "neutron.port: duplicate label: owner")
+
+	clone := flg.Clone()
+	assert.NoError(clone.Set("cinder.volume:tier"))
+	assert.Len(flg.Get("cinder.volume").Labels, 3, "changing the clone should not change the mappings")
+
+	var noFlag *ResourceLabelMappingFlag
+	assert.Empty(noFlag.Get("nova.server").Labels)
+}
+
+func TestExternalLabelsFlag_Set(t *testing.T) {
+	flg := new(ExternalLabelsFlag)
+	require.NoError(t, flg.Set("cluster=edge,east"))
+	require.NoError(t, flg.Set("env=prod"))
+	require.NoError(t, flg.Set("env=staging"))
+	assertpkg.Equal(t, map[string]string{"cluster": "edge,east", "env": "staging"}, flg.Labels)
+	assertpkg.Equal(t, "cluster=edge,east,env=staging", flg.String())
+
+	for _, badLabel := range []string{"env", "__env=prod", "1env=prod"} {
+		assertpkg.ErrorIs(t, flg.Set(badLabel), ErrLabelName)
+	}
+}
+
+func TestTagsMetadata(t *testing.T) {
+	assertpkg.Equal(t, map[string]string{"cost-center": "42", "managed": "true", "owner": "a=b"},
+		TagsMetadata([]string{"cost-center=42", "managed", "owner=a=b"}))
+}
@@ -0,0 +1,190 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// Actions of the label rules.
+const (
+	LabelDrop = "labeldrop"
+	LabelKeep = "labelkeep"
+)
+
+// MetricFilter selects the exported metrics and their labels by regular expressions.
+//
+// A metric is exported when its fully qualified name (i.e: openstack_neutron_port) matches
+// one of the Allow patterns, or when there is none, and matches none of the Deny patterns.
+// The label rules are then applied in order to the labels of the metric.
+//
+// The metrics having more series than their series limit are not exported at all. The default
+// series limit only applies to the metrics having a series per resource, the counts and the
+// quotas of a project have as many series as the resources have states or types.
+type MetricFilter struct {
+	Allow      []*regexp.Regexp
+	Deny       []*regexp.Regexp
+	LabelRules []LabelRule
+	// SeriesLimit is the default series limit of the metrics having a series per resource, 0 means no limit.
+	SeriesLimit int
+	// SeriesLimits set the series limit of the metrics they match, the first match wins.
+	SeriesLimits []SeriesLimit
+}
+
+// SeriesLimit is the series limit of the metrics matching Metrics, 0 means no limit.
+type SeriesLimit struct {
+	Metrics *regexp.Regexp
+	Limit   int
+}
+
+// LabelRule drops the labels matching Regex, or keeps only them, from the metrics matching Metrics.
+type LabelRule struct {
+	// Metrics selects the metrics the rule applies to, nil selects every metric.
+	Metrics *regexp.Regexp
+	Action  string
+	Regex   *regexp.Regexp
+}
+
+// CompilePattern compiles a metric or label name pattern. Like Prometheus relabeling,
+// the pattern is anchored and has to match the whole name.
+func CompilePattern(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return re, nil
+}
+
+// CompilePatterns compiles a list of metric or label name patterns.
+func CompilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	var res []*regexp.Regexp
+	for _, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+		re, err := CompilePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// NewLabelRule compiles a label rule. An empty metrics pattern selects every metric.
+func NewLabelRule(metrics, action, regex string) (LabelRule, error) {
+	rule := LabelRule{Action: action}
+	if action != LabelDrop && action != LabelKeep {
+		return rule, fmt.Errorf("invalid label rule action %q, must be one of %s or %s", action, LabelDrop, LabelKeep)
+	}
+
+	var err error
+	if metrics != "" {
+		if rule.Metrics, err = CompilePattern(metrics); err != nil {
+			return rule, err
+		}
+	}
+	if rule.Regex, err = CompilePattern(regex); err != nil {
+		return rule, err
+	}
+	return rule, nil
+}
+
+// IsEmpty returns true when the filter exports every metric with all its labels and series.
+// A nil filter is empty.
+func (f *MetricFilter) IsEmpty() bool {
+	return f == nil || len(f.Allow) == 0 && len(f.Deny) == 0 && len(f.LabelRules) == 0 &&
+		f.SeriesLimit == 0 && len(f.SeriesLimits) == 0
+}
+
+// MetricAllowed returns true if the metric is exported.
+func (f *MetricFilter) MetricAllowed(name string) bool {
+	if f == nil {
+		return true
+	}
+
+	matches := func(re *regexp.Regexp) bool { return re.MatchString(name) }
+	if len(f.Allow) > 0 && !slices.ContainsFunc(f.Allow, matches) {
+		return false
+	}
+	return !slices.ContainsFunc(f.Deny, matches)
+}
+
+// KeptLabels returns the indexes of the labels of the metric kept by the label rules,
+// or nil when all the labels are kept.
+func (f *MetricFilter) KeptLabels(name string, labels []string) []int {
+	if f == nil {
+		return nil
+	}
+
+	kept := make([]bool, len(labels))
+	for i := range kept {
+		kept[i] = true
+	}
+	for _, rule := range f.LabelRules {
+		if rule.Metrics != nil && !rule.Metrics.MatchString(name) {
+			continue
+		}
+		for i, label := range labels {
+			if rule.Regex.MatchString(label) == (rule.Action == LabelDrop) {
+				kept[i] = false
+			}
+		}
+	}
+
+	if !slices.Contains(kept, false) {
+		return nil
+	}
+	indexes := []int{}
+	for i, keep := range kept {
+		if keep {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// MetricSeriesLimit returns the series limit of the metric, 0 means no limit. The default series
+// limit only applies when the metric has a series per resource.
+func (f *MetricFilter) MetricSeriesLimit(name string, perResource bool) int {
+	if f == nil {
+		return 0
+	}
+	for _, limit := range f.SeriesLimits {
+		if limit.Metrics.MatchString(name) {
+			return limit.Limit
+		}
+	}
+	if !perResource {
+		return 0
+	}
+	return f.SeriesLimit
+}
+
+// Equal returns true when the filters export the same metrics, labels and series. The patterns are
+// compared as strings.
+func (f *MetricFilter) Equal(other *MetricFilter) bool {
+	if f.IsEmpty() || other.IsEmpty() {
+		return f.IsEmpty() == other.IsEmpty()
+	}
+
+	sameRule := func(a, b LabelRule) bool {
+		return a.Action == b.Action && samePattern(a.Metrics, b.Metrics) && samePattern(a.Regex, b.Regex)
+	}
+	sameLimit := func(a, b SeriesLimit) bool {
+		return a.Limit == b.Limit && samePattern(a.Metrics, b.Metrics)
+	}
+	return slices.EqualFunc(f.Allow, other.Allow, samePattern) &&
+		slices.EqualFunc(f.Deny, other.Deny, samePattern) &&
+		slices.EqualFunc(f.LabelRules, other.LabelRules, sameRule) &&
+		f.SeriesLimit == other.SeriesLimit &&
+		slices.EqualFunc(f.SeriesLimits, other.SeriesLimits, sameLimit)
+}
+
+// samePattern returns true when both patterns are nil or have the same source.
+func samePattern(a, b *regexp.Regexp) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.String() == b.String()
+}
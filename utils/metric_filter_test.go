@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"regexp"
+	"testing"
+
+	assertpkg "github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricFilter_MetricAllowed(t *testing.T) {
+	assert := assertpkg.New(t)
+
+	allow, err := CompilePatterns([]string{"openstack_neutron_.*"})
+	require.NoError(t, err)
+	deny, err := CompilePatterns([]string{"openstack_neutron_port", ""})
+	require.NoError(t, err)
+	filter := &MetricFilter{Allow: allow, Deny: deny}
+
+	assert.True(filter.MetricAllowed("openstack_neutron_ports"))
+	assert.False(filter.MetricAllowed("openstack_neutron_port"), "deny should win over allow")
+	assert.False(filter.MetricAllowed("openstack_nova_server_status"))
+	assert.False(filter.MetricAllowed("xopenstack_neutron_ports"), "patterns should be anchored")
+
+	var noFilter *MetricFilter
+	assert.True(noFilter.MetricAllowed("openstack_nova_server_status"))
+	assert.True(noFilter.IsEmpty())
+	assert.True((&MetricFilter{}).IsEmpty())
+
+	_, err = CompilePatterns([]string{"openstack_("})
+	assert.ErrorContains(err, `invalid pattern "openstack_("`)
+}
+
+func TestMetricFilter_KeptLabels(t *testing.T) {
+	assert := assertpkg.New(t)
+
+	drop, err := NewLabelRule("openstack_nova_server_status", LabelDrop, "address_ipv6|uuid")
+	require.NoError(t, err)
+	keep, err := NewLabelRule("", LabelKeep, "id|name|status")
+	require.NoError(t, err)
+	filter := &MetricFilter{LabelRules: []LabelRule{drop}}
+
+	labels := []string{"id", "name", "status", "address_ipv6", "uuid"}
+	assert.Equal([]int{0, 1, 2}, filter.KeptLabels("openstack_nova_server_status", labels))
+	assert.Nil(filter.KeptLabels("openstack_nova_flavor", labels), "the rule should only apply to the selected metrics")
+
+	filter.LabelRules = append(filter.LabelRules, keep)
+	assert.Equal([]int{0, 1, 2}, filter.KeptLabels("openstack_nova_flavor", labels))
+	assert.Equal([]int{}, filter.KeptLabels("openstack_nova_flavor", []string{"vcpus"}))
+
+	_, err = NewLabelRule("", "replace", "id")
+	assert.ErrorContains(err, `invalid label rule action "replace"`)
+}
+
+func TestMetricFilter_MetricSeriesLimit(t *testing.T) {
+	assert := assertpkg.New(t)
+
+	ports, err := CompilePattern("openstack_neutron_port")
+	require.NoError(t, err)
+	all, err := CompilePattern("openstack_neutron_.*")
+	require.NoError(t, err)
+	filter := &MetricFilter{SeriesLimit: 1000, SeriesLimits: []SeriesLimit{{Metrics: ports, Limit: 50000}, {Metrics: all, Limit: 0}}}
+
+	assert.Equal(50000, filter.MetricSeriesLimit("openstack_neutron_port", true))
+	assert.Equal(50000, filter.MetricSeriesLimit("openstack_neutron_port", false), "the series limits should apply to every metric they match")
+	assert.Equal(0, filter.MetricSeriesLimit("openstack_neutron_network", true))
+	assert.Equal(1000, filter.MetricSeriesLimit("openstack_nova_server_status", true))
+	assert.Equal(0, filter.MetricSeriesLimit("openstack_nova_limits_vcpus_used", false), "the default series limit should only apply to the metrics per resource")
+	assert.False(filter.IsEmpty())
+
+	var noFilter *MetricFilter
+	assert.Equal(0, noFilter.MetricSeriesLimit("openstack_neutron_port", true))
+}
+
+func TestMetricFilter_Equal(t *testing.T) {
+	assert := assertpkg.New(t)
+
+	newFilter := func(limit int) *MetricFilter {
+		ports, err := CompilePattern("openstack_neutron_port")
+		require.NoError(t, err)
+		rule, err := NewLabelRule("", LabelDrop, "id")
+		require.NoError(t, err)
+		return &MetricFilter{Deny: []*regexp.Regexp{ports}, LabelRules: []LabelRule{rule}, SeriesLimits: []SeriesLimit{{Metrics: ports, Limit: limit}}}
+	}
+
+	assert.True(newFilter(10).Equal(newFilter(10)), "the filters compiled from the same patterns should be equal")
+	assert.False(newFilter(10).Equal(newFilter(20)))
+	assert.False(newFilter(10).Equal(nil))
+
+	var noFilter *MetricFilter
+	assert.True(noFilter.Equal(&MetricFilter{}))
+}